@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go-lab/pkg/disasm"
+	"go-lab/pkg/runner"
+)
+
+func init() {
+	c := &command{
+		name:    "calls",
+		usage:   "[flags] [topic ...]",
+		summary: "Disassemble experiment test binaries and list the runtime calls of each function.",
+	}
+	c.run = func(ctx context.Context, args []string) error {
+		fs := newFlagSet(c)
+		all := fs.Bool("all", false, "list every call, not only runtime functions")
+		tests := fs.Bool("tests", false, "include functions declared in _test.go files")
+		if err := parseFlags(fs, args); err != nil {
+			return err
+		}
+		w, err := runner.Open(ctx, ".")
+		if err != nil {
+			return err
+		}
+		mods, err := w.Select(fs.Args())
+		if err != nil {
+			return err
+		}
+		for _, m := range mods {
+			r, err := disasm.Disassemble(ctx, m.Dir)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("%s (%s)\n", m.Topic, r.Pkg)
+			for _, f := range r.Funcs {
+				if !*tests && strings.HasSuffix(f.File, "_test.go") {
+					continue
+				}
+				callees := f.Runtime()
+				if *all {
+					callees = nil
+					for _, c := range f.Calls {
+						callees = append(callees, c.Target)
+					}
+				}
+				fmt.Printf("  %s: %s\n", f.Name, strings.Join(callees, ", "))
+			}
+		}
+		return nil
+	}
+	commands = append(commands, c)
+}
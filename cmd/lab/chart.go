@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go-lab/pkg/benchfmt"
+	"go-lab/pkg/chart"
+)
+
+// chartOptions selects the charts writeCharts renders.
+type chartOptions struct {
+	units      []string
+	axis       string   // numeric axis of the line charts
+	series     []string // words splitting groups into bar series; no bar charts when empty
+	logX, logY bool
+}
+
+// addChartFlags defines the chart flags on fs.
+func addChartFlags(fs *flag.FlagSet) func() chartOptions {
+	units := fs.String("units", "ns/op,B/op,allocs/op", "comma-separated `units` to chart")
+	axis := fs.String("axis", "N", "numeric benchmark name `key` of the line charts")
+	series := fs.String("series", "", "comma-separated `words` splitting benchmarks into bar series, e.g. Value,Pointer")
+	logX := fs.Bool("logx", false, "logarithmic x axis")
+	logY := fs.Bool("logy", false, "logarithmic y axis")
+	return func() chartOptions {
+		o := chartOptions{units: strings.Split(*units, ","), axis: *axis, logX: *logX, logY: *logY}
+		if *series != "" {
+			o.series = strings.Split(*series, ",")
+		}
+		return o
+	}
+}
+
+// writeCharts writes the charts of topic into dir, a line chart per unit
+// for the sweeps over the axis and, with series words, a grouped bar chart
+// per unit, and returns the file names written.
+func writeCharts(dir, topic string, results []benchfmt.Result, o chartOptions) ([]string, error) {
+	var files []string
+	write := func(name string, svg []byte) error {
+		if err := os.WriteFile(filepath.Join(dir, name), svg, 0o644); err != nil {
+			return fmt.Errorf("write chart: %w", err)
+		}
+		files = append(files, name)
+		return nil
+	}
+	unitName := strings.NewReplacer("/", "-", " ", "-")
+	for _, unit := range o.units {
+		if l := chart.Sweep(results, unit, o.axis); l != nil {
+			l.Title = topic + " " + unit
+			l.LogX, l.LogY = o.logX, o.logY
+			if err := write(topic+"-"+unitName.Replace(unit)+".svg", l.SVG()); err != nil {
+				return nil, err
+			}
+		}
+		if len(o.series) == 0 {
+			continue
+		}
+		if b := chart.Compare(results, unit, o.series); b != nil {
+			b.Title = topic + " " + unit + ": " + strings.Join(o.series, " vs ")
+			b.LogY = o.logY
+			if err := write(topic+"-"+unitName.Replace(unit)+"-bars.svg", b.SVG()); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return files, nil
+}
+
+// chartLinks returns the Markdown image links embedding files.
+func chartLinks(files []string) string {
+	var sb strings.Builder
+	for _, f := range files {
+		fmt.Fprintf(&sb, "![%s](%s)\n", strings.TrimSuffix(f, ".svg"), f)
+	}
+	return sb.String()
+}
+
+func init() {
+	c := &command{
+		name:    "chart",
+		usage:   "[flags] <topic>",
+		summary: "Render SVG charts of a stored run of one experiment.",
+	}
+	c.run = func(ctx context.Context, args []string) error {
+		fs := newFlagSet(c)
+		runDir := fs.String("run", "", "stored run `dir` (default: latest run)")
+		out := fs.String("o", "", "output `dir` (default: the run directory, next to <topic>.txt)")
+		opts := addChartFlags(fs)
+		if err := parseFlags(fs, args); err != nil {
+			return err
+		}
+		if fs.NArg() != 1 {
+			fs.Usage()
+			return errUsage
+		}
+		run, err := loadRun(ctx, *runDir)
+		if err != nil {
+			return err
+		}
+		topic := fs.Arg(0)
+		results, err := runBenchmarks(run, []string{topic})
+		if err != nil {
+			return err
+		}
+		dir := *out
+		if dir == "" {
+			dir = run.Dir
+		}
+		files, err := writeCharts(dir, topic, results, opts())
+		if err != nil {
+			return err
+		}
+		if len(files) == 0 {
+			return errors.New("no " + opts().axis + "= sweeps or series matches in run " + run.ID + "; use -axis or -series")
+		}
+		fmt.Fprintf(os.Stderr, "wrote %d charts to %s\n", len(files), dir)
+		fmt.Print(chartLinks(files))
+		return nil
+	}
+	commands = append(commands, c)
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"go-lab/pkg/benchfmt"
+	"go-lab/pkg/stats"
+)
+
+func init() {
+	c := &command{
+		name:    "compare",
+		usage:   "[flags] <benchmark A> <benchmark B>",
+		summary: "Compare two benchmarks of a stored run (medians, CI, Mann-Whitney U, geomean).",
+	}
+	c.run = func(ctx context.Context, args []string) error {
+		fs := newFlagSet(c)
+		runDir := fs.String("run", "", "stored run `dir` (default: latest run)")
+		unit := fs.String("unit", "ns/op", "compare values reported in `unit`")
+		alpha := fs.Float64("alpha", stats.DefaultAlpha, "significance level")
+		if err := parseFlags(fs, args); err != nil {
+			return err
+		}
+		if fs.NArg() != 2 {
+			fs.Usage()
+			return errUsage
+		}
+		run, err := loadRun(ctx, *runDir)
+		if err != nil {
+			return err
+		}
+		results, err := runBenchmarks(run, nil)
+		if err != nil {
+			return err
+		}
+		a, b := fs.Arg(0), fs.Arg(1)
+		rows := compareRows(results, *unit, a, b, *alpha)
+		if len(rows) == 0 {
+			return errors.New("no samples of " + *unit + " shared by " + a + " and " + b)
+		}
+		fmt.Fprint(os.Stdout, stats.Table(a, b, *unit, rows))
+		return nil
+	}
+	commands = append(commands, c)
+}
+
+// compareRows compares benchmarks a and b for every axis combination they share.
+func compareRows(results []benchfmt.Result, unit, a, b string, alpha float64) []stats.Row {
+	var rows []stats.Row
+	for _, p := range benchfmt.Pairs(results, unit, a, b) {
+		rows = append(rows, stats.Row{Key: p.Key, Comparison: stats.Compare(p.A, p.B, alpha)})
+	}
+	return rows
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go-lab/pkg/escape"
+	"go-lab/pkg/runner"
+)
+
+func init() {
+	c := &command{
+		name:    "escape",
+		usage:   "[flags] [topic ...]",
+		summary: "Re-derive escape and inlining decisions and diff them against each experiment's " + escape.GoldenFile + ".",
+	}
+	c.run = func(ctx context.Context, args []string) error {
+		flags := newFlagSet(c)
+		update := flags.Bool("update", false, "rewrite "+escape.GoldenFile+" with the current decisions")
+		if err := parseFlags(flags, args); err != nil {
+			return err
+		}
+		w, err := runner.Open(ctx, ".")
+		if err != nil {
+			return err
+		}
+		mods, err := w.Select(flags.Args())
+		if err != nil {
+			return err
+		}
+
+		stale := 0
+		for _, m := range mods {
+			if !hasSource(m.Dir) {
+				fmt.Printf("%s: no non-test Go files; skipped\n", m.Topic)
+				continue
+			}
+			r, err := escape.Compile(ctx, m.Dir)
+			if err != nil {
+				return err
+			}
+			cur := r.Snapshot()
+			if *update {
+				if err := escape.WriteGolden(m.Dir, cur); err != nil {
+					return err
+				}
+				fmt.Printf("%s: wrote %d decisions (%s)\n", m.Topic, len(cur.Facts), cur.GoVersion)
+				continue
+			}
+			golden, err := escape.ReadGolden(m.Dir)
+			if errors.Is(err, fs.ErrNotExist) {
+				fmt.Printf("%s: no %s; run with -update\n", m.Topic, escape.GoldenFile)
+				stale++
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			changes := escape.Diff(golden, cur)
+			if len(changes) == 0 {
+				fmt.Printf("%s: ok (%s)\n", m.Topic, cur.GoVersion)
+				continue
+			}
+			stale++
+			fmt.Printf("%s: %d functions changed (%s → %s)\n", m.Topic, len(changes), golden.GoVersion, cur.GoVersion)
+			escape.FormatChanges(os.Stdout, changes)
+		}
+		if stale > 0 {
+			return fmt.Errorf("%d experiments differ from %s; review the findings and rerun with -update", stale, escape.GoldenFile)
+		}
+		return nil
+	}
+	commands = append(commands, c)
+}
+
+// hasSource reports whether dir has a non-test Go file to compile.
+func hasSource(dir string) bool {
+	files, _ := filepath.Glob(filepath.Join(dir, "*.go"))
+	for _, f := range files {
+		if !strings.HasSuffix(f, "_test.go") {
+			return true
+		}
+	}
+	return false
+}
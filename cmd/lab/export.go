@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"go-lab/pkg/benchfmt"
+	"go-lab/pkg/runner"
+)
+
+func init() {
+	c := &command{
+		name:    "export",
+		usage:   "[flags] [topic ...]",
+		summary: "Export benchmark results of a stored run (or of raw go test output) as JSON or CSV.",
+	}
+	c.run = func(ctx context.Context, args []string) error {
+		fs := newFlagSet(c)
+		format := fs.String("format", "json", "output `format`: json or csv")
+		runDir := fs.String("run", "", "stored run `dir` (default: latest run)")
+		in := fs.String("in", "", "parse raw go test output from `file` instead (- for stdin)")
+		if err := parseFlags(fs, args); err != nil {
+			return err
+		}
+		var write func(io.Writer, []benchfmt.Result) error
+		switch *format {
+		case "json":
+			write = benchfmt.WriteJSON
+		case "csv":
+			write = benchfmt.WriteCSV
+		default:
+			return fmt.Errorf("unknown format %q", *format)
+		}
+
+		var results []benchfmt.Result
+		if *in != "" {
+			r, err := openInput(*in)
+			if err != nil {
+				return err
+			}
+			defer r.Close()
+			results, err = benchfmt.Parse(r)
+			if err != nil {
+				return err
+			}
+		} else {
+			run, err := loadRun(ctx, *runDir)
+			if err != nil {
+				return err
+			}
+			results, err = runBenchmarks(run, fs.Args())
+			if err != nil {
+				return err
+			}
+		}
+		return write(os.Stdout, results)
+	}
+	commands = append(commands, c)
+}
+
+func openInput(name string) (io.ReadCloser, error) {
+	if name == "-" {
+		return io.NopCloser(os.Stdin), nil
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("open input: %w", err)
+	}
+	return f, nil
+}
+
+// loadRun loads the run stored in dir, or the latest run of the workspace
+// when dir is empty.
+func loadRun(ctx context.Context, dir string) (*runner.Run, error) {
+	if dir == "" {
+		w, err := runner.Open(ctx, ".")
+		if err != nil {
+			return nil, err
+		}
+		dir, err = runner.LatestRun(filepath.Join(w.Root, runner.RunsDir))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return runner.LoadRun(dir)
+}
+
+// runBenchmarks collects the benchmark results of the named topics of run,
+// or of every topic when none is named.
+func runBenchmarks(run *runner.Run, topics []string) ([]benchfmt.Result, error) {
+	if len(topics) == 0 {
+		for _, res := range run.Results {
+			topics = append(topics, res.Topic)
+		}
+	}
+	var all []benchfmt.Result
+	for _, t := range topics {
+		res, ok := run.Result(t)
+		if !ok {
+			return nil, fmt.Errorf("run %s has no results for %q", run.ID, t)
+		}
+		all = append(all, res.Benchmarks...)
+	}
+	return all, nil
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"go-lab/pkg/fieldorder"
+)
+
+func init() {
+	c := &command{
+		name:    "fieldorder",
+		usage:   "[flags] [dir | dir/... ...]",
+		summary: "Report structs whose field order wastes memory, with sizes per GOARCH.",
+	}
+	c.run = func(ctx context.Context, args []string) error {
+		flags := newFlagSet(c)
+		all := flags.Bool("all", false, "also report structs already in optimal order")
+		arches := flags.String("arch", strings.Join(fieldorder.Arches, ","), "comma-separated GOARCH `list`")
+		if err := parseFlags(flags, args); err != nil {
+			return err
+		}
+		patterns := flags.Args()
+		if len(patterns) == 0 {
+			patterns = []string{"."}
+		}
+		dirs, err := expandDirs(patterns)
+		if err != nil {
+			return err
+		}
+		archList := strings.Split(*arches, ",")
+		for _, dir := range dirs {
+			structs, err := fieldorder.AnalyzeDir(dir, archList...)
+			if err != nil {
+				return err
+			}
+			for _, s := range structs {
+				if !*all && !s.Reorderable() {
+					continue
+				}
+				fmt.Printf("%s:%d: %s {%s}\n\n", relPath(s.Pos.Filename), s.Pos.Line, s.Name, strings.Join(s.Fields, ", "))
+				fmt.Print(s.Table())
+				fmt.Printf("\nOptimal order (%s): %s\n\n", s.Sizes[0].Arch, strings.Join(s.Sizes[0].Order, ", "))
+			}
+		}
+		return nil
+	}
+	commands = append(commands, c)
+}
+
+// expandDirs resolves "dir/..." patterns to every directory below dir that
+// holds non-test Go files, skipping testdata and hidden directories.
+func expandDirs(patterns []string) ([]string, error) {
+	var dirs []string
+	for _, p := range patterns {
+		root, ok := strings.CutSuffix(p, "/...")
+		if !ok {
+			dirs = append(dirs, p)
+			continue
+		}
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() {
+				return nil
+			}
+			if name := d.Name(); path != root && (name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+				return filepath.SkipDir
+			}
+			if hasSource(path) {
+				dirs = append(dirs, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("expand %s: %w", p, err)
+		}
+	}
+	return dirs, nil
+}
+
+func relPath(path string) string {
+	if rel, err := filepath.Rel(".", path); err == nil {
+		return rel
+	}
+	return path
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"go-lab/pkg/complexity"
+)
+
+func init() {
+	c := &command{
+		name:    "fit",
+		usage:   "[flags] [topic ...]",
+		summary: "Fit the N= sweeps of a stored run with O(1), O(log N), O(N), O(N log N) and O(N²).",
+	}
+	c.run = func(ctx context.Context, args []string) error {
+		fs := newFlagSet(c)
+		runDir := fs.String("run", "", "stored run `dir` (default: latest run)")
+		units := fs.String("units", "ns/op,B/op,allocs/op", "comma-separated `units` to fit")
+		verbose := fs.Bool("v", false, "print the fit of every model")
+		if err := parseFlags(fs, args); err != nil {
+			return err
+		}
+		run, err := loadRun(ctx, *runDir)
+		if err != nil {
+			return err
+		}
+		results, err := runBenchmarks(run, fs.Args())
+		if err != nil {
+			return err
+		}
+		rows := 0
+		var details strings.Builder
+		fmt.Println("| Benchmark | Unit | Best | R² | Runner-up | R² |")
+		fmt.Println("| :--- | :--- | :--- | ---: | :--- | ---: |")
+		for unit := range strings.SplitSeq(*units, ",") {
+			groups, sweeps := complexity.FromResults(results, unit)
+			for _, g := range groups {
+				fits, err := complexity.FitAll(sweeps[g])
+				if err != nil {
+					continue // too few sizes
+				}
+				fmt.Printf("| %s | %s | %v | %.4f | %v | %.4f |\n",
+					g, unit, fits[0].Model, fits[0].R2, fits[1].Model, fits[1].R2)
+				if *verbose {
+					fmt.Fprintf(&details, "\n%s %s:\n%s", g, unit, complexity.Table(fits))
+				}
+				rows++
+			}
+		}
+		if rows == 0 {
+			return errors.New("no benchmark sweeps over N with 3 or more sizes in run " + run.ID)
+		}
+		fmt.Fprint(os.Stdout, details.String())
+		return nil
+	}
+	commands = append(commands, c)
+}
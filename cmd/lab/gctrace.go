@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go-lab/pkg/gctrace"
+	"go-lab/pkg/runner"
+)
+
+func init() {
+	c := &command{
+		name:    "gctrace",
+		usage:   "[flags] <topic>",
+		summary: "Run benchmarks under GODEBUG=gctrace=1 and summarize the GC work of each.",
+	}
+	c.run = func(ctx context.Context, args []string) error {
+		fs := newFlagSet(c)
+		var opts gctrace.Options
+		fs.StringVar(&opts.Bench, "bench", "", "trace only top-level benchmarks matching `regexp`")
+		fs.IntVar(&opts.Count, "count", 1, "run each benchmark `n` times")
+		fs.StringVar(&opts.Benchtime, "benchtime", "", "benchmark duration or iteration count (Nx keeps b.N ramp-up out of the trace)")
+		cycles := fs.Bool("v", false, "also print every GC cycle")
+		if err := parseFlags(fs, args); err != nil {
+			return err
+		}
+		if fs.NArg() != 1 {
+			fs.Usage()
+			return errUsage
+		}
+		w, err := runner.Open(ctx, ".")
+		if err != nil {
+			return err
+		}
+		mods, err := w.Select(fs.Args())
+		if err != nil {
+			return err
+		}
+		benchs, err := gctrace.Run(ctx, mods[0].Dir, opts)
+		if err != nil {
+			return err
+		}
+		var sums []gctrace.Summary
+		for _, b := range benchs {
+			sums = append(sums, gctrace.Summarize(b))
+			if *cycles {
+				fmt.Fprintf(os.Stderr, "%s\n", b.Name)
+				for _, c := range b.Cycles {
+					fmt.Fprintf(os.Stderr, "  gc %d @%v: STW %v, mark %v, heap %d->%d->%d MiB, goal %d MiB\n",
+						c.Num, c.At, c.STW(), c.Mark, c.HeapStart>>20, c.HeapEnd>>20, c.HeapLive>>20, c.HeapGoal>>20)
+				}
+			}
+		}
+		fmt.Print(gctrace.Table(sums))
+		return nil
+	}
+	commands = append(commands, c)
+}
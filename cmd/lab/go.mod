@@ -0,0 +1,5 @@
+module go-lab/cmd/lab
+
+go 1.26.0
+
+require go-lab/pkg v0.0.0
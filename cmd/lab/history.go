@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"go-lab/pkg/history"
+	"go-lab/pkg/runner"
+	"go-lab/pkg/stats"
+)
+
+func init() {
+	c := &command{
+		name:    "history",
+		usage:   "[flags] <topic> [benchmark]",
+		summary: "Show how a benchmark evolved across runs and flag significant regressions.",
+	}
+	c.run = func(ctx context.Context, args []string) error {
+		fs := newFlagSet(c)
+		unit := fs.String("unit", "ns/op", "compare values reported in `unit`")
+		alpha := fs.Float64("alpha", stats.DefaultAlpha, "significance level")
+		runA := fs.String("a", "", "compare every benchmark of `run` A ...")
+		runB := fs.String("b", "", "... against `run` B (default: latest entry)")
+		imp := fs.Bool("import", false, "first append the stored runs missing from the history")
+		build := fs.String("build", "", "show the series of matrix cells built with `config` (e.g. \"-gcflags=-l\"; default: the default build)")
+		if err := parseFlags(fs, args); err != nil {
+			return err
+		}
+		if fs.NArg() < 1 || fs.NArg() > 2 || (*runB != "" && *runA == "") {
+			fs.Usage()
+			return errUsage
+		}
+		topic := fs.Arg(0)
+
+		w, err := runner.Open(ctx, ".")
+		if err != nil {
+			return err
+		}
+		dir := filepath.Join(w.Root, history.Dir)
+		if *imp {
+			n, err := importRuns(filepath.Join(w.Root, runner.RunsDir), dir)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "imported %d entries\n", n)
+		}
+		entries, err := history.Load(dir, topic)
+		if err != nil {
+			return err
+		}
+		if *runA == "" {
+			// Runs of different builds measure different binaries; -a/-b
+			// may compare them on purpose, a series must not.
+			entries = slices.DeleteFunc(entries, func(e history.Entry) bool { return e.Key.Build != *build })
+		}
+		if len(entries) == 0 {
+			return errors.New("no history for " + topic + " (run lab run, or lab history -import)")
+		}
+
+		switch {
+		case *runA != "":
+			a, ok := history.Find(entries, *runA)
+			if !ok {
+				return errors.New("run " + *runA + " not in the history of " + topic)
+			}
+			b := entries[len(entries)-1]
+			if *runB != "" {
+				if b, ok = history.Find(entries, *runB); !ok {
+					return errors.New("run " + *runB + " not in the history of " + topic)
+				}
+			}
+			changes := history.Compare(a, b, *unit, *alpha)
+			if len(changes) == 0 {
+				return errors.New("no " + *unit + " samples shared by runs " + a.Run + " and " + b.Run)
+			}
+			fmt.Fprint(os.Stdout, history.CompareTable(a, b, *unit, changes))
+		case fs.NArg() == 2:
+			points := history.Series(entries, fs.Arg(1), *unit, *alpha)
+			if len(points) == 0 {
+				return errors.New("no " + *unit + " samples of " + fs.Arg(1) + " in the history of " + topic)
+			}
+			fmt.Fprint(os.Stdout, history.SeriesTable(points, *unit))
+		default:
+			fmt.Println("| Run | Commit | Go | GOAMD64 | CPU | Model | Results |")
+			fmt.Println("| :--- | :--- | :--- | :--- | :--- | :--- | ---: |")
+			for _, e := range entries {
+				k := e.Key
+				amd64 := k.GOAMD64
+				if amd64 == "" {
+					amd64 = "-"
+				}
+				fmt.Printf("| %s | %s | %s | %s | %s | %s | %d |\n",
+					e.Run, k.ShortCommit(), k.GoVersion, amd64, k.CPU, e.CPUModel, len(e.Benchmarks))
+			}
+		}
+		return nil
+	}
+	commands = append(commands, c)
+}
+
+// importRuns appends the benchmarks of every run stored under runsDir to the
+// history in dir, oldest first, and returns the number of entries added.
+func importRuns(runsDir, dir string) (int, error) {
+	des, err := os.ReadDir(runsDir)
+	if err != nil {
+		return 0, fmt.Errorf("list runs: %w", err)
+	}
+	// Run IDs are timestamps; ReadDir sorts them chronologically.
+	des = slices.DeleteFunc(des, func(de os.DirEntry) bool { return !de.IsDir() })
+	added := 0
+	for _, de := range des {
+		run, err := runner.LoadRun(filepath.Join(runsDir, de.Name()))
+		if err != nil {
+			continue // not a finished run
+		}
+		for _, e := range history.FromRun(run) {
+			ok, err := history.Append(dir, e)
+			if err != nil {
+				return added, err
+			}
+			if ok {
+				added++
+			}
+		}
+	}
+	return added, nil
+}
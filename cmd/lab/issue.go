@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"go-lab/pkg/imrad"
+	"go-lab/pkg/report"
+	"go-lab/pkg/runner"
+)
+
+func init() {
+	c := &command{
+		name:    "issue",
+		usage:   "[flags] <topic>",
+		summary: "Render the IMRaD issue of an experiment from its " + imrad.SpecFile + ", with results from a stored run.",
+	}
+	c.run = func(ctx context.Context, args []string) error {
+		fs := newFlagSet(c)
+		results := fs.Bool("results", false, "fill in Results, Discussion and Next Actions from a stored run")
+		runDir := fs.String("run", "", "stored run `dir` for -results (default: latest run)")
+		a := fs.String("a", "", "Pattern A benchmark `name` (default: pattern_a of the spec)")
+		b := fs.String("b", "", "Pattern B benchmark `name` (default: pattern_b of the spec)")
+		title := fs.Bool("title", false, "print the issue title instead of the body")
+		out := fs.String("o", "", "write the issue to `file` instead of stdout")
+		if err := parseFlags(fs, args); err != nil {
+			return err
+		}
+		if fs.NArg() != 1 || (*a == "") != (*b == "") {
+			fs.Usage()
+			return errUsage
+		}
+		w, err := runner.Open(ctx, ".")
+		if err != nil {
+			return err
+		}
+		mods, err := w.Select(fs.Args())
+		if err != nil {
+			return err
+		}
+		spec, err := imrad.Load(filepath.Join(mods[0].Dir, imrad.SpecFile))
+		if err != nil {
+			return err
+		}
+		if *title {
+			fmt.Println(spec.IssueTitle())
+			return nil
+		}
+
+		var d *report.Data
+		if *results {
+			run, err := loadRun(ctx, *runDir)
+			if err != nil {
+				return err
+			}
+			if *a == "" {
+				*a, *b = spec.PatternA, spec.PatternB
+			}
+			data, err := report.Build(run, mods[0].Topic, *a, *b)
+			if err != nil {
+				return err
+			}
+			d = &data
+		}
+
+		var wr io.Writer = os.Stdout
+		if *out != "" {
+			f, err := os.Create(*out)
+			if err != nil {
+				return fmt.Errorf("create issue: %w", err)
+			}
+			defer f.Close()
+			wr = f
+		}
+		return imrad.Render(wr, spec, d)
+	}
+	commands = append(commands, c)
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"go-lab/pkg/runner"
+)
+
+func init() {
+	c := &command{
+		name:    "list",
+		usage:   "",
+		summary: "List the experiment modules of the workspace.",
+	}
+	c.run = func(ctx context.Context, args []string) error {
+		fs := newFlagSet(c)
+		if err := parseFlags(fs, args); err != nil {
+			return err
+		}
+		w, err := runner.Open(ctx, ".")
+		if err != nil {
+			return err
+		}
+		for _, m := range w.Experiments() {
+			fmt.Println(m.Topic)
+		}
+		return nil
+	}
+	commands = append(commands, c)
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go-lab/pkg/runner"
+	"go-lab/pkg/stats"
+)
+
+func init() {
+	c := &command{
+		name:    "matrix",
+		usage:   "[flags] [topic ...]",
+		summary: "Run experiments under several Go toolchains and build configurations and tabulate the results side by side.",
+	}
+	c.run = func(ctx context.Context, args []string) error {
+		fs := newFlagSet(c)
+		opts := optionFlags(fs)
+		gos := fs.String("go", "", "comma-separated toolchain `list`: SDK directories, go binaries or installed GOTOOLCHAIN values (e.g. go1.23.4)")
+		var builds []runner.Config
+		fs.Func("config", "add a build `configuration` compared with the default build: \";\"-separated go build flags and VAR=value settings, e.g. \"-gcflags=-N -l\" or \"-pgo=off;GOAMD64=v3\" (repeatable)", func(s string) error {
+			c, err := runner.ParseConfig(s)
+			builds = append(builds, c)
+			return err
+		})
+		show := fs.String("show", "", "print the tables of the stored matrix in `dir` instead of running (\"latest\" for the newest)")
+		units := fs.String("units", "ns/op,B/op,allocs/op", "comma-separated benchmark `units` to tabulate")
+		alpha := fs.Float64("alpha", stats.DefaultAlpha, "significance level")
+		out := fs.String("out", "", "store runs under `dir` (default <workspace>/"+runner.RunsDir+")")
+		if err := parseFlags(fs, args); err != nil {
+			return err
+		}
+		if (*gos == "" && len(builds) == 0) == (*show == "") {
+			fs.Usage()
+			return errUsage
+		}
+
+		w, err := runner.Open(ctx, ".")
+		if err != nil {
+			return err
+		}
+		runsDir := *out
+		if runsDir == "" {
+			runsDir = filepath.Join(w.Root, runner.RunsDir)
+		}
+		var m *runner.Matrix
+		if *show != "" {
+			dir := *show
+			if dir == "latest" {
+				if dir, err = runner.LatestMatrix(runsDir); err != nil {
+					return err
+				}
+			}
+			if m, err = runner.LoadMatrix(dir); err != nil {
+				return err
+			}
+		} else {
+			toolchains := []runner.Config{{}}
+			if *gos != "" {
+				if toolchains, err = toolchainConfigs(ctx, strings.Split(*gos, ",")); err != nil {
+					return err
+				}
+			}
+			configs := product(toolchains, builds)
+			mods, err := w.Select(fs.Args())
+			if err != nil {
+				return err
+			}
+			if m, err = w.ExecuteMatrix(ctx, configs, mods, *opts, runsDir, os.Stderr); err != nil {
+				return err
+			}
+			for _, run := range m.Runs {
+				if err := appendHistory(w, run); err != nil {
+					return err
+				}
+			}
+			fmt.Fprintln(os.Stderr, m.Dir)
+		}
+		printMatrix(m, strings.Split(*units, ","), *alpha)
+		return nil
+	}
+	commands = append(commands, c)
+}
+
+// toolchainConfigs resolves every spec to a matrix configuration named
+// after the toolchain version, or after the spec when versions repeat.
+func toolchainConfigs(ctx context.Context, specs []string) ([]runner.Config, error) {
+	var (
+		configs []runner.Config
+		count   = map[string]int{}
+	)
+	for _, spec := range specs {
+		tc, err := runner.ResolveToolchain(ctx, strings.TrimSpace(spec))
+		if err != nil {
+			return nil, err
+		}
+		count[tc.Version]++
+		configs = append(configs, runner.Config{Name: tc.Version, Toolchain: tc})
+	}
+	for i, c := range configs {
+		if count[c.Name] > 1 {
+			configs[i].Name = c.Toolchain.Spec
+		}
+	}
+	if len(configs) == 0 {
+		return nil, errors.New("no toolchains given")
+	}
+	return configs, nil
+}
+
+// product combines every toolchain with the default build and each of
+// builds. Names get a toolchain prefix only when there are several
+// toolchains, and a build suffix only when there are builds.
+func product(toolchains, builds []runner.Config) []runner.Config {
+	if len(builds) == 0 {
+		return toolchains
+	}
+	builds = append([]runner.Config{{Name: "default"}}, builds...)
+	var configs []runner.Config
+	for _, tc := range toolchains {
+		for _, b := range builds {
+			b.Toolchain = tc.Toolchain
+			if len(toolchains) > 1 {
+				b.Name = tc.Name + " " + b.Name
+			}
+			configs = append(configs, b)
+		}
+	}
+	return configs
+}
+
+func printMatrix(m *runner.Matrix, units []string, alpha float64) {
+	fmt.Printf("## Status\n\n%s\n", m.StatusTable())
+	for _, u := range units {
+		fmt.Printf("## %s\n\n%s\n", u, m.BenchTable(u, alpha))
+	}
+	if t := m.AllocTable(); strings.Count(t, "\n") > 2 {
+		fmt.Printf("## Allocation assertions\n\n%s", t)
+	}
+}
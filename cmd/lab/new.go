@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go-lab/pkg/runner"
+	"go-lab/pkg/scaffold"
+)
+
+func init() {
+	c := &command{
+		name:    "new",
+		usage:   "<issue-id> <topic>",
+		summary: "Create an experiment module, its go.work entry and its exp/<issue-id>-<topic> branch.",
+	}
+	c.run = func(ctx context.Context, args []string) error {
+		fs := newFlagSet(c)
+		if err := parseFlags(fs, args); err != nil {
+			return err
+		}
+		if fs.NArg() != 2 {
+			fs.Usage()
+			return errUsage
+		}
+		issue, err := strconv.Atoi(strings.TrimPrefix(fs.Arg(0), "#"))
+		if err != nil {
+			fs.Usage()
+			return errUsage
+		}
+		w, err := runner.Open(ctx, ".")
+		if err != nil {
+			return err
+		}
+		e, err := scaffold.New(issue, fs.Arg(1), w.GoVersion)
+		if err != nil {
+			return err
+		}
+		if err := e.Create(ctx, w.Root); err != nil {
+			return err
+		}
+		fmt.Printf("created %s (module %s, go %s) on branch %s\n", e.Dir(), e.Module(), e.GoVersion, e.Branch())
+		fmt.Printf("next: fill in %s/%s.go, then go run ./cmd/lab run %s\n", e.Dir(), e.Topic, e.Topic)
+		return nil
+	}
+	commands = append(commands, c)
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"go-lab/pkg/report"
+)
+
+func init() {
+	c := &command{
+		name:    "report",
+		usage:   "[flags] <topic>",
+		summary: "Fill in the pull request template from a stored run of one experiment.",
+	}
+	c.run = func(ctx context.Context, args []string) error {
+		fs := newFlagSet(c)
+		runDir := fs.String("run", "", "stored run `dir` (default: latest run)")
+		issue := fs.Int("issue", 0, "issue `number` closed by the pull request")
+		a := fs.String("a", "", "Pattern A benchmark `name` (default: inferred when the topic has two)")
+		b := fs.String("b", "", "Pattern B benchmark `name`")
+		out := fs.String("o", "", "write the report to `file` instead of stdout")
+		charts := fs.Bool("charts", false, "write SVG charts next to the -o file and embed them in the report")
+		chartOpts := addChartFlags(fs)
+		if err := parseFlags(fs, args); err != nil {
+			return err
+		}
+		if fs.NArg() != 1 || (*a == "") != (*b == "") || (*charts && *out == "") {
+			fs.Usage()
+			return errUsage
+		}
+		run, err := loadRun(ctx, *runDir)
+		if err != nil {
+			return err
+		}
+		d, err := report.Build(run, fs.Arg(0), *a, *b)
+		if err != nil {
+			return err
+		}
+		d.Issue = *issue
+		if *charts {
+			res, _ := run.Result(fs.Arg(0)) // Build checked it exists
+			d.Charts, err = writeCharts(filepath.Dir(*out), fs.Arg(0), res.Benchmarks, chartOpts())
+			if err != nil {
+				return err
+			}
+		}
+
+		var w io.Writer = os.Stdout
+		if *out != "" {
+			f, err := os.Create(*out)
+			if err != nil {
+				return fmt.Errorf("create report: %w", err)
+			}
+			defer f.Close()
+			w = f
+		}
+		return report.Render(w, d)
+	}
+	commands = append(commands, c)
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"go-lab/pkg/history"
+	"go-lab/pkg/measure"
+	"go-lab/pkg/runner"
+)
+
+func init() {
+	c := &command{
+		name:    "run",
+		usage:   "[flags] [topic ...]",
+		summary: "Run tests and benchmarks of the named experiments (all when none) and store the results and append benchmarks to the history.",
+	}
+	c.run = func(ctx context.Context, args []string) error {
+		fs := newFlagSet(c)
+		opts := optionFlags(fs)
+		out := fs.String("out", "", "store runs under `dir` (default <workspace>/"+runner.RunsDir+")")
+		if err := parseFlags(fs, args); err != nil {
+			return err
+		}
+
+		w, err := runner.Open(ctx, ".")
+		if err != nil {
+			return err
+		}
+		mods, err := w.Select(fs.Args())
+		if err != nil {
+			return err
+		}
+		runsDir := *out
+		if runsDir == "" {
+			runsDir = filepath.Join(w.Root, runner.RunsDir)
+		}
+		run, err := w.Execute(ctx, mods, *opts, runsDir, os.Stderr)
+		if err != nil {
+			return err
+		}
+		if err := appendHistory(w, run); err != nil {
+			return err
+		}
+		fmt.Fprint(os.Stderr, run.Noise)
+		fmt.Println(run.Dir)
+		if !run.Passed() {
+			return fmt.Errorf("some experiments failed; see %s", run.Dir)
+		}
+		return nil
+	}
+	commands = append(commands, c)
+}
+
+// optionFlags registers the go test flags of runner.Options on fs.
+func optionFlags(fs *flag.FlagSet) *runner.Options {
+	opts := runner.DefaultOptions()
+	fs.StringVar(&opts.Run, "run", opts.Run, "run only tests matching `regexp`")
+	fs.StringVar(&opts.Bench, "bench", opts.Bench, "run only benchmarks matching `regexp` (empty disables benchmarks)")
+	fs.IntVar(&opts.Count, "count", opts.Count, "run each test and benchmark `n` times")
+	fs.StringVar(&opts.CPU, "cpu", opts.CPU, "comma-separated GOMAXPROCS `list`")
+	fs.StringVar(&opts.Benchtime, "benchtime", opts.Benchtime, "benchmark duration or iteration count (e.g. 1s, 100x)")
+	fs.StringVar(&opts.Warmup, "warmup", opts.Warmup, "run the benchmarks for `benchtime` and discard the results before measuring")
+	fs.Func("pin", "pin the benchmarks to the CPUs in `list` (e.g. 2-3)", func(s string) error {
+		cpus, err := measure.ParseCPUList(s)
+		opts.Pin = cpus
+		return err
+	})
+	return &opts
+}
+
+// appendHistory appends the benchmarks of run to the workspace history.
+func appendHistory(w *runner.Workspace, run *runner.Run) error {
+	dir := filepath.Join(w.Root, history.Dir)
+	for _, e := range history.FromRun(run) {
+		if _, err := history.Append(dir, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -1,41 +1,42 @@
 package closurecapture
 
-import "testing"
+import (
+	"testing"
+
+	"go-lab/pkg/escape"
+	"go-lab/pkg/hypothesis"
+	"go-lab/pkg/measure"
+)
 
 // sink prevents the compiler from eliminating benchmark results via dead-code elimination.
-var sink int
+var sink measure.Sink[int]
 
-// TestAllocations measures heap allocations per call using testing.AllocsPerRun.
+// TestAllocations measures heap allocations per call using measure.AssertAllocs
+// and measure.ObserveAllocs.
 //
 // Assertion policy:
-//   - Hard assertion (t.Errorf): patterns where the compiler outcome is determined
-//     by the Go spec or well-established escape analysis rules.
-//   - Observation only (t.Logf): exploratory patterns whose allocation count is
-//     the primary research question. These must NOT be constrained by a prior hypothesis.
+//   - Hard assertion (measure.AssertAllocs): patterns where the compiler outcome
+//     is determined by the Go spec or well-established escape analysis rules.
+//   - Observation only (measure.ObserveAllocs): exploratory patterns whose
+//     allocation count is the primary research question. These must NOT be
+//     constrained by a prior hypothesis.
 func TestAllocations(t *testing.T) {
 
 	// ---- Section 0: Baseline ----
 
 	t.Run("Baseline_NoCapture", func(t *testing.T) {
-		got := testing.AllocsPerRun(100, func() { sink = NoCapture() })
-		if got != 0 {
-			t.Errorf("NoCapture: want 0 allocs, got %v", got)
-		}
+		measure.AssertAllocs(t, "NoCapture", 0, func() { sink.Set(NoCapture()) })
 	})
 
 	// ---- Section 1: 2×2 Factorial ----
 
 	t.Run("PatternA_NonEscapingReadOnly", func(t *testing.T) {
-		got := testing.AllocsPerRun(100, func() { sink = PatternA_NonEscapingReadOnly() })
-		if got != 0 {
-			t.Errorf("PatternA: want 0 allocs, got %v", got)
-		}
+		measure.AssertAllocs(t, "PatternA", 0, func() { sink.Set(PatternA_NonEscapingReadOnly()) })
 	})
 
 	// PatternB is the core research question — observe without asserting.
 	t.Run("PatternB_NonEscapingMutating", func(t *testing.T) {
-		got := testing.AllocsPerRun(100, func() { sink = PatternB_NonEscapingMutating() })
-		t.Logf("PatternB (main experiment — reference capture, non-escaping): allocs/run = %v", got)
+		measure.ObserveAllocs(t, "PatternB (main experiment — reference capture, non-escaping)", func() { sink.Set(PatternB_NonEscapingMutating()) })
 	})
 
 	// PatternC: the returned closure is immediately called in the test expression.
@@ -43,112 +44,127 @@ func TestAllocations(t *testing.T) {
 	// the heap allocation even though the function signature implies escape.
 	// Observation only — this is part of the research result.
 	t.Run("PatternC_EscapingReadOnly", func(t *testing.T) {
-		got := testing.AllocsPerRun(100, func() { sink = PatternC_EscapingReadOnly()() })
-		t.Logf("PatternC (escaping read-only, inlined call site): allocs/run = %v", got)
+		measure.ObserveAllocs(t, "PatternC (escaping read-only, inlined call site)", func() { sink.Set(PatternC_EscapingReadOnly()()) })
 	})
 
 	// PatternD: same inlining caveat as PatternC.
 	t.Run("PatternD_EscapingMutating", func(t *testing.T) {
-		got := testing.AllocsPerRun(100, func() { sink = PatternD_EscapingMutating()() })
-		t.Logf("PatternD (escaping mutating, inlined call site): allocs/run = %v", got)
+		measure.ObserveAllocs(t, "PatternD (escaping mutating, inlined call site)", func() { sink.Set(PatternD_EscapingMutating()()) })
 	})
 
 	// ---- Section 2: Escape Mechanism Variants ----
 
 	t.Run("EscapeViaGlobal", func(t *testing.T) {
-		got := testing.AllocsPerRun(100, func() { EscapeViaGlobal() })
-		t.Logf("EscapeViaGlobal: allocs/run = %v", got)
+		measure.ObserveAllocs(t, "EscapeViaGlobal", func() { EscapeViaGlobal() })
 	})
 
 	t.Run("EscapeViaGoroutine", func(t *testing.T) {
-		got := testing.AllocsPerRun(100, func() { sink = EscapeViaGoroutine() })
-		t.Logf("EscapeViaGoroutine: allocs/run = %v", got)
+		measure.ObserveAllocs(t, "EscapeViaGoroutine", func() { sink.Set(EscapeViaGoroutine()) })
 	})
 
 	t.Run("EscapeViaInterface", func(t *testing.T) {
-		got := testing.AllocsPerRun(100, func() { EscapeViaInterface() })
-		t.Logf("EscapeViaInterface: allocs/run = %v", got)
+		measure.ObserveAllocs(t, "EscapeViaInterface", func() { EscapeViaInterface() })
 	})
 
 	// ---- Section 3: IIFE ----
 
 	t.Run("IIFEReadOnly", func(t *testing.T) {
-		got := testing.AllocsPerRun(100, func() { sink = IIFEReadOnly() })
-		t.Logf("IIFEReadOnly: allocs/run = %v", got)
+		measure.ObserveAllocs(t, "IIFEReadOnly", func() { sink.Set(IIFEReadOnly()) })
 	})
 
 	t.Run("IIFEMutating", func(t *testing.T) {
-		got := testing.AllocsPerRun(100, func() { sink = IIFEMutating() })
-		t.Logf("IIFEMutating: allocs/run = %v", got)
+		measure.ObserveAllocs(t, "IIFEMutating", func() { sink.Set(IIFEMutating()) })
 	})
 
 	t.Run("IIFEMutatingEscaping", func(t *testing.T) {
-		got := testing.AllocsPerRun(100, func() { sink = IIFEMutatingEscaping()() })
-		t.Logf("IIFEMutatingEscaping: allocs/run = %v", got)
+		measure.ObserveAllocs(t, "IIFEMutatingEscaping", func() { sink.Set(IIFEMutatingEscaping()()) })
 	})
 
 	// ---- Section 4: Multi-Variable Capture ----
 
 	t.Run("CaptureOneVar", func(t *testing.T) {
-		got := testing.AllocsPerRun(100, func() { sink = CaptureOneVar()() })
-		t.Logf("CaptureOneVar: allocs/run = %v", got)
+		measure.ObserveAllocs(t, "CaptureOneVar", func() { sink.Set(CaptureOneVar()()) })
 	})
 
 	t.Run("CaptureTwoVars", func(t *testing.T) {
-		got := testing.AllocsPerRun(100, func() { sink = CaptureTwoVars()() })
-		t.Logf("CaptureTwoVars: allocs/run = %v", got)
+		measure.ObserveAllocs(t, "CaptureTwoVars", func() { sink.Set(CaptureTwoVars()()) })
 	})
 
 	t.Run("CaptureFourVars", func(t *testing.T) {
-		got := testing.AllocsPerRun(100, func() { sink = CaptureFourVars()() })
-		t.Logf("CaptureFourVars: allocs/run = %v", got)
+		measure.ObserveAllocs(t, "CaptureFourVars", func() { sink.Set(CaptureFourVars()()) })
 	})
 
 	t.Run("CaptureEightVars", func(t *testing.T) {
-		got := testing.AllocsPerRun(100, func() { sink = CaptureEightVars()() })
-		t.Logf("CaptureEightVars: allocs/run = %v", got)
+		measure.ObserveAllocs(t, "CaptureEightVars", func() { sink.Set(CaptureEightVars()()) })
 	})
 
 	// ---- Section 5: Nested Closures ----
 
 	t.Run("NestedNeitherEscapes", func(t *testing.T) {
-		got := testing.AllocsPerRun(100, func() { sink = NestedNeitherEscapes() })
-		t.Logf("NestedNeitherEscapes: allocs/run = %v", got)
+		measure.ObserveAllocs(t, "NestedNeitherEscapes", func() { sink.Set(NestedNeitherEscapes()) })
 	})
 
 	t.Run("NestedInnerEscapes", func(t *testing.T) {
-		got := testing.AllocsPerRun(100, func() { sink = NestedInnerEscapes()() })
-		t.Logf("NestedInnerEscapes: allocs/run = %v", got)
+		measure.ObserveAllocs(t, "NestedInnerEscapes", func() { sink.Set(NestedInnerEscapes()()) })
 	})
 
 	t.Run("NestedOuterEscapes", func(t *testing.T) {
-		got := testing.AllocsPerRun(100, func() { sink = NestedOuterEscapes()() })
-		t.Logf("NestedOuterEscapes: allocs/run = %v", got)
+		measure.ObserveAllocs(t, "NestedOuterEscapes", func() { sink.Set(NestedOuterEscapes()()) })
 	})
 
 	// ---- Section 6: Pointer Capture ----
 
 	t.Run("CapturePointerNonEscaping", func(t *testing.T) {
-		got := testing.AllocsPerRun(100, func() { sink = CapturePointerNonEscaping() })
-		t.Logf("CapturePointerNonEscaping: allocs/run = %v", got)
+		measure.ObserveAllocs(t, "CapturePointerNonEscaping", func() { sink.Set(CapturePointerNonEscaping()) })
 	})
 
 	t.Run("CapturePointerMutatingNonEscaping", func(t *testing.T) {
-		got := testing.AllocsPerRun(100, func() { sink = CapturePointerMutatingNonEscaping() })
-		t.Logf("CapturePointerMutatingNonEscaping: allocs/run = %v", got)
+		measure.ObserveAllocs(t, "CapturePointerMutatingNonEscaping", func() { sink.Set(CapturePointerMutatingNonEscaping()) })
 	})
 
 	t.Run("CapturePointerEscaping", func(t *testing.T) {
-		got := testing.AllocsPerRun(100, func() { sink = CapturePointerEscaping()() })
-		t.Logf("CapturePointerEscaping: allocs/run = %v", got)
+		measure.ObserveAllocs(t, "CapturePointerEscaping", func() { sink.Set(CapturePointerEscaping()()) })
 	})
 }
 
+// TestHypothesis evaluates the 2×2 factorial core (Section 1) as an Expected
+// Outcome table. PatternA is a control and is asserted (hypothesis.Assert);
+// PatternB is the research question, so its prediction is in
+// hypothesis.Observe mode: a mismatch yields result:unexpected instead of
+// a test failure.
+func TestHypothesis(t *testing.T) {
+	h := hypothesis.New("Reference capture without escape",
+		"PatternA_NonEscapingReadOnly", "PatternB_NonEscapingMutating")
+	h.Predict("Allocs/op", "", hypothesis.Exactly(0), hypothesis.Exactly(0).Observe())
+
+	h.Record("Allocs/op",
+		measure.Allocs(func() { sink.Set(PatternA_NonEscapingReadOnly()) }),
+		measure.Allocs(func() { sink.Set(PatternB_NonEscapingMutating()) }))
+	h.Check(t)
+}
+
+// TestEscapeAnalysis reads the compiler's -gcflags=-m=2 diagnostics directly
+// instead of inferring escape from allocation counts. Controls are asserted;
+// PatternB is observed, with the compiler's reasoning chain logged.
+func TestEscapeAnalysis(t *testing.T) {
+	r := escape.Load(t, ".")
+
+	r.AssertStack(t, "PatternA_NonEscapingReadOnly", "x")
+	r.AssertHeap(t, "PatternD_EscapingMutating", "x")
+
+	if d, ok := r.Heap("PatternB_NonEscapingMutating", "x"); ok {
+		t.Logf("PatternB: x moved to heap\n%s", d.Explain())
+	} else {
+		t.Logf("PatternB: x stays on the stack")
+	}
+	r.Log(t, "PatternB_NonEscapingMutating")
+}
+
 // ---- Section 0: Baseline ----
 
 func BenchmarkBaseline_NoCapture(b *testing.B) {
 	for b.Loop() {
-		sink = NoCapture()
+		sink.Set(NoCapture())
 	}
 }
 
@@ -156,25 +172,25 @@ func BenchmarkBaseline_NoCapture(b *testing.B) {
 
 func BenchmarkPatternA_NonEscapingReadOnly(b *testing.B) {
 	for b.Loop() {
-		sink = PatternA_NonEscapingReadOnly()
+		sink.Set(PatternA_NonEscapingReadOnly())
 	}
 }
 
 func BenchmarkPatternB_NonEscapingMutating(b *testing.B) {
 	for b.Loop() {
-		sink = PatternB_NonEscapingMutating()
+		sink.Set(PatternB_NonEscapingMutating())
 	}
 }
 
 func BenchmarkPatternC_EscapingReadOnly(b *testing.B) {
 	for b.Loop() {
-		sink = PatternC_EscapingReadOnly()()
+		sink.Set(PatternC_EscapingReadOnly()())
 	}
 }
 
 func BenchmarkPatternD_EscapingMutating(b *testing.B) {
 	for b.Loop() {
-		sink = PatternD_EscapingMutating()()
+		sink.Set(PatternD_EscapingMutating()())
 	}
 }
 
@@ -188,7 +204,7 @@ func BenchmarkEscapeViaGlobal(b *testing.B) {
 
 func BenchmarkEscapeViaGoroutine(b *testing.B) {
 	for b.Loop() {
-		sink = EscapeViaGoroutine()
+		sink.Set(EscapeViaGoroutine())
 	}
 }
 
@@ -202,19 +218,19 @@ func BenchmarkEscapeViaInterface(b *testing.B) {
 
 func BenchmarkIIFEReadOnly(b *testing.B) {
 	for b.Loop() {
-		sink = IIFEReadOnly()
+		sink.Set(IIFEReadOnly())
 	}
 }
 
 func BenchmarkIIFEMutating(b *testing.B) {
 	for b.Loop() {
-		sink = IIFEMutating()
+		sink.Set(IIFEMutating())
 	}
 }
 
 func BenchmarkIIFEMutatingEscaping(b *testing.B) {
 	for b.Loop() {
-		sink = IIFEMutatingEscaping()()
+		sink.Set(IIFEMutatingEscaping()())
 	}
 }
 
@@ -222,25 +238,25 @@ func BenchmarkIIFEMutatingEscaping(b *testing.B) {
 
 func BenchmarkCaptureOneVar(b *testing.B) {
 	for b.Loop() {
-		sink = CaptureOneVar()()
+		sink.Set(CaptureOneVar()())
 	}
 }
 
 func BenchmarkCaptureTwoVars(b *testing.B) {
 	for b.Loop() {
-		sink = CaptureTwoVars()()
+		sink.Set(CaptureTwoVars()())
 	}
 }
 
 func BenchmarkCaptureFourVars(b *testing.B) {
 	for b.Loop() {
-		sink = CaptureFourVars()()
+		sink.Set(CaptureFourVars()())
 	}
 }
 
 func BenchmarkCaptureEightVars(b *testing.B) {
 	for b.Loop() {
-		sink = CaptureEightVars()()
+		sink.Set(CaptureEightVars()())
 	}
 }
 
@@ -248,19 +264,19 @@ func BenchmarkCaptureEightVars(b *testing.B) {
 
 func BenchmarkNestedNeitherEscapes(b *testing.B) {
 	for b.Loop() {
-		sink = NestedNeitherEscapes()
+		sink.Set(NestedNeitherEscapes())
 	}
 }
 
 func BenchmarkNestedInnerEscapes(b *testing.B) {
 	for b.Loop() {
-		sink = NestedInnerEscapes()()
+		sink.Set(NestedInnerEscapes()())
 	}
 }
 
 func BenchmarkNestedOuterEscapes(b *testing.B) {
 	for b.Loop() {
-		sink = NestedOuterEscapes()()
+		sink.Set(NestedOuterEscapes()())
 	}
 }
 
@@ -268,18 +284,18 @@ func BenchmarkNestedOuterEscapes(b *testing.B) {
 
 func BenchmarkCapturePointerNonEscaping(b *testing.B) {
 	for b.Loop() {
-		sink = CapturePointerNonEscaping()
+		sink.Set(CapturePointerNonEscaping())
 	}
 }
 
 func BenchmarkCapturePointerMutatingNonEscaping(b *testing.B) {
 	for b.Loop() {
-		sink = CapturePointerMutatingNonEscaping()
+		sink.Set(CapturePointerMutatingNonEscaping())
 	}
 }
 
 func BenchmarkCapturePointerEscaping(b *testing.B) {
 	for b.Loop() {
-		sink = CapturePointerEscaping()()
+		sink.Set(CapturePointerEscaping()())
 	}
 }
@@ -1,21 +1,76 @@
 package goroutinecost
 
-import "testing"
+import (
+	"testing"
+
+	"go-lab/pkg/measure"
+	"go-lab/pkg/schedtrace"
+)
 
 func BenchmarkSpawnUnbuffered(b *testing.B) {
+	measure.ReportMetrics(b)
 	for b.Loop() {
 		SpawnUnbuffered()
 	}
 }
 
 func BenchmarkSpawnBuffered(b *testing.B) {
+	measure.ReportMetrics(b)
 	for b.Loop() {
 		SpawnBuffered()
 	}
 }
 
 func BenchmarkSpawnWaitGroup(b *testing.B) {
+	measure.ReportMetrics(b)
 	for b.Loop() {
 		SpawnWaitGroup()
 	}
 }
+
+// TestSchedTrace records a runtime/trace of each pattern and reports the
+// scheduler interaction that ns/op alone cannot explain: creation-to-running
+// latency of the spawned goroutine, park/unpark events, and whether the
+// receiver resumed on the P of the goroutine that woke it (runnext handoff).
+//
+//   - Hard assertion: every spawned goroutine is seen being created and
+//     running (the trace is complete).
+//   - Observation only: latencies, parks and P placement are the research
+//     question and are logged.
+func TestSchedTrace(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs go tool trace")
+	}
+	const n = 1000
+	patterns := []struct {
+		name string
+		f    func()
+	}{
+		{"SpawnUnbuffered", SpawnUnbuffered},
+		{"SpawnBuffered", SpawnBuffered},
+		{"SpawnWaitGroup", SpawnWaitGroup},
+	}
+	for _, p := range patterns {
+		t.Run(p.name, func(t *testing.T) {
+			s := schedtrace.Record(t, p.name, func() {
+				for range n {
+					p.f()
+				}
+			})
+			if s.Spawned != n || len(s.Latency) != n {
+				t.Fatalf("trace shows %d spawned, %d running goroutines; want %d", s.Spawned, len(s.Latency), n)
+			}
+			s.Log(t, p.name)
+		})
+	}
+}
+
+// TestMeasurementBackends shows how much reading memory statistics every
+// 100 spawns distorts the spawn cost: runtime.ReadMemStats stops the world
+// on every read, runtime/metrics does not. Observation only.
+func TestMeasurementBackends(t *testing.T) {
+	if testing.Short() {
+		t.Skip("timing comparison")
+	}
+	measure.CompareBackends(100_000, 100, SpawnUnbuffered).Log(t, "SpawnUnbuffered")
+}
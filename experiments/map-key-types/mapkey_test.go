@@ -3,12 +3,14 @@ package mapkey
 import (
 	"strconv"
 	"testing"
+
+	"go-lab/pkg/measure"
 )
 
 const mapSize = 10_000
 
 // sink prevents dead-code elimination by the compiler.
-var sink int
+var sink measure.Sink[int]
 
 // ---------------------------------------------------------------------------
 // Test: verify all three key strategies produce correct lookups
@@ -104,7 +106,7 @@ func BenchmarkLookup_StringKey(b *testing.B) {
 		for i := range mapSize {
 			acc += m[StringKey(i, "abc")]
 		}
-		sink = acc
+		sink.Set(acc)
 	}
 }
 
@@ -119,7 +121,7 @@ func BenchmarkLookup_CompositeKey(b *testing.B) {
 		for i := range mapSize {
 			acc += m[CompositeKey{ID: i, Code: "abc"}]
 		}
-		sink = acc
+		sink.Set(acc)
 	}
 }
 
@@ -134,7 +136,7 @@ func BenchmarkLookup_IntPairKey(b *testing.B) {
 		for i := range mapSize {
 			acc += m[IntPairKey{X: i, Y: i * 7}]
 		}
-		sink = acc
+		sink.Set(acc)
 	}
 }
 
@@ -159,7 +161,7 @@ func BenchmarkLookupPrebuilt_StringKey(b *testing.B) {
 		for _, k := range keys {
 			acc += m[k]
 		}
-		sink = acc
+		sink.Set(acc)
 	}
 }
 
@@ -170,7 +172,7 @@ func BenchmarkLookupPrebuilt_StringKey(b *testing.B) {
 func BenchmarkKeyBuild_String(b *testing.B) {
 	for b.Loop() {
 		for i := range mapSize {
-			sink = len(strconv.Itoa(i) + ":" + "abc")
+			sink.Set(len(strconv.Itoa(i) + ":" + "abc"))
 		}
 	}
 }
@@ -179,7 +181,7 @@ func BenchmarkKeyBuild_CompositeKey(b *testing.B) {
 	for b.Loop() {
 		for i := range mapSize {
 			k := CompositeKey{ID: i, Code: "abc"}
-			sink = k.ID
+			sink.Set(k.ID)
 		}
 	}
 }
@@ -188,7 +190,7 @@ func BenchmarkKeyBuild_IntPairKey(b *testing.B) {
 	for b.Loop() {
 		for i := range mapSize {
 			k := IntPairKey{X: i, Y: i * 7}
-			sink = k.X
+			sink.Set(k.X)
 		}
 	}
 }
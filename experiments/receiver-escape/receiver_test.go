@@ -3,32 +3,57 @@ package receiver
 import (
 	"testing"
 	"unsafe"
+
+	"go-lab/pkg/layout"
+	"go-lab/pkg/measure"
 )
 
 // TestStructSizes verifies expected struct sizes.
 func TestStructSizes(t *testing.T) {
 	tests := []struct {
-		name string
-		got  uintptr
-		want uintptr
+		name   string
+		got    uintptr
+		want   uintptr
+		layout layout.Layout
 	}{
-		{"Small", unsafe.Sizeof(Small{}), 24},
-		{"Medium", unsafe.Sizeof(Medium{}), 64},
-		{"Large", unsafe.Sizeof(Large{}), 128},
-		{"XLarge", unsafe.Sizeof(XLarge{}), 256},
+		{"Small", unsafe.Sizeof(Small{}), 24, layout.For[Small]()},
+		{"Medium", unsafe.Sizeof(Medium{}), 64, layout.For[Medium]()},
+		{"Large", unsafe.Sizeof(Large{}), 128, layout.For[Large]()},
+		{"XLarge", unsafe.Sizeof(XLarge{}), 256, layout.For[XLarge]()},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			if tt.got != tt.want {
 				t.Errorf("unsafe.Sizeof(%s{}) = %d B, want %d B", tt.name, tt.got, tt.want)
 			}
-			t.Logf("unsafe.Sizeof(%s{}) = %d B", tt.name, tt.got)
+			if p := tt.layout.Padding(); p != 0 {
+				t.Errorf("%s has %d B padding, want none\n%s", tt.name, p, tt.layout.Diagram())
+			}
+			t.Logf("unsafe.Sizeof(%s{}) = %d B\n%s", tt.name, tt.got, tt.layout.Diagram())
 		})
 	}
 }
 
+// TestStructSizesByArch statically verifies that the sizes hold on every
+// target: float64 arrays have no padding, even where float64 is only
+// 4-byte aligned (386, arm).
+func TestStructSizesByArch(t *testing.T) {
+	want := map[string]uintptr{"Small": 24, "Medium": 64, "Large": 128, "XLarge": 256}
+	for _, arch := range []string{"amd64", "arm64", "386", "arm", "wasm"} {
+		layouts, err := layout.Static(".", arch)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for name, size := range want {
+			if got := layouts[name].Size; got != size {
+				t.Errorf("%s on %s = %d B, want %d B", name, arch, got, size)
+			}
+		}
+	}
+}
+
 // sink prevents dead-code elimination.
-var sink float64
+var sink measure.Sink[float64]
 
 // ---------------------------------------------------------------------------
 // Small (24 B)
@@ -37,14 +62,14 @@ var sink float64
 func BenchmarkSmallValue(b *testing.B) {
 	s := Small{X: 1.0, Y: 2.0, Z: 3.0}
 	for b.Loop() {
-		sink = s.Sum()
+		sink.Set(s.Sum())
 	}
 }
 
 func BenchmarkSmallPointer(b *testing.B) {
 	s := Small{X: 1.0, Y: 2.0, Z: 3.0}
 	for b.Loop() {
-		sink = s.PSum()
+		sink.Set(s.PSum())
 	}
 }
 
@@ -58,7 +83,7 @@ func BenchmarkMediumValue(b *testing.B) {
 		m.Data[i] = float64(i)
 	}
 	for b.Loop() {
-		sink = m.Sum()
+		sink.Set(m.Sum())
 	}
 }
 
@@ -68,7 +93,7 @@ func BenchmarkMediumPointer(b *testing.B) {
 		m.Data[i] = float64(i)
 	}
 	for b.Loop() {
-		sink = m.PSum()
+		sink.Set(m.PSum())
 	}
 }
 
@@ -82,7 +107,7 @@ func BenchmarkLargeValue(b *testing.B) {
 		l.Data[i] = float64(i)
 	}
 	for b.Loop() {
-		sink = l.Sum()
+		sink.Set(l.Sum())
 	}
 }
 
@@ -92,7 +117,7 @@ func BenchmarkLargePointer(b *testing.B) {
 		l.Data[i] = float64(i)
 	}
 	for b.Loop() {
-		sink = l.PSum()
+		sink.Set(l.PSum())
 	}
 }
 
@@ -106,7 +131,7 @@ func BenchmarkXLargeValue(b *testing.B) {
 		x.Data[i] = float64(i)
 	}
 	for b.Loop() {
-		sink = x.Sum()
+		sink.Set(x.Sum())
 	}
 }
 
@@ -116,7 +141,7 @@ func BenchmarkXLargePointer(b *testing.B) {
 		x.Data[i] = float64(i)
 	}
 	for b.Loop() {
-		sink = x.PSum()
+		sink.Set(x.PSum())
 	}
 }
 
@@ -131,14 +156,14 @@ func BenchmarkXLargePointer(b *testing.B) {
 func BenchmarkSmallValueNoInline(b *testing.B) {
 	s := Small{X: 1.0, Y: 2.0, Z: 3.0}
 	for b.Loop() {
-		sink = s.SumNoInline()
+		sink.Set(s.SumNoInline())
 	}
 }
 
 func BenchmarkSmallPointerNoInline(b *testing.B) {
 	s := Small{X: 1.0, Y: 2.0, Z: 3.0}
 	for b.Loop() {
-		sink = s.PSumNoInline()
+		sink.Set(s.PSumNoInline())
 	}
 }
 
@@ -152,7 +177,7 @@ func BenchmarkMediumValueNoInline(b *testing.B) {
 		m.Data[i] = float64(i)
 	}
 	for b.Loop() {
-		sink = m.SumNoInline()
+		sink.Set(m.SumNoInline())
 	}
 }
 
@@ -162,7 +187,7 @@ func BenchmarkMediumPointerNoInline(b *testing.B) {
 		m.Data[i] = float64(i)
 	}
 	for b.Loop() {
-		sink = m.PSumNoInline()
+		sink.Set(m.PSumNoInline())
 	}
 }
 
@@ -176,7 +201,7 @@ func BenchmarkLargeValueNoInline(b *testing.B) {
 		l.Data[i] = float64(i)
 	}
 	for b.Loop() {
-		sink = l.SumNoInline()
+		sink.Set(l.SumNoInline())
 	}
 }
 
@@ -186,7 +211,7 @@ func BenchmarkLargePointerNoInline(b *testing.B) {
 		l.Data[i] = float64(i)
 	}
 	for b.Loop() {
-		sink = l.PSumNoInline()
+		sink.Set(l.PSumNoInline())
 	}
 }
 
@@ -200,7 +225,7 @@ func BenchmarkXLargeValueNoInline(b *testing.B) {
 		x.Data[i] = float64(i)
 	}
 	for b.Loop() {
-		sink = x.SumNoInline()
+		sink.Set(x.SumNoInline())
 	}
 }
 
@@ -210,7 +235,7 @@ func BenchmarkXLargePointerNoInline(b *testing.B) {
 		x.Data[i] = float64(i)
 	}
 	for b.Loop() {
-		sink = x.PSumNoInline()
+		sink.Set(x.PSumNoInline())
 	}
 }
 
@@ -239,14 +264,14 @@ func callViaPInterface(s PSumer) float64 {
 func BenchmarkSmallValueIface(b *testing.B) {
 	s := Small{X: 1.0, Y: 2.0, Z: 3.0}
 	for b.Loop() {
-		sink = callViaInterface(s)
+		sink.Set(callViaInterface(s))
 	}
 }
 
 func BenchmarkSmallPointerIface(b *testing.B) {
 	s := &Small{X: 1.0, Y: 2.0, Z: 3.0}
 	for b.Loop() {
-		sink = callViaPInterface(s)
+		sink.Set(callViaPInterface(s))
 	}
 }
 
@@ -260,7 +285,7 @@ func BenchmarkMediumValueIface(b *testing.B) {
 		m.Data[i] = float64(i)
 	}
 	for b.Loop() {
-		sink = callViaInterface(m)
+		sink.Set(callViaInterface(m))
 	}
 }
 
@@ -270,7 +295,7 @@ func BenchmarkMediumPointerIface(b *testing.B) {
 		m.Data[i] = float64(i)
 	}
 	for b.Loop() {
-		sink = callViaPInterface(m)
+		sink.Set(callViaPInterface(m))
 	}
 }
 
@@ -284,7 +309,7 @@ func BenchmarkLargeValueIface(b *testing.B) {
 		l.Data[i] = float64(i)
 	}
 	for b.Loop() {
-		sink = callViaInterface(l)
+		sink.Set(callViaInterface(l))
 	}
 }
 
@@ -294,7 +319,7 @@ func BenchmarkLargePointerIface(b *testing.B) {
 		l.Data[i] = float64(i)
 	}
 	for b.Loop() {
-		sink = callViaPInterface(l)
+		sink.Set(callViaPInterface(l))
 	}
 }
 
@@ -308,7 +333,7 @@ func BenchmarkXLargeValueIface(b *testing.B) {
 		x.Data[i] = float64(i)
 	}
 	for b.Loop() {
-		sink = callViaInterface(x)
+		sink.Set(callViaInterface(x))
 	}
 }
 
@@ -318,6 +343,6 @@ func BenchmarkXLargePointerIface(b *testing.B) {
 		x.Data[i] = float64(i)
 	}
 	for b.Loop() {
-		sink = callViaPInterface(x)
+		sink.Set(callViaPInterface(x))
 	}
 }
@@ -1,3 +1,5 @@
 module go-lab/experiments/stdout-is-file
 
 go 1.26.0
+
+require go-lab/pkg v0.0.0
@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	stdout "go-lab/experiments/stdout-is-file"
+	"go-lab/pkg/measure"
 )
 
 // ============================================================
@@ -159,7 +160,7 @@ func TestRedirectionToFile(t *testing.T) {
 var benchData = []byte("hello, world\n") // 13 bytes, fixed
 
 // sink prevents the compiler from eliminating Write calls.
-var sink int
+var sink measure.Sink[int]
 
 // BenchmarkWriteStdout writes through os.Stdout (redirected to /dev/null).
 // Pattern A: os.File.Write via the os.Stdout variable.
@@ -177,7 +178,7 @@ func BenchmarkWriteStdout(b *testing.B) {
 	b.ResetTimer()
 	for b.Loop() {
 		n, _ := os.Stdout.Write(benchData)
-		sink = n
+		sink.Set(n)
 	}
 }
 
@@ -193,7 +194,7 @@ func BenchmarkWriteFile(b *testing.B) {
 	b.ResetTimer()
 	for b.Loop() {
 		n, _ := devNull.Write(benchData)
-		sink = n
+		sink.Set(n)
 	}
 }
 
@@ -223,7 +224,7 @@ func BenchmarkSyscallWriteFd1(b *testing.B) {
 	b.ResetTimer()
 	for b.Loop() {
 		n, _ := syscall.Write(1, benchData)
-		sink = n
+		sink.Set(n)
 	}
 }
 
@@ -240,6 +241,6 @@ func BenchmarkSyscallWriteFileFd(b *testing.B) {
 	b.ResetTimer()
 	for b.Loop() {
 		n, _ := syscall.Write(fd, benchData)
-		sink = n
+		sink.Set(n)
 	}
 }
@@ -1,9 +1,13 @@
 package stringconcat
 
 import (
-	"fmt"
 	"strings"
 	"testing"
+
+	"go-lab/pkg/allocsite"
+	"go-lab/pkg/complexity"
+	"go-lab/pkg/hypothesis"
+	"go-lab/pkg/measure"
 )
 
 // makeParts generates n fixed-length (8 byte) string parts.
@@ -17,35 +21,113 @@ func makeParts(n int) []string {
 
 var sizes = []int{2, 4, 8, 16, 32, 64}
 
+// TestHypothesis checks the Expected Outcome at the largest size (N=64).
+// ConcatPlus allocates one new string per appended part after the first and
+// copies the accumulated prefix every time; ConcatBuilderGrow allocates once.
+func TestHypothesis(t *testing.T) {
+	const n = 64
+	parts := makeParts(n)
+
+	h := hypothesis.New("String concatenation (N=64)", "ConcatPlus", "ConcatBuilderGrow")
+	h.Predict("Allocs/op", "", hypothesis.Exactly(n-1), hypothesis.Exactly(1))
+	// Σ 8k for k=2..64 bytes of copying, rounded up to size classes.
+	h.Predict("B/op", "B", hypothesis.AtLeast(16_632).Observe(), hypothesis.Approx(8*n, 0.05).Observe())
+
+	h.Record("Allocs/op",
+		measure.Allocs(func() { _ = ConcatPlus(parts) }),
+		measure.Allocs(func() { _ = ConcatBuilderGrow(parts) }))
+	h.Record("B/op",
+		measure.Mem(1000, func() { _ = ConcatPlus(parts) }).BytesPerOp(),
+		measure.Mem(1000, func() { _ = ConcatBuilderGrow(parts) }).BytesPerOp())
+	h.Check(t)
+}
+
 func BenchmarkConcatPlus(b *testing.B) {
-	for _, n := range sizes {
+	measure.SweepN(b, sizes, func(b *testing.B, n int) {
 		parts := makeParts(n)
-		b.Run(fmt.Sprintf("N=%d", n), func(b *testing.B) {
-			for b.Loop() {
-				_ = ConcatPlus(parts)
-			}
-		})
-	}
+		for b.Loop() {
+			_ = ConcatPlus(parts)
+		}
+	})
 }
 
 func BenchmarkConcatBuilder(b *testing.B) {
-	for _, n := range sizes {
+	measure.SweepN(b, sizes, func(b *testing.B, n int) {
 		parts := makeParts(n)
-		b.Run(fmt.Sprintf("N=%d", n), func(b *testing.B) {
-			for b.Loop() {
-				_ = ConcatBuilder(parts)
-			}
-		})
-	}
+		for b.Loop() {
+			_ = ConcatBuilder(parts)
+		}
+	})
 }
 
 func BenchmarkConcatBuilderGrow(b *testing.B) {
-	for _, n := range sizes {
+	measure.SweepN(b, sizes, func(b *testing.B, n int) {
 		parts := makeParts(n)
-		b.Run(fmt.Sprintf("N=%d", n), func(b *testing.B) {
-			for b.Loop() {
-				_ = ConcatBuilderGrow(parts)
+		for b.Loop() {
+			_ = ConcatBuilderGrow(parts)
+		}
+	})
+}
+
+// TestAllocSites reruns each benchmark at N=64 with every allocation
+// profiled and logs concat.go annotated with the allocations per op of each
+// line and the runtime function that made them. The control: the profile
+// accounts for the allocations testing.AllocsPerRun counts, except that
+// only every other tiny allocation (ConcatBuilder's first 8-byte buffer) is
+// profiled.
+func TestAllocSites(t *testing.T) {
+	if testing.Short() {
+		t.Skip("reruns benchmarks")
+	}
+	parts := makeParts(64)
+	for _, c := range []struct {
+		name string
+		f    func([]string) string
+	}{
+		{"ConcatPlus", ConcatPlus},
+		{"ConcatBuilder", ConcatBuilder},
+		{"ConcatBuilderGrow", ConcatBuilderGrow},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			p := allocsite.Benchmark(func(b *testing.B) {
+				for b.Loop() {
+					_ = c.f(parts)
+				}
+			})
+			if got, want := p.AllocsPerOp("concat.go"), measure.Allocs(func() { _ = c.f(parts) }); got > want || want-got >= 1 {
+				t.Errorf("profile attributes %v allocs/op to concat.go, AllocsPerRun counts %v", got, want)
+			}
+			listing, err := p.Listing("concat.go")
+			if err != nil {
+				t.Fatal(err)
 			}
+			t.Logf("BenchmarkConcat%s/N=64:\n%s", strings.TrimPrefix(c.name, "Concat"), listing)
 		})
 	}
 }
+
+// TestComplexity checks the growth rates claimed in concat.go over the
+// benchmark sizes. B/op and allocs/op are deterministic, so every claim is
+// asserted: a fit that picks another model fails the test.
+func TestComplexity(t *testing.T) {
+	bytesPerOp := func(f func([]string) string) func(n int) float64 {
+		return func(n int) float64 {
+			parts := makeParts(n)
+			return measure.Mem(1000, func() { _ = f(parts) }).BytesPerOp()
+		}
+	}
+	allocsPerOp := func(f func([]string) string) func(n int) float64 {
+		return func(n int) float64 {
+			parts := makeParts(n)
+			return measure.Allocs(func() { _ = f(parts) })
+		}
+	}
+	// ConcatPlus copies the accumulated prefix for every part: Σ 8k bytes.
+	complexity.Assert(t, "ConcatPlus B/op", complexity.Points(sizes, bytesPerOp(ConcatPlus)), complexity.Quadratic)
+	complexity.Assert(t, "ConcatPlus allocs/op", complexity.Points(sizes, allocsPerOp(ConcatPlus)), complexity.Linear)
+	// ConcatBuilder doubles its buffer: log N allocations summing to ~2·8N bytes.
+	complexity.Assert(t, "ConcatBuilder B/op", complexity.Points(sizes, bytesPerOp(ConcatBuilder)), complexity.Linear)
+	complexity.Assert(t, "ConcatBuilder allocs/op", complexity.Points(sizes, allocsPerOp(ConcatBuilder)), complexity.Log)
+	complexity.Assert(t, "ConcatBuilderGrow B/op", complexity.Points(sizes, bytesPerOp(ConcatBuilderGrow)), complexity.Linear)
+	complexity.Assert(t, "ConcatBuilderGrow allocs/op", complexity.Points(sizes, allocsPerOp(ConcatBuilderGrow)), complexity.Constant)
+}
@@ -1,3 +1,5 @@
 module go-lab/experiments/string-concat
 
 go 1.26.0
+
+require go-lab/pkg v0.0.0
@@ -1,9 +1,12 @@
 package stringzerocopy
 
 import (
-	"fmt"
 	"strings"
 	"testing"
+
+	"go-lab/pkg/complexity"
+	"go-lab/pkg/disasm"
+	"go-lab/pkg/measure"
 )
 
 var sizes = []int{8, 64, 512, 4096}
@@ -15,74 +18,97 @@ func makeMap(n int) map[string]int {
 	return m
 }
 
+// TestRuntimeCalls backs the doc-comment claims of conversion.go with the
+// machine code: the copying conversions call the runtime copy, while the
+// patterns the compiler recognizes call neither conversion routine.
+func TestRuntimeCalls(t *testing.T) {
+	r := disasm.Load(t, ".")
+
+	r.AssertCalls(t, "BytesToStringAssign", "runtime.slicebytetostring")
+	r.AssertCalls(t, "StringToBytesAssign", "runtime.stringtoslicebyte")
+	r.AssertCalls(t, "BytesToStringConcat", "runtime.concatstring2")
+
+	for _, fn := range []string{"BytesToStringMapLookup", "StringToBytesRange", "BytesToStringCompare"} {
+		r.AssertNoCalls(t, fn, "runtime.slicebytetostring")
+		r.AssertNoCalls(t, fn, "runtime.stringtoslicebyte")
+		r.LogRuntime(t, fn)
+	}
+}
+
+// TestComplexity checks the cost claims over the sizes: a copying
+// conversion allocates bytes linear in N, a recognized pattern allocates
+// nothing at any N.
+func TestComplexity(t *testing.T) {
+	bytesPerOp := func(f func(bs []byte)) func(n int) float64 {
+		return func(n int) float64 {
+			bs := makeBytes(n)
+			return measure.Mem(1000, func() { f(bs) }).BytesPerOp()
+		}
+	}
+	complexity.Assert(t, "BytesToStringAssign B/op",
+		complexity.Points(sizes, bytesPerOp(func(bs []byte) { _ = BytesToStringAssign(bs) })), complexity.Linear)
+	complexity.Assert(t, "BytesToStringConcat B/op",
+		complexity.Points(sizes, bytesPerOp(func(bs []byte) { _ = BytesToStringConcat(bs) })), complexity.Linear)
+	complexity.Assert(t, "BytesToStringCompare B/op",
+		complexity.Points(sizes, bytesPerOp(func(bs []byte) { _ = BytesToStringCompare(bs, "target") })), complexity.Constant)
+}
+
 // --- Copy patterns (expect allocs/op = 1) ---
 
 func BenchmarkBytesToStringAssign(b *testing.B) {
-	for _, n := range sizes {
+	measure.SweepN(b, sizes, func(b *testing.B, n int) {
 		bs := makeBytes(n)
-		b.Run(fmt.Sprintf("N=%d", n), func(b *testing.B) {
-			for b.Loop() {
-				_ = BytesToStringAssign(bs)
-			}
-		})
-	}
+		for b.Loop() {
+			_ = BytesToStringAssign(bs)
+		}
+	})
 }
 
 func BenchmarkStringToBytesAssign(b *testing.B) {
-	for _, n := range sizes {
+	measure.SweepN(b, sizes, func(b *testing.B, n int) {
 		s := makeString(n)
-		b.Run(fmt.Sprintf("N=%d", n), func(b *testing.B) {
-			for b.Loop() {
-				_ = StringToBytesAssign(s)
-			}
-		})
-	}
+		for b.Loop() {
+			_ = StringToBytesAssign(s)
+		}
+	})
 }
 
 func BenchmarkBytesToStringConcat(b *testing.B) {
-	for _, n := range sizes {
+	measure.SweepN(b, sizes, func(b *testing.B, n int) {
 		bs := makeBytes(n)
-		b.Run(fmt.Sprintf("N=%d", n), func(b *testing.B) {
-			for b.Loop() {
-				_ = BytesToStringConcat(bs)
-			}
-		})
-	}
+		for b.Loop() {
+			_ = BytesToStringConcat(bs)
+		}
+	})
 }
 
 // --- Zero-copy patterns (expect allocs/op = 0) ---
 
 func BenchmarkBytesToStringMapLookup(b *testing.B) {
-	for _, n := range sizes {
+	measure.SweepN(b, sizes, func(b *testing.B, n int) {
 		bs := makeBytes(n)
 		m := makeMap(n)
-		b.Run(fmt.Sprintf("N=%d", n), func(b *testing.B) {
-			for b.Loop() {
-				_ = BytesToStringMapLookup(m, bs)
-			}
-		})
-	}
+		for b.Loop() {
+			_ = BytesToStringMapLookup(m, bs)
+		}
+	})
 }
 
 func BenchmarkStringToBytesRange(b *testing.B) {
-	for _, n := range sizes {
+	measure.SweepN(b, sizes, func(b *testing.B, n int) {
 		s := makeString(n)
-		b.Run(fmt.Sprintf("N=%d", n), func(b *testing.B) {
-			for b.Loop() {
-				StringToBytesRange(s)
-			}
-		})
-	}
+		for b.Loop() {
+			StringToBytesRange(s)
+		}
+	})
 }
 
 func BenchmarkBytesToStringCompare(b *testing.B) {
-	for _, n := range sizes {
+	measure.SweepN(b, sizes, func(b *testing.B, n int) {
 		bs := makeBytes(n)
 		target := makeString(n)
-		b.Run(fmt.Sprintf("N=%d", n), func(b *testing.B) {
-			for b.Loop() {
-				_ = BytesToStringCompare(bs, target)
-			}
-		})
-	}
+		for b.Loop() {
+			_ = BytesToStringCompare(bs, target)
+		}
+	})
 }
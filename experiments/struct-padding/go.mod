@@ -1,3 +1,5 @@
 module go-lab/experiments/struct-padding
 
 go 1.25.7
+
+require go-lab/pkg v0.0.0
@@ -10,6 +10,9 @@ package padding
 //	c bool    offset 16: 1 B + 3 B padding  (align next int32 to 4)
 //	d int32   offset 20: 4 B
 //	                      Total: 24 B
+//
+// On 386 and arm int64 is only 4-byte aligned, so b starts at offset 4 and
+// the total is 20 B (see TestSizeByArch).
 type Unpadded struct {
 	a bool
 	b int64
@@ -27,6 +30,8 @@ type Unpadded struct {
 //	a bool    offset 12: 1 B
 //	c bool    offset 13: 1 B + 2 B padding  (struct tail alignment to 8)
 //	                      Total: 16 B
+//
+// The layout and size are the same on 386 and arm.
 type Padded struct {
 	b int64
 	d int32
@@ -3,6 +3,10 @@ package padding
 import (
 	"testing"
 	"unsafe"
+
+	"go-lab/pkg/hypothesis"
+	"go-lab/pkg/layout"
+	"go-lab/pkg/measure"
 )
 
 // TestSize statically verifies struct sizes predicted by the hypothesis.
@@ -28,21 +32,85 @@ func TestSize(t *testing.T) {
 	t.Logf("Size reduction: %.1f%%", diff)
 }
 
-// TestFieldOffsets logs the offset of each field for visual verification.
+// TestSizeByArch statically verifies the sizes on every target, including
+// the 32-bit ones where int64 is only 4-byte aligned, using the gc size
+// model of go/types. It passes or fails identically on every host.
+func TestSizeByArch(t *testing.T) {
+	tests := []struct {
+		arch     string
+		unpadded uintptr
+		padded   uintptr
+	}{
+		{"amd64", 24, 16},
+		{"arm64", 24, 16},
+		{"386", 20, 16},
+		{"arm", 20, 16},
+		{"wasm", 24, 16},
+	}
+	for _, tt := range tests {
+		t.Run(tt.arch, func(t *testing.T) {
+			layouts, err := layout.Static(".", tt.arch)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for name, want := range map[string]uintptr{"Unpadded": tt.unpadded, "Padded": tt.padded} {
+				if got := layouts[name].Size; got != want {
+					t.Errorf("%s on %s = %d B, want %d B\n%s", name, tt.arch, got, want, layouts[name].Diagram())
+				}
+			}
+			t.Logf("Unpadded on %s:\n%s", tt.arch, layouts["Unpadded"].Diagram())
+		})
+	}
+}
+
+// TestHypothesis evaluates the Expected Outcome table of the experiment issue.
+// Sizes are fixed by the alignment rules and asserted; the allocation volume
+// of a 1M-element slice is observed.
+func TestHypothesis(t *testing.T) {
+	h := hypothesis.New("Struct padding", "Unpadded", "Padded")
+	h.Predict("Size", "B", hypothesis.Exactly(24), hypothesis.Exactly(16))
+	h.Predict("B/op (1M slice)", "B",
+		hypothesis.AtLeast(24*N).Observe(), hypothesis.AtLeast(16*N).Observe())
+
+	h.Record("Size", float64(unsafe.Sizeof(Unpadded{})), float64(unsafe.Sizeof(Padded{})))
+	h.Record("B/op (1M slice)",
+		measure.Mem(10, func() { sliceSink.Set(make([]Unpadded, N)) }).BytesPerOp(),
+		measure.Mem(10, func() { sliceSink.Set(make([]Padded, N)) }).BytesPerOp())
+	h.Check(t)
+}
+
+// TestFieldOffsets verifies the layout diagrams of the Unpadded and Padded
+// doc comments against reflect, field by field.
 func TestFieldOffsets(t *testing.T) {
-	t.Log("=== Unpadded ===")
-	t.Logf("  a bool   offset=%d  size=%d", unsafe.Offsetof(Unpadded{}.a), unsafe.Sizeof(Unpadded{}.a))
-	t.Logf("  b int64  offset=%d  size=%d", unsafe.Offsetof(Unpadded{}.b), unsafe.Sizeof(Unpadded{}.b))
-	t.Logf("  c bool   offset=%d  size=%d", unsafe.Offsetof(Unpadded{}.c), unsafe.Sizeof(Unpadded{}.c))
-	t.Logf("  d int32  offset=%d  size=%d", unsafe.Offsetof(Unpadded{}.d), unsafe.Sizeof(Unpadded{}.d))
-	t.Logf("  total=%d", unsafe.Sizeof(Unpadded{}))
-
-	t.Log("=== Padded ===")
-	t.Logf("  b int64  offset=%d  size=%d", unsafe.Offsetof(Padded{}.b), unsafe.Sizeof(Padded{}.b))
-	t.Logf("  d int32  offset=%d  size=%d", unsafe.Offsetof(Padded{}.d), unsafe.Sizeof(Padded{}.d))
-	t.Logf("  a bool   offset=%d  size=%d", unsafe.Offsetof(Padded{}.a), unsafe.Sizeof(Padded{}.a))
-	t.Logf("  c bool   offset=%d  size=%d", unsafe.Offsetof(Padded{}.c), unsafe.Sizeof(Padded{}.c))
-	t.Logf("  total=%d", unsafe.Sizeof(Padded{}))
+	if unsafe.Sizeof(uintptr(0)) != 8 {
+		t.Skip("the doc-comment diagrams are for 64-bit platforms")
+	}
+	tests := []struct {
+		name string
+		got  layout.Layout
+		want string
+	}{
+		{"Unpadded", layout.For[Unpadded](), "" +
+			"a bool    offset 0:  1 B + 7 B padding  (align next int64 to 8)\n" +
+			"b int64   offset 8:  8 B\n" +
+			"c bool    offset 16: 1 B + 3 B padding  (align next int32 to 4)\n" +
+			"d int32   offset 20: 4 B\n" +
+			"                      Total: 24 B\n"},
+		{"Padded", layout.For[Padded](), "" +
+			"b int64   offset 0:  8 B\n" +
+			"d int32   offset 8:  4 B\n" +
+			"a bool    offset 12: 1 B\n" +
+			"c bool    offset 13: 1 B + 2 B padding  (struct tail alignment to 8)\n" +
+			"                      Total: 16 B\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.got.Diagram(); got != tt.want {
+				t.Errorf("layout of %s =\n%s\nwant (doc comment)\n%s", tt.name, got, tt.want)
+			}
+			t.Logf("%s: %d B padding\n%s", tt.name, tt.got.Padding(), tt.got.Table())
+		})
+	}
 }
 
 // ---------------------------------------------------------------------------
@@ -52,7 +120,10 @@ func TestFieldOffsets(t *testing.T) {
 const N = 1024 * 1024 // 1M elements
 
 // BenchmarkAllocUnpadded measures allocation throughput for Unpadded structs.
+// The GC work per op is reported as well: 24 MB vs 16 MB per slice should
+// show up as more frequent GC cycles (see lab gctrace).
 func BenchmarkAllocUnpadded(b *testing.B) {
+	measure.ReportGC(b)
 	for b.Loop() {
 		s := make([]Unpadded, N)
 		_ = s
@@ -61,6 +132,7 @@ func BenchmarkAllocUnpadded(b *testing.B) {
 
 // BenchmarkAllocPadded measures allocation throughput for Padded structs.
 func BenchmarkAllocPadded(b *testing.B) {
+	measure.ReportGC(b)
 	for b.Loop() {
 		s := make([]Padded, N)
 		_ = s
@@ -72,7 +144,10 @@ func BenchmarkAllocPadded(b *testing.B) {
 // ---------------------------------------------------------------------------
 
 // sink prevents dead-code elimination by the compiler.
-var sink int64
+var sink measure.Sink[int64]
+
+// sliceSink keeps the slices allocated by TestHypothesis on the heap.
+var sliceSink measure.Sink[any]
 
 // BenchmarkTraverseUnpadded measures traversal throughput over a large
 // slice of Unpadded structs to expose cache-line inefficiency.
@@ -87,7 +162,7 @@ func BenchmarkTraverseUnpadded(b *testing.B) {
 		for i := range data {
 			acc += data[i].b + int64(data[i].d)
 		}
-		sink = acc
+		sink.Set(acc)
 	}
 }
 
@@ -104,6 +179,6 @@ func BenchmarkTraversePadded(b *testing.B) {
 		for i := range data {
 			acc += data[i].b + int64(data[i].d)
 		}
-		sink = acc
+		sink.Set(acc)
 	}
 }
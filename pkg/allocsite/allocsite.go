@@ -0,0 +1,180 @@
+// Package allocsite attributes the allocations of a benchmark to the source
+// lines and runtime allocator functions (runtime.growslice,
+// runtime.concatstring2, runtime.newobject, ...) that performed them. It
+// reruns the benchmark with every allocation profiled
+// (runtime.MemProfileRate = 1) and reads the records of runtime.MemProfile,
+// so no pprof tooling is needed to answer "which line allocates?".
+package allocsite
+
+import (
+	"reflect"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// Frame is one call of an allocation stack.
+type Frame struct {
+	Func string
+	File string
+	Line int
+}
+
+// Sample is one distinct allocation stack and what was allocated through it.
+type Sample struct {
+	// Stack starts at the allocator, the outermost runtime function of the
+	// allocation (the one called from non-runtime code), and lists its
+	// callers outwards. mallocgc and the other internals below the
+	// allocator are dropped.
+	Stack  []Frame
+	Allocs int64
+	Bytes  int64
+}
+
+// Allocator returns the function name of the allocator of s.
+func (s Sample) Allocator() string {
+	if len(s.Stack) == 0 {
+		return ""
+	}
+	return s.Stack[0].Func
+}
+
+// Profile holds the allocations of Ops benchmark iterations.
+type Profile struct {
+	Ops     int
+	Samples []Sample
+}
+
+// Benchmark reruns bench under testing.Benchmark with every allocation
+// profiled and returns the allocations made by bench. Ops counts the
+// iterations of every round testing.Benchmark ran, so per-op values are
+// exact for allocations inside the benchmark loop; setup outside the loop
+// is spread over all iterations. Allocations of goroutines bench starts
+// are not attributed to it.
+//
+// Tiny allocations (pointer-free and smaller than 16 bytes) share 16-byte
+// blocks, and only the allocation opening a new block is profiled: they
+// are undercounted compared with testing.AllocsPerRun.
+func Benchmark(bench func(b *testing.B)) *Profile {
+	name := runtime.FuncForPC(reflect.ValueOf(bench).Pointer()).Name()
+
+	prev := runtime.MemProfileRate
+	runtime.MemProfileRate = 1
+	defer func() { runtime.MemProfileRate = prev }()
+
+	before := snapshot()
+	ops := 0
+	testing.Benchmark(func(b *testing.B) {
+		bench(b)
+		ops += b.N // after bench: b.Loop sets b.N to its iteration count
+	})
+	after := snapshot()
+
+	p := &Profile{Ops: ops}
+	for key, a := range after {
+		b := before[key]
+		if a.AllocObjects == b.AllocObjects {
+			continue
+		}
+		stack := trim(frames(a.Stack()))
+		// Keep the allocations made under bench, up to its frame.
+		i := slices.IndexFunc(stack, func(f Frame) bool { return f.Func == name })
+		if i < 0 {
+			continue
+		}
+		p.Samples = append(p.Samples, Sample{
+			Stack:  stack[:i+1],
+			Allocs: a.AllocObjects - b.AllocObjects,
+			Bytes:  a.AllocBytes - b.AllocBytes,
+		})
+	}
+	return p
+}
+
+// snapshot returns the current memory profile keyed by stack. The profile
+// is published by the garbage collector and may lag up to two cycles, so
+// two cycles are completed first.
+func snapshot() map[[32]uintptr]runtime.MemProfileRecord {
+	runtime.GC()
+	runtime.GC()
+	var records []runtime.MemProfileRecord
+	n, _ := runtime.MemProfile(nil, true)
+	for {
+		records = make([]runtime.MemProfileRecord, n+50)
+		var ok bool
+		if n, ok = runtime.MemProfile(records, true); ok {
+			break
+		}
+	}
+	m := make(map[[32]uintptr]runtime.MemProfileRecord, n)
+	for _, r := range records[:n] {
+		// Stacks deeper than Stack0 are truncated and may share a key.
+		if prev, ok := m[r.Stack0]; ok {
+			r.AllocObjects += prev.AllocObjects
+			r.AllocBytes += prev.AllocBytes
+		}
+		m[r.Stack0] = r
+	}
+	return m
+}
+
+// frames expands pcs, including inlined calls, innermost first.
+func frames(pcs []uintptr) []Frame {
+	var stack []Frame
+	it := runtime.CallersFrames(pcs)
+	for {
+		f, more := it.Next()
+		if f.Function != "" {
+			stack = append(stack, Frame{Func: f.Function, File: f.File, Line: f.Line})
+		}
+		if !more {
+			return stack
+		}
+	}
+}
+
+// trim drops the runtime frames below the allocator. Stacks made only of
+// runtime frames (e.g. the GC's own allocations) are dropped entirely.
+//
+// The compiler turns new(T) and &T{} of a fixed size into direct calls of
+// size-specialized mallocgc variants, which the profiler leaves out of the
+// stack or reports as the allocator. Both are reported as
+// runtime.newobject, the call they replace.
+func trim(stack []Frame) []Frame {
+	for i, f := range stack {
+		if isRuntime(f.Func) {
+			continue
+		}
+		if i == 0 {
+			return append([]Frame{{Func: newobject}}, stack...)
+		}
+		stack = stack[i-1:]
+		if strings.HasPrefix(stack[0].Func, "runtime.mallocgc") {
+			stack[0] = Frame{Func: newobject}
+		}
+		return stack
+	}
+	return nil
+}
+
+const newobject = "runtime.newobject"
+
+// isRuntime reports whether fn belongs to the runtime: package runtime,
+// the internal/runtime packages, or internal/bytealg.MakeNoZero, which is
+// implemented by the runtime.
+func isRuntime(fn string) bool {
+	pkg := funcPackage(fn)
+	return pkg == "runtime" || strings.HasPrefix(pkg, "internal/runtime/") ||
+		fn == "internal/bytealg.MakeNoZero"
+}
+
+// funcPackage returns the import path of the package of function name fn,
+// e.g. "internal/runtime/maps" for "internal/runtime/maps.(*Map).grow".
+func funcPackage(fn string) string {
+	slash := strings.LastIndexByte(fn, '/') + 1
+	if dot := strings.IndexByte(fn[slash:], '.'); dot >= 0 {
+		return fn[:slash+dot]
+	}
+	return fn
+}
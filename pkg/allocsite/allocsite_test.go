@@ -0,0 +1,123 @@
+package allocsite
+
+import (
+	"bufio"
+	"os"
+	"strings"
+	"testing"
+)
+
+var (
+	ptrSink   **int
+	sliceSink []int
+)
+
+//go:noinline
+func newPtr() **int {
+	return new(*int) // site: newobject
+}
+
+//go:noinline
+func appendSixteen(s []int) []int {
+	for i := range 16 {
+		s = append(s, i) // site: growslice
+	}
+	return s
+}
+
+// lineOf returns the line of this file carrying the comment "// site: name".
+func lineOf(t *testing.T, name string) int {
+	t.Helper()
+	f, err := os.Open("allocsite_test.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	for n := 1; sc.Scan(); n++ {
+		if strings.HasSuffix(sc.Text(), "// site: "+name) {
+			return n
+		}
+	}
+	t.Fatalf("no line marked %q", name)
+	return 0
+}
+
+func TestBenchmark(t *testing.T) {
+	p := Benchmark(func(b *testing.B) {
+		for b.Loop() {
+			ptrSink = newPtr()
+			sliceSink = appendSixteen(nil)
+		}
+	})
+	if p.Ops == 0 {
+		t.Fatal("no iterations profiled")
+	}
+	sites := p.Sites("allocsite_test.go")
+	if len(sites) != 2 {
+		t.Fatalf("sites = %+v, want newPtr and appendFour", sites)
+	}
+	// The first four elements fit the 32-byte stack buffer the compiler
+	// gives append; the slice then grows to capacity 8 and 16 on the heap.
+	want := []Site{
+		{Line: lineOf(t, "newobject"), Allocator: "runtime.newobject", Allocs: 1, Bytes: 8},
+		{Line: lineOf(t, "growslice"), Allocator: "runtime.growslice", Allocs: 2, Bytes: 64 + 128},
+	}
+	for i, w := range want {
+		if sites[i] != w {
+			t.Errorf("sites[%d] = %+v, want %+v", i, sites[i], w)
+		}
+	}
+	if got := p.AllocsPerOp("allocsite_test.go"); got != 3 {
+		t.Errorf("AllocsPerOp = %v, want 3", got)
+	}
+
+	listing, err := p.Listing("allocsite_test.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{
+		"allocsite_test.go: 3 allocs/op, 200 B/op (",
+		"  1          8    17  \treturn new(*int) // site: newobject  // ← runtime.newobject\n",
+	} {
+		if !strings.Contains(listing, s) {
+			t.Errorf("listing lacks %q:\n%s", s, listing)
+		}
+	}
+}
+
+func TestSitesVia(t *testing.T) {
+	p := &Profile{Ops: 2, Samples: []Sample{{
+		Stack: []Frame{
+			{Func: "runtime.growslice", File: "/go/src/runtime/slice.go", Line: 265},
+			{Func: "strings.(*Builder).WriteString", File: "/go/src/strings/builder.go", Line: 114},
+			{Func: "x.Concat", File: "/x/concat.go", Line: 10},
+			{Func: "x.Concat", File: "/x/concat.go", Line: 20}, // recursion: innermost counts
+		},
+		Allocs: 4,
+		Bytes:  64,
+	}}}
+	want := Site{Line: 10, Allocator: "runtime.growslice", Via: "strings.(*Builder).WriteString", Allocs: 2, Bytes: 32}
+	if got := p.Sites("/x/concat.go"); len(got) != 1 || got[0] != want {
+		t.Errorf("Sites = %+v, want [%+v]", got, want)
+	}
+	if got := p.Sites("/x/other.go"); len(got) != 0 {
+		t.Errorf("Sites of an unrelated file = %+v", got)
+	}
+}
+
+func TestTrim(t *testing.T) {
+	stack := []Frame{
+		{Func: "runtime.mallocgc"},
+		{Func: "runtime.newarray"},
+		{Func: "internal/runtime/maps.newarray"},
+		{Func: "runtime.mapassign_faststr"},
+		{Func: "x.Fill"},
+	}
+	if got := trim(stack); len(got) != 2 || got[0].Func != "runtime.mapassign_faststr" {
+		t.Errorf("trim = %+v, want the stack from runtime.mapassign_faststr", got)
+	}
+	if got := trim(stack[:4]); got != nil {
+		t.Errorf("trim of a runtime-only stack = %+v, want nil", got)
+	}
+}
@@ -0,0 +1,146 @@
+package allocsite
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Site is the allocations attributed to one source line through one
+// allocator.
+type Site struct {
+	Line      int
+	Allocator string // e.g. "runtime.growslice"
+	// Via lists the calls between the line and the allocator, outermost
+	// first, e.g. "strings.(*Builder).WriteString".
+	Via    string
+	Allocs float64 // per op
+	Bytes  float64 // per op
+}
+
+// Sites attributes the allocations of p to the lines of file: every sample
+// counts for the innermost frame of its stack in file. Samples that never
+// pass through file are left out. Sites are ordered by line and, within a
+// line, by decreasing allocations.
+func (p *Profile) Sites(file string) []Site {
+	file = cleanPath(file)
+	type key struct {
+		line           int
+		allocator, via string
+	}
+	sums := map[key]*Site{}
+	for _, s := range p.Samples {
+		i := slices.IndexFunc(s.Stack, func(f Frame) bool { return cleanPath(f.File) == file })
+		if i < 0 {
+			continue
+		}
+		var via []string
+		for j := i - 1; j > 0; j-- {
+			via = append(via, s.Stack[j].Func)
+		}
+		k := key{s.Stack[i].Line, s.Allocator(), strings.Join(via, " → ")}
+		site, ok := sums[k]
+		if !ok {
+			site = &Site{Line: k.line, Allocator: k.allocator, Via: k.via}
+			sums[k] = site
+		}
+		site.Allocs += float64(s.Allocs)
+		site.Bytes += float64(s.Bytes)
+	}
+	sites := make([]Site, 0, len(sums))
+	for _, s := range sums {
+		if p.Ops > 0 {
+			s.Allocs /= float64(p.Ops)
+			s.Bytes /= float64(p.Ops)
+		}
+		sites = append(sites, *s)
+	}
+	slices.SortFunc(sites, func(a, b Site) int {
+		return cmp.Or(cmp.Compare(a.Line, b.Line), cmp.Compare(b.Allocs, a.Allocs),
+			cmp.Compare(a.Allocator, b.Allocator), cmp.Compare(a.Via, b.Via))
+	})
+	return sites
+}
+
+// AllocsPerOp returns the allocations per op attributed to file.
+func (p *Profile) AllocsPerOp(file string) float64 {
+	var n float64
+	for _, s := range p.Sites(file) {
+		n += s.Allocs
+	}
+	return n
+}
+
+// Listing renders file as an annotated source listing: every line that
+// allocates is prefixed with its allocations and bytes per op and
+// followed by the allocator it reached, e.g.
+//
+//	allocs/op       B/op
+//	       63      16632    10  		s += p  // ← runtime.concatstring2
+func (p *Profile) Listing(file string) (string, error) {
+	src, err := os.ReadFile(file)
+	if err != nil {
+		return "", fmt.Errorf("allocsite: %w", err)
+	}
+	byLine := map[int][]Site{}
+	var total Site
+	for _, s := range p.Sites(file) {
+		byLine[s.Line] = append(byLine[s.Line], s)
+		total.Allocs += s.Allocs
+		total.Bytes += s.Bytes
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s: %s allocs/op, %s B/op (%d ops)\n",
+		filepath.Base(file), formatPerOp(total.Allocs), formatPerOp(total.Bytes), p.Ops)
+	fmt.Fprintf(&sb, "%10s %10s\n", "allocs/op", "B/op")
+	sc := bufio.NewScanner(bytes.NewReader(src))
+	for n := 1; sc.Scan(); n++ {
+		sites := byLine[n]
+		if len(sites) == 0 {
+			fmt.Fprintf(&sb, "%10s %10s %5d  %s\n", "", "", n, sc.Text())
+			continue
+		}
+		var allocs, size float64
+		notes := make([]string, len(sites))
+		for i, s := range sites {
+			allocs += s.Allocs
+			size += s.Bytes
+			notes[i] = s.Allocator
+			if s.Via != "" {
+				notes[i] += " via " + s.Via
+			}
+			if len(sites) > 1 {
+				notes[i] += " (" + formatPerOp(s.Allocs) + ")"
+			}
+		}
+		fmt.Fprintf(&sb, "%10s %10s %5d  %s  // ← %s\n",
+			formatPerOp(allocs), formatPerOp(size), n, sc.Text(), strings.Join(notes, "; "))
+	}
+	if err := sc.Err(); err != nil {
+		return "", fmt.Errorf("allocsite: %s: %w", file, err)
+	}
+	return sb.String(), nil
+}
+
+// formatPerOp formats a per-op value rounded to three decimals.
+func formatPerOp(v float64) string {
+	return strconv.FormatFloat(math.Round(v*1000)/1000, 'f', -1, 64)
+}
+
+// cleanPath returns the absolute, slash-separated form of path so that
+// paths given by the caller compare equal to those recorded by the
+// compiler.
+func cleanPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	return filepath.ToSlash(path)
+}
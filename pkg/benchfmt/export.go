@@ -0,0 +1,83 @@
+package benchfmt
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// WriteJSON writes results as an indented JSON array.
+func WriteJSON(w io.Writer, results []Result) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if results == nil {
+		results = []Result{}
+	}
+	if err := enc.Encode(results); err != nil {
+		return fmt.Errorf("benchfmt: encode json: %w", err)
+	}
+	return nil
+}
+
+// WriteCSV writes results in long ("tidy") form: one row per sample and
+// unit, with one column per axis key, so that the output can be pivoted by
+// pattern, size and unit in any spreadsheet or dataframe tool.
+//
+// Columns: pkg, benchmark, <axis keys...>, gomaxprocs, sample, iterations, unit, value.
+// Positional sub-benchmark parts are named part1, part2, ... by position.
+func WriteCSV(w io.Writer, results []Result) error {
+	var keys []string
+	seen := map[string]bool{}
+	for _, r := range results {
+		for i, a := range r.Name.Axes {
+			k := columnKey(a, i)
+			if k == ProcsKey || seen[k] {
+				continue
+			}
+			seen[k] = true
+			keys = append(keys, k)
+		}
+	}
+
+	cw := csv.NewWriter(w)
+	header := append([]string{"pkg", "benchmark"}, keys...)
+	header = append(header, ProcsKey, "sample", "iterations", "unit", "value")
+	if err := cw.Write(header); err != nil {
+		return fmt.Errorf("benchfmt: write csv: %w", err)
+	}
+	for _, r := range results {
+		axes := make(map[string]string, len(r.Name.Axes))
+		for i, a := range r.Name.Axes {
+			axes[columnKey(a, i)] = a.Value
+		}
+		for _, v := range r.Values {
+			row := []string{r.Pkg(), r.Name.Base}
+			for _, k := range keys {
+				row = append(row, axes[k])
+			}
+			row = append(row,
+				strconv.Itoa(r.Name.Procs),
+				strconv.Itoa(r.Sample),
+				strconv.FormatInt(r.Iterations, 10),
+				v.Unit,
+				strconv.FormatFloat(v.Value, 'g', -1, 64))
+			if err := cw.Write(row); err != nil {
+				return fmt.Errorf("benchfmt: write csv: %w", err)
+			}
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("benchfmt: write csv: %w", err)
+	}
+	return nil
+}
+
+func columnKey(a Axis, i int) string {
+	if a.Key == "" {
+		return "part" + strconv.Itoa(i+1)
+	}
+	return a.Key
+}
@@ -0,0 +1,104 @@
+package benchfmt
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ProcsKey is the axis key assigned to the GOMAXPROCS name suffix.
+const ProcsKey = "gomaxprocs"
+
+// Axis is one dimension encoded in a benchmark name, e.g. "N=64".
+// Positional sub-benchmark parts without "=" have an empty Key.
+type Axis struct {
+	Key   string   `json:"key"`
+	Value string   `json:"value"`
+	Num   *float64 `json:"num,omitempty"` // set when Value is numeric
+}
+
+// Name is a benchmark name split into its base and axes.
+type Name struct {
+	Base  string `json:"base"`  // e.g. "ConcatPlus" for "BenchmarkConcatPlus/N=64-8"
+	Axes  []Axis `json:"axes"`  // sub-benchmark axes followed by the gomaxprocs axis
+	Procs int    `json:"procs"` // GOMAXPROCS; go test omits the suffix when it is 1
+}
+
+// ParseName splits a full benchmark name such as
+// "BenchmarkBytesToStringAssign/N=4096-8" into its base name "BytesToStringAssign",
+// the axis N=4096 and the GOMAXPROCS axis gomaxprocs=8.
+func ParseName(full string) Name {
+	full = strings.TrimPrefix(full, "Benchmark")
+	procs := 1
+	if i := strings.LastIndexByte(full, '-'); i > 0 {
+		if n, err := strconv.Atoi(full[i+1:]); err == nil && n > 0 {
+			procs = n
+			full = full[:i]
+		}
+	}
+	parts := strings.Split(full, "/")
+	n := Name{Base: parts[0], Procs: procs}
+	for _, p := range parts[1:] {
+		var a Axis
+		if k, v, ok := strings.Cut(p, "="); ok {
+			a = newAxis(k, v)
+		} else {
+			a = newAxis("", p)
+		}
+		n.Axes = append(n.Axes, a)
+	}
+	n.Axes = append(n.Axes, newAxis(ProcsKey, strconv.Itoa(procs)))
+	return n
+}
+
+func newAxis(key, value string) Axis {
+	a := Axis{Key: key, Value: value}
+	if f, err := strconv.ParseFloat(value, 64); err == nil {
+		a.Num = &f
+	}
+	return a
+}
+
+// Get returns the value of the axis named key.
+func (n Name) Get(key string) (Axis, bool) {
+	for _, a := range n.Axes {
+		if a.Key == key {
+			return a, true
+		}
+	}
+	return Axis{}, false
+}
+
+// Float returns the numeric value of the axis named key.
+func (n Name) Float(key string) (float64, bool) {
+	a, ok := n.Get(key)
+	if !ok || a.Num == nil {
+		return 0, false
+	}
+	return *a.Num, true
+}
+
+// Group returns the name without the "Benchmark" prefix and without the axes
+// listed in drop, e.g. Group("N") of "BenchmarkConcatPlus/N=64-8" is
+// "ConcatPlus-8" and Group("N", ProcsKey) is "ConcatPlus".
+// It is the key for pivoting samples along the dropped axes.
+func (n Name) Group(drop ...string) string {
+	var sb strings.Builder
+	sb.WriteString(n.Base)
+	for _, a := range n.Axes {
+		if a.Key == ProcsKey || slices.Contains(drop, a.Key) {
+			continue
+		}
+		sb.WriteByte('/')
+		if a.Key != "" {
+			sb.WriteString(a.Key)
+			sb.WriteByte('=')
+		}
+		sb.WriteString(a.Value)
+	}
+	if n.Procs != 1 && !slices.Contains(drop, ProcsKey) {
+		sb.WriteByte('-')
+		sb.WriteString(strconv.Itoa(n.Procs))
+	}
+	return sb.String()
+}
@@ -0,0 +1,64 @@
+package benchfmt
+
+import "testing"
+
+func TestParseName(t *testing.T) {
+	tests := []struct {
+		full  string
+		base  string
+		procs int
+		axes  map[string]string
+	}{
+		{"BenchmarkConcatPlus/N=64-8", "ConcatPlus", 8, map[string]string{"N": "64", ProcsKey: "8"}},
+		{"BenchmarkBytesToStringAssign/N=4096", "BytesToStringAssign", 1, map[string]string{"N": "4096", ProcsKey: "1"}},
+		{"BenchmarkSpawnBuffered-16", "SpawnBuffered", 16, map[string]string{ProcsKey: "16"}},
+		{"BenchmarkInsert_StringKey", "Insert_StringKey", 1, map[string]string{ProcsKey: "1"}},
+		{"BenchmarkX/small/N=-3-4", "X", 4, map[string]string{"": "small", "N": "-3", ProcsKey: "4"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.full, func(t *testing.T) {
+			n := ParseName(tt.full)
+			if n.Base != tt.base || n.Procs != tt.procs {
+				t.Errorf("ParseName = {%s %d}, want {%s %d}", n.Base, n.Procs, tt.base, tt.procs)
+			}
+			if len(n.Axes) != len(tt.axes) {
+				t.Fatalf("axes = %+v, want %v", n.Axes, tt.axes)
+			}
+			for _, a := range n.Axes {
+				if want, ok := tt.axes[a.Key]; !ok || want != a.Value {
+					t.Errorf("axis %q = %q, want %q", a.Key, a.Value, want)
+				}
+			}
+		})
+	}
+}
+
+func TestNameFloat(t *testing.T) {
+	n := ParseName("BenchmarkConcatPlus/N=64/kind=ascii-8")
+	if v, ok := n.Float("N"); !ok || v != 64 {
+		t.Errorf("Float(N) = %v, %v; want 64, true", v, ok)
+	}
+	if _, ok := n.Float("kind"); ok {
+		t.Error("Float(kind) must not be numeric")
+	}
+	if _, ok := n.Float("missing"); ok {
+		t.Error("Float(missing) must report false")
+	}
+}
+
+func TestNameGroup(t *testing.T) {
+	n := ParseName("BenchmarkConcatPlus/N=64/kind=ascii-8")
+	tests := []struct {
+		drop []string
+		want string
+	}{
+		{nil, "ConcatPlus/N=64/kind=ascii-8"},
+		{[]string{"N"}, "ConcatPlus/kind=ascii-8"},
+		{[]string{"N", ProcsKey}, "ConcatPlus/kind=ascii"},
+	}
+	for _, tt := range tests {
+		if got := n.Group(tt.drop...); got != tt.want {
+			t.Errorf("Group(%q) = %q, want %q", tt.drop, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package benchfmt
+
+import "strings"
+
+// Pair holds the samples of two benchmarks that share every axis but the
+// base name, e.g. ConcatPlus/N=64 and ConcatBuilder/N=64.
+type Pair struct {
+	Key  string    // shared axes, e.g. "N=64"; empty when there are none
+	A, B []float64 // samples of unit for each base name
+}
+
+// Pairs matches the results of base names a and b ("Benchmark" prefix
+// optional) on their remaining axes and returns the samples of unit for
+// every key present in both, in order of first appearance.
+func Pairs(results []Result, unit, a, b string) []Pair {
+	a = strings.TrimPrefix(a, "Benchmark")
+	b = strings.TrimPrefix(b, "Benchmark")
+	var (
+		keys []string
+		seen = map[string]bool{}
+		byA  = map[string][]float64{}
+		byB  = map[string][]float64{}
+	)
+	for _, r := range results {
+		v, ok := r.Get(unit)
+		if !ok {
+			continue
+		}
+		key := strings.TrimPrefix(strings.TrimPrefix(r.Name.Group(), r.Name.Base), "/")
+		switch r.Name.Base {
+		case a:
+			byA[key] = append(byA[key], v)
+		case b:
+			byB[key] = append(byB[key], v)
+		default:
+			continue
+		}
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	var pairs []Pair
+	for _, k := range keys {
+		if len(byA[k]) > 0 && len(byB[k]) > 0 {
+			pairs = append(pairs, Pair{Key: k, A: byA[k], B: byB[k]})
+		}
+	}
+	return pairs
+}
@@ -0,0 +1,159 @@
+// Package benchfmt parses the standard go test -bench text format into
+// structured results whose names are split into a base name and typed
+// key=value axes, so that samples can be pivoted by pattern and size.
+package benchfmt
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"maps"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Value is one measurement of a result line, e.g. 9105 ns/op.
+// Units reported through b.ReportMetric are kept verbatim.
+type Value struct {
+	Value float64 `json:"value"`
+	Unit  string  `json:"unit"`
+}
+
+// Result is one benchmark result line. Every -count repetition produces
+// its own Result; Sample numbers the repetitions of the same full name.
+type Result struct {
+	Config     map[string]string `json:"config"` // goos, goarch, pkg, cpu, ... in effect
+	FullName   string            `json:"full_name"`
+	Name       Name              `json:"name"`
+	Sample     int               `json:"sample"`
+	Iterations int64             `json:"iterations"`
+	Values     []Value           `json:"values"`
+}
+
+// Get returns the value reported in unit.
+func (r Result) Get(unit string) (float64, bool) {
+	for _, v := range r.Values {
+		if v.Unit == unit {
+			return v.Value, true
+		}
+	}
+	return 0, false
+}
+
+// Pkg returns the package the result belongs to.
+func (r Result) Pkg() string { return r.Config["pkg"] }
+
+// Parse reads go test -bench output and returns its result lines in order.
+// Lines that are neither configuration nor results (test logs, PASS, ok)
+// are ignored, so -v output can be parsed directly.
+func Parse(r io.Reader) ([]Result, error) {
+	var (
+		results []Result
+		config  = map[string]string{}
+		shared  map[string]string // config snapshot shared by results until it changes
+		samples = map[string]int{}
+	)
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	for sc.Scan() {
+		line := sc.Text()
+		if k, v, ok := parseConfig(line); ok {
+			if k == "pkg" {
+				// A new package starts a new configuration block.
+				config = map[string]string{"goos": config["goos"], "goarch": config["goarch"], "cpu": config["cpu"]}
+			}
+			config[k] = v
+			shared = nil
+			continue
+		}
+		res, ok := parseResult(line)
+		if !ok {
+			continue
+		}
+		if shared == nil {
+			shared = maps.Clone(config)
+		}
+		res.Config = shared
+		key := shared["pkg"] + "\x00" + res.FullName
+		res.Sample = samples[key]
+		samples[key]++
+		results = append(results, res)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("benchfmt: %w", err)
+	}
+	return results, nil
+}
+
+// Extract returns only the benchmark-format lines of go test output
+// (configuration lines and result lines), dropping test logs and status
+// lines. The result is what benchstat-style tools expect as input.
+func Extract(r io.Reader) (string, error) {
+	var sb strings.Builder
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	for sc.Scan() {
+		line := sc.Text()
+		if _, _, ok := parseConfig(line); ok {
+			sb.WriteString(line)
+			sb.WriteByte('\n')
+			continue
+		}
+		if _, ok := parseResult(line); ok {
+			sb.WriteString(line)
+			sb.WriteByte('\n')
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return "", fmt.Errorf("benchfmt: %w", err)
+	}
+	return sb.String(), nil
+}
+
+// parseConfig parses a "key: value" configuration line such as "goos: linux".
+// Keys start with a lower-case letter and contain no spaces.
+func parseConfig(line string) (string, string, bool) {
+	k, v, ok := strings.Cut(line, ":")
+	if !ok || k == "" || !unicode.IsLower(rune(k[0])) || strings.ContainsAny(k, " \t") {
+		return "", "", false
+	}
+	if v != "" && v[0] != ' ' {
+		return "", "", false
+	}
+	return k, strings.TrimSpace(v), true
+}
+
+// parseResult parses "BenchmarkName-8  1000  123 ns/op  16 B/op ...".
+func parseResult(line string) (Result, bool) {
+	fields := strings.Fields(line)
+	if len(fields) < 4 || len(fields)%2 != 0 || !strings.HasPrefix(fields[0], "Benchmark") {
+		return Result{}, false
+	}
+	n, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		return Result{}, false
+	}
+	res := Result{FullName: fields[0], Name: ParseName(fields[0]), Iterations: n}
+	for i := 2; i+1 < len(fields); i += 2 {
+		v, err := strconv.ParseFloat(fields[i], 64)
+		if err != nil {
+			return Result{}, false
+		}
+		res.Values = append(res.Values, Value{Value: v, Unit: fields[i+1]})
+	}
+	return res, true
+}
+
+// Samples groups the values reported in unit by Name.Group(drop...),
+// keeping every -count sample in input order.
+func Samples(results []Result, unit string, drop ...string) map[string][]float64 {
+	out := make(map[string][]float64)
+	for _, r := range results {
+		if v, ok := r.Get(unit); ok {
+			k := r.Name.Group(drop...)
+			out[k] = append(out[k], v)
+		}
+	}
+	return out
+}
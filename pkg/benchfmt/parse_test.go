@@ -0,0 +1,151 @@
+package benchfmt
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const sample = `=== RUN   TestHypothesis
+    concat_test.go:40: Result: result:verified
+--- PASS: TestHypothesis (0.02s)
+goos: linux
+goarch: amd64
+pkg: go-lab/experiments/string-concat
+cpu: Intel(R) Xeon(R) Processor
+BenchmarkConcatPlus
+BenchmarkConcatPlus/N=2
+BenchmarkConcatPlus/N=2-8         	 1000000	        43.10 ns/op	      16 B/op	       1 allocs/op
+BenchmarkConcatPlus/N=2-8         	 1000000	        44.90 ns/op	      16 B/op	       1 allocs/op
+BenchmarkConcatPlus/N=64-8        	  129157	      9105 ns/op	   17128 B/op	      63 allocs/op
+PASS
+ok  	go-lab/experiments/string-concat	3.2s
+goos: linux
+goarch: amd64
+pkg: go-lab/experiments/goroutine-cost
+cpu: Intel(R) Xeon(R) Processor
+BenchmarkSpawnBuffered-8          	 3000000	       412.5 ns/op	        1.000 park/op
+PASS
+`
+
+func parseSample(t *testing.T) []Result {
+	t.Helper()
+	rs, err := Parse(strings.NewReader(sample))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rs
+}
+
+func TestParse(t *testing.T) {
+	rs := parseSample(t)
+	if len(rs) != 4 {
+		t.Fatalf("got %d results, want 4", len(rs))
+	}
+
+	if rs[0].Sample != 0 || rs[1].Sample != 1 || rs[2].Sample != 0 {
+		t.Errorf("samples = %d %d %d, want 0 1 0", rs[0].Sample, rs[1].Sample, rs[2].Sample)
+	}
+	if got := rs[2].Pkg(); got != "go-lab/experiments/string-concat" {
+		t.Errorf("pkg = %q", got)
+	}
+	if v, ok := rs[2].Get("allocs/op"); !ok || v != 63 {
+		t.Errorf("allocs/op = %v, %v; want 63", v, ok)
+	}
+	if rs[2].Config["cpu"] != "Intel(R) Xeon(R) Processor" {
+		t.Errorf("cpu = %q", rs[2].Config["cpu"])
+	}
+
+	last := rs[3]
+	if last.Pkg() != "go-lab/experiments/goroutine-cost" || last.Config["goos"] != "linux" {
+		t.Errorf("config of second package = %v", last.Config)
+	}
+	if v, ok := last.Get("park/op"); !ok || v != 1 {
+		t.Errorf("custom metric park/op = %v, %v; want 1", v, ok)
+	}
+}
+
+func TestSamples(t *testing.T) {
+	got := Samples(parseSample(t), "ns/op", ProcsKey)
+	if s := got["ConcatPlus/N=2"]; len(s) != 2 || s[0] != 43.10 || s[1] != 44.90 {
+		t.Errorf("ConcatPlus/N=2 = %v, want [43.1 44.9]", s)
+	}
+	byPattern := Samples(parseSample(t), "ns/op", "N", ProcsKey)
+	if s := byPattern["ConcatPlus"]; len(s) != 3 {
+		t.Errorf("ConcatPlus over N = %v, want 3 samples", s)
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, parseSample(t)[2:]); err != nil {
+		t.Fatal(err)
+	}
+	want := `pkg,benchmark,N,gomaxprocs,sample,iterations,unit,value
+go-lab/experiments/string-concat,ConcatPlus,64,8,0,129157,ns/op,9105
+go-lab/experiments/string-concat,ConcatPlus,64,8,0,129157,B/op,17128
+go-lab/experiments/string-concat,ConcatPlus,64,8,0,129157,allocs/op,63
+go-lab/experiments/goroutine-cost,SpawnBuffered,,8,0,3000000,ns/op,412.5
+go-lab/experiments/goroutine-cost,SpawnBuffered,,8,0,3000000,park/op,1
+`
+	if got := buf.String(); got != want {
+		t.Errorf("WriteCSV =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestWriteJSONRoundTrip(t *testing.T) {
+	rs := parseSample(t)
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, rs); err != nil {
+		t.Fatal(err)
+	}
+	var back []Result
+	if err := json.Unmarshal(buf.Bytes(), &back); err != nil {
+		t.Fatal(err)
+	}
+	if len(back) != len(rs) || back[2].Name.Base != "ConcatPlus" {
+		t.Fatalf("round trip lost data: %+v", back)
+	}
+	if n, ok := back[2].Name.Float("N"); !ok || n != 64 {
+		t.Errorf("N after round trip = %v, %v", n, ok)
+	}
+}
+
+func TestPairs(t *testing.T) {
+	rs, err := Parse(strings.NewReader(`pkg: go-lab/experiments/string-concat
+BenchmarkConcatPlus/N=2-8      	 1000	  43 ns/op
+BenchmarkConcatPlus/N=64-8     	 1000	 9105 ns/op
+BenchmarkConcatBuilder/N=64-8  	 1000	 1222 ns/op
+BenchmarkConcatPlus/N=64-8     	 1000	 9200 ns/op
+BenchmarkConcatBuilder/N=64-8  	 1000	 1230 ns/op
+BenchmarkConcatBuilder/N=4-8   	 1000	  50 ns/op
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ps := Pairs(rs, "ns/op", "BenchmarkConcatPlus", "ConcatBuilder")
+	if len(ps) != 1 {
+		t.Fatalf("Pairs = %+v, want only N=64", ps)
+	}
+	p := ps[0]
+	if p.Key != "N=64-8" || len(p.A) != 2 || len(p.B) != 2 || p.A[1] != 9200 || p.B[0] != 1222 {
+		t.Errorf("pair = %+v", p)
+	}
+}
+
+func TestExtract(t *testing.T) {
+	got, err := Extract(strings.NewReader(sample))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(got, "RUN") || strings.Contains(got, "PASS") || strings.Contains(got, "concat_test.go") {
+		t.Errorf("Extract kept test output:\n%s", got)
+	}
+	if n := strings.Count(got, "\nBenchmark"); n != 4 {
+		t.Errorf("Extract kept %d result lines, want 4:\n%s", n, got)
+	}
+	if !strings.HasPrefix(got, "goos: linux\n") {
+		t.Errorf("Extract dropped configuration:\n%s", got)
+	}
+}
@@ -0,0 +1,78 @@
+package chart
+
+import (
+	"fmt"
+	"math"
+	"slices"
+)
+
+// BarSeries is one bar per category of a Bars chart, e.g. pattern A.
+// Values are in the order of the categories; a NaN Y leaves a gap.
+type BarSeries struct {
+	Name   string
+	Values []Value
+}
+
+// Bars is a grouped bar chart: one group of bars per category, one bar per
+// series, for comparisons such as value vs pointer receivers across
+// struct sizes.
+type Bars struct {
+	Title, YLabel string
+	LogY          bool
+	Categories    []string
+	Series        []BarSeries
+}
+
+// SVG renders the chart. On a log axis, bars start at the bottom of the
+// axis and non-positive values are left out.
+func (b *Bars) SVG() []byte {
+	var values []Value
+	for _, s := range b.Series {
+		for _, v := range s.Values {
+			if !math.IsNaN(v.Y) {
+				values = append(values, v)
+			}
+		}
+	}
+	c := newCanvas(b.Title)
+	y, yTicks := yAxis(values, b.LogY)
+	c.axes(y, yTicks, "", b.YLabel)
+
+	left := float64(marginLeft)
+	bottom := float64(height - marginBottom)
+	base := bottom
+	if !b.LogY {
+		base = y.pos(0)
+	}
+	band := float64(width-marginRight-marginLeft) / float64(max(len(b.Categories), 1))
+	bar := band * 0.8 / float64(max(len(b.Series), 1))
+	// Slant the labels when one is wider than its band, so they do not overlap.
+	slant := slices.ContainsFunc(b.Categories, func(cat string) bool { return float64(len(cat))*charWidth > band })
+	names := make([]string, len(b.Series))
+	for i, cat := range b.Categories {
+		start := left + float64(i)*band + band*0.1
+		if x := start + band*0.4; slant {
+			c.text(x, bottom+14, "end", fmt.Sprintf(`transform="rotate(-30 %.1f %.1f)"`, x, bottom+14), cat)
+		} else {
+			c.text(x, bottom+18, "middle", "", cat)
+		}
+		for j, s := range b.Series {
+			names[j] = s.Name
+			if i >= len(s.Values) {
+				continue
+			}
+			v := s.Values[i]
+			if math.IsNaN(v.Y) || (b.LogY && !validLog(v.Y)) {
+				continue
+			}
+			x, top := start+float64(j)*bar, y.pos(v.Y)
+			fmt.Fprintf(c, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s"/>`+"\n",
+				x, min(top, base), bar-1, math.Abs(base-top), color(j))
+			if !b.LogY || validLog(v.Lo) {
+				c.errorBar(x+bar/2, y.pos(v.Lo), y.pos(v.Hi), "black")
+			}
+		}
+	}
+	c.legend(names)
+	return c.close()
+}
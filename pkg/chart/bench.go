@@ -0,0 +1,103 @@
+package chart
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"strings"
+
+	"go-lab/pkg/benchfmt"
+	"go-lab/pkg/stats"
+)
+
+// Summarize returns the median of the -count samples xs with the 95%
+// confidence interval of the median as error bar (the full range when
+// there are too few samples to reach 95%).
+func Summarize(xs []float64) Value {
+	s := stats.Summarize(xs, stats.DefaultConfidence)
+	return Value{Y: s.Median, Lo: s.Lo, Hi: s.Hi}
+}
+
+// Sweep charts the unit values of results along the numeric axis key (e.g.
+// "N"), one series per benchmark group with key dropped (e.g.
+// "ConcatPlus-8"), in first-seen order. It returns nil when no result has
+// the axis.
+func Sweep(results []benchfmt.Result, unit, key string) *Line {
+	var names []string
+	samples := map[string]map[float64][]float64{}
+	for _, r := range results {
+		x, ok := r.Name.Float(key)
+		if !ok {
+			continue
+		}
+		v, ok := r.Get(unit)
+		if !ok {
+			continue
+		}
+		g := r.Name.Group(key)
+		if samples[g] == nil {
+			names = append(names, g)
+			samples[g] = map[float64][]float64{}
+		}
+		samples[g][x] = append(samples[g][x], v)
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	l := &Line{XLabel: key, YLabel: unit}
+	for _, g := range names {
+		s := Series{Name: g}
+		for x, xs := range samples[g] {
+			s.Points = append(s.Points, Point{X: x, Value: Summarize(xs)})
+		}
+		slices.SortFunc(s.Points, func(a, b Point) int { return cmp.Compare(a.X, b.X) })
+		l.Series = append(l.Series, s)
+	}
+	return l
+}
+
+// Compare charts the unit values of results as grouped bars: a benchmark
+// group belongs to the first series whose word it contains, and its
+// category is the group without that word, so series "Value" and
+// "Pointer" turn SmallValue and SmallPointer into the bars of category
+// Small. Groups containing none of the words are left out. It returns nil
+// when no group matches.
+func Compare(results []benchfmt.Result, unit string, series []string) *Bars {
+	samples := benchfmt.Samples(results, unit)
+	var groups []string
+	for _, r := range results {
+		if g := r.Name.Group(); samples[g] != nil && !slices.Contains(groups, g) {
+			groups = append(groups, g)
+		}
+	}
+	b := &Bars{YLabel: unit}
+	cells := map[[2]string]Value{}
+	for _, g := range groups {
+		for _, word := range series {
+			if !strings.Contains(g, word) {
+				continue
+			}
+			cat := strings.Replace(g, word, "", 1)
+			if !slices.Contains(b.Categories, cat) {
+				b.Categories = append(b.Categories, cat)
+			}
+			cells[[2]string{word, cat}] = Summarize(samples[g])
+			break
+		}
+	}
+	if len(b.Categories) == 0 {
+		return nil
+	}
+	for _, word := range series {
+		s := BarSeries{Name: word}
+		for _, cat := range b.Categories {
+			v, ok := cells[[2]string{word, cat}]
+			if !ok {
+				v = Value{Y: math.NaN()}
+			}
+			s.Values = append(s.Values, v)
+		}
+		b.Series = append(b.Series, s)
+	}
+	return b
+}
@@ -0,0 +1,225 @@
+// Package chart renders benchmark results as self-contained SVG files using
+// the standard library only: line charts of a sweep over a parameter axis
+// (one series per pattern, optional log scales, error bars from -count
+// samples) and grouped bar charts for A/B comparisons. The files can be
+// embedded in issue results next to the tables they illustrate.
+package chart
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"math"
+	"slices"
+	"strconv"
+)
+
+// Chart dimensions in SVG user units.
+const (
+	width        = 720
+	height       = 420
+	marginLeft   = 80
+	marginRight  = 170 // room for the legend
+	marginTop    = 40
+	marginBottom = 60
+	charWidth    = 7 // approximate width of a label character
+)
+
+// palette holds the series colors, cycled when there are more series.
+var palette = []string{"#1f77b4", "#d62728", "#2ca02c", "#ff7f0e", "#9467bd", "#8c564b", "#e377c2", "#7f7f7f"}
+
+func color(i int) string { return palette[i%len(palette)] }
+
+// Value is a measured value with its error bar: Lo ≤ Y ≤ Hi. Lo and Hi
+// equal Y when there is no spread to show.
+type Value struct {
+	Y, Lo, Hi float64
+}
+
+// scale maps data values to pixel positions.
+type scale struct {
+	lo, hi   float64 // data range; logarithms for a log scale
+	log      bool
+	from, to float64 // pixel range
+}
+
+func newScale(lo, hi float64, log bool, from, to float64) scale {
+	if log {
+		lo, hi = math.Log10(lo), math.Log10(hi)
+	}
+	if hi == lo {
+		lo, hi = lo-1, hi+1
+	}
+	return scale{lo: lo, hi: hi, log: log, from: from, to: to}
+}
+
+// pos returns the pixel position of v; v must be positive on a log scale.
+func (s scale) pos(v float64) float64 {
+	if s.log {
+		v = math.Log10(v)
+	}
+	return s.from + (v-s.lo)/(s.hi-s.lo)*(s.to-s.from)
+}
+
+// niceTicks returns about n round tick values (1, 2 or 5 × 10^k steps)
+// covering [lo, hi].
+func niceTicks(lo, hi float64, n int) []float64 {
+	if hi <= lo {
+		return []float64{lo}
+	}
+	raw := (hi - lo) / float64(n)
+	mag := math.Pow(10, math.Floor(math.Log10(raw)))
+	var step float64
+	switch norm := raw / mag; {
+	case norm < 1.5:
+		step = mag
+	case norm < 3:
+		step = 2 * mag
+	case norm < 7:
+		step = 5 * mag
+	default:
+		step = 10 * mag
+	}
+	var ticks []float64
+	for v := math.Ceil(lo/step) * step; v <= hi+step*1e-9; v += step {
+		ticks = append(ticks, math.Round(v/step)*step)
+	}
+	return ticks
+}
+
+// logTicks returns the powers of ten in [lo, hi], completed with their 2
+// and 5 multiples when there are fewer than three.
+func logTicks(lo, hi float64) []float64 {
+	var ticks []float64
+	mults := []float64{1}
+	if math.Floor(math.Log10(hi))-math.Ceil(math.Log10(lo)) < 2 {
+		mults = []float64{1, 2, 5}
+	}
+	for e := math.Floor(math.Log10(lo)); e <= math.Ceil(math.Log10(hi)); e++ {
+		for _, m := range mults {
+			if v := m * math.Pow(10, e); v >= lo*(1-1e-9) && v <= hi*(1+1e-9) {
+				ticks = append(ticks, v)
+			}
+		}
+	}
+	return ticks
+}
+
+// formatTick formats an axis label compactly.
+func formatTick(v float64) string {
+	switch a := math.Abs(v); {
+	case a >= 1e6 || (a > 0 && a < 1e-3):
+		return strconv.FormatFloat(v, 'g', 3, 64)
+	default:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+}
+
+// canvas accumulates SVG elements.
+type canvas struct {
+	bytes.Buffer
+}
+
+func newCanvas(title string) *canvas {
+	c := &canvas{}
+	fmt.Fprintf(c, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="12">`+"\n",
+		width, height, width, height)
+	fmt.Fprintf(c, `<rect width="%d" height="%d" fill="white"/>`+"\n", width, height)
+	c.text(width/2, 22, "middle", "font-size=\"15\" font-weight=\"bold\"", title)
+	return c
+}
+
+func (c *canvas) line(x1, y1, x2, y2 float64, stroke string, extra string) {
+	fmt.Fprintf(c, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="%s"%s/>`+"\n", x1, y1, x2, y2, stroke, attr(extra))
+}
+
+func (c *canvas) text(x, y float64, anchor, extra, s string) {
+	fmt.Fprintf(c, `<text x="%.1f" y="%.1f" text-anchor="%s"%s>%s</text>`+"\n", x, y, anchor, attr(extra), html.EscapeString(s))
+}
+
+func attr(extra string) string {
+	if extra == "" {
+		return ""
+	}
+	return " " + extra
+}
+
+// errorBar draws a vertical error bar with caps at x from lo to hi pixels.
+func (c *canvas) errorBar(x, lo, hi float64, stroke string) {
+	if math.Abs(hi-lo) < 0.5 {
+		return
+	}
+	fmt.Fprintf(c, `<path class="error" d="M%.1f %.1fV%.1fM%.1f %.1fh8M%.1f %.1fh8" stroke="%s" fill="none"/>`+"\n",
+		x, lo, hi, x-4, lo, x-4, hi, stroke)
+}
+
+// axes draws the plot frame, the y axis ticks and grid lines, and the
+// axis labels.
+func (c *canvas) axes(y scale, yTicks []float64, xLabel, yLabel string) {
+	left, right := float64(marginLeft), float64(width-marginRight)
+	top, bottom := float64(marginTop), float64(height-marginBottom)
+	for _, t := range yTicks {
+		p := y.pos(t)
+		c.line(left, p, right, p, "#e0e0e0", "")
+		c.text(left-6, p+4, "end", "", formatTick(t))
+	}
+	c.line(left, bottom, right, bottom, "black", "")
+	c.line(left, top, left, bottom, "black", "")
+	if xLabel != "" {
+		c.text((left+right)/2, height-15, "middle", "", xLabel)
+	}
+	fmt.Fprintf(c, `<text x="18" y="%.1f" text-anchor="middle" transform="rotate(-90 18 %.1f)">%s</text>`+"\n",
+		(top+bottom)/2, (top+bottom)/2, html.EscapeString(yLabel))
+}
+
+// legend lists the series names with their colors right of the plot.
+func (c *canvas) legend(names []string) {
+	x := float64(width - marginRight + 15)
+	for i, n := range names {
+		y := float64(marginTop + 10 + 20*i)
+		fmt.Fprintf(c, `<rect x="%.1f" y="%.1f" width="12" height="12" fill="%s"/>`+"\n", x, y-10, color(i))
+		c.text(x+18, y, "start", "", n)
+	}
+}
+
+func (c *canvas) close() []byte {
+	c.WriteString("</svg>\n")
+	return c.Bytes()
+}
+
+// yRange returns the range of the y axis for values: from 0 (linear) or
+// the smallest positive value (log) to the largest error bar end.
+func yRange(values []Value, log bool) (lo, hi float64) {
+	lo, hi = math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		for _, y := range []float64{v.Lo, v.Y, v.Hi} {
+			if log && y <= 0 {
+				continue
+			}
+			lo, hi = min(lo, y), max(hi, y)
+		}
+	}
+	if math.IsInf(lo, 1) {
+		lo, hi = 1, 10
+	}
+	if !log {
+		lo = min(lo, 0)
+		hi = max(hi, 0)
+		if hi == lo {
+			hi = lo + 1
+		}
+		return lo, hi * 1.05
+	}
+	return math.Pow(10, math.Floor(math.Log10(lo))), math.Pow(10, math.Ceil(math.Log10(hi)))
+}
+
+// yAxis returns the scale and ticks of the y axis for values.
+func yAxis(values []Value, log bool) (scale, []float64) {
+	lo, hi := yRange(values, log)
+	s := newScale(lo, hi, log, height-marginBottom, marginTop)
+	if log {
+		return s, logTicks(lo, hi)
+	}
+	ticks := niceTicks(lo, hi, 5)
+	return s, slices.DeleteFunc(ticks, func(t float64) bool { return t > hi })
+}
@@ -0,0 +1,129 @@
+package chart
+
+import (
+	"encoding/xml"
+	"io"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+
+	"go-lab/pkg/benchfmt"
+)
+
+// elements decodes svg and counts its elements by name, failing tb when it
+// is not well-formed XML.
+func elements(tb testing.TB, svg []byte) map[string]int {
+	tb.Helper()
+	n := map[string]int{}
+	d := xml.NewDecoder(strings.NewReader(string(svg)))
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			return n
+		}
+		if err != nil {
+			tb.Fatalf("invalid SVG: %v\n%s", err, svg)
+		}
+		if se, ok := tok.(xml.StartElement); ok {
+			n[se.Name.Local]++
+			if se.Name.Local == "path" {
+				n["error"]++
+			}
+		}
+	}
+}
+
+func TestNiceTicks(t *testing.T) {
+	if got, want := niceTicks(0, 105, 5), []float64{0, 20, 40, 60, 80, 100}; !slices.Equal(got, want) {
+		t.Errorf("niceTicks(0, 105) = %v, want %v", got, want)
+	}
+	if got, want := logTicks(1, 1e4), []float64{1, 10, 100, 1000, 1e4}; !slices.Equal(got, want) {
+		t.Errorf("logTicks(1, 1e4) = %v, want %v", got, want)
+	}
+	if got, want := logTicks(1, 10), []float64{1, 2, 5, 10}; !slices.Equal(got, want) {
+		t.Errorf("logTicks(1, 10) = %v, want %v", got, want)
+	}
+}
+
+func TestScale(t *testing.T) {
+	s := newScale(1, 100, true, 0, 200)
+	for v, want := range map[float64]float64{1: 0, 10: 100, 100: 200} {
+		if got := s.pos(v); math.Abs(got-want) > 1e-9 {
+			t.Errorf("log pos(%v) = %v, want %v", v, got, want)
+		}
+	}
+	if got := newScale(0, 10, false, 300, 100).pos(5); got != 200 {
+		t.Errorf("linear pos(5) = %v, want 200", got)
+	}
+}
+
+func results(tb testing.TB, lines string) []benchfmt.Result {
+	tb.Helper()
+	rs, err := benchfmt.Parse(strings.NewReader(lines))
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return rs
+}
+
+func TestSweep(t *testing.T) {
+	rs := results(t, `BenchmarkConcatPlus/N=2-8      	1000	  40 ns/op	  16 B/op	1 allocs/op
+BenchmarkConcatPlus/N=2-8      	1000	  44 ns/op	  16 B/op	1 allocs/op
+BenchmarkConcatPlus/N=64-8     	1000	3000 ns/op	9000 B/op	63 allocs/op
+BenchmarkConcatPlus/N=64-8     	1000	3300 ns/op	9000 B/op	63 allocs/op
+BenchmarkConcatBuilder/N=2-8   	1000	  30 ns/op	   8 B/op	1 allocs/op
+BenchmarkConcatBuilder/N=64-8  	1000	 400 ns/op	 512 B/op	7 allocs/op
+BenchmarkOther-8               	1000	  10 ns/op
+`)
+	l := Sweep(rs, "ns/op", "N")
+	if l == nil || len(l.Series) != 2 || l.Series[0].Name != "ConcatPlus-8" || l.Series[1].Name != "ConcatBuilder-8" {
+		t.Fatalf("Sweep series = %+v", l)
+	}
+	want := []Point{{2, Value{42, 40, 44}}, {64, Value{3150, 3000, 3300}}}
+	if got := l.Series[0].Points; !slices.Equal(got, want) {
+		t.Errorf("ConcatPlus points = %v, want %v", got, want)
+	}
+	if Sweep(rs, "ns/op", "M") != nil {
+		t.Error("Sweep over a missing axis is not nil")
+	}
+
+	l.LogX, l.LogY = true, true
+	n := elements(t, l.SVG())
+	if n["polyline"] != 2 || n["circle"] != 4 || n["error"] != 2 {
+		t.Errorf("SVG has %d lines, %d points, %d error bars; want 2, 4, 2", n["polyline"], n["circle"], n["error"])
+	}
+}
+
+func TestCompare(t *testing.T) {
+	rs := results(t, `BenchmarkSmallValue-8     	1000	 2 ns/op
+BenchmarkSmallPointer-8   	1000	 3 ns/op
+BenchmarkLargeValue-8     	1000	90 ns/op
+BenchmarkLargeValue-8     	1000	95 ns/op
+BenchmarkLargePointer-8   	1000	 3 ns/op
+BenchmarkXLargeValue-8    	1000	300 ns/op
+BenchmarkUnrelated-8      	1000	 1 ns/op
+`)
+	b := Compare(rs, "ns/op", []string{"Value", "Pointer"})
+	if b == nil {
+		t.Fatal("Compare = nil")
+	}
+	if want := []string{"Small-8", "Large-8", "XLarge-8"}; !slices.Equal(b.Categories, want) {
+		t.Errorf("categories = %v, want %v", b.Categories, want)
+	}
+	if v := b.Series[0].Values[1]; v != (Value{92.5, 90, 95}) {
+		t.Errorf("LargeValue = %+v", v)
+	}
+	if v := b.Series[1].Values[2]; !math.IsNaN(v.Y) {
+		t.Errorf("missing XLargePointer = %+v, want NaN", v)
+	}
+	b.Title = "receiver <size> & kind"
+	n := elements(t, b.SVG())
+	// Background, legend swatches and five bars.
+	if n["rect"] != 1+2+5 || n["error"] != 1 {
+		t.Errorf("SVG has %d rects, %d error bars; want 8, 1", n["rect"], n["error"])
+	}
+	if Compare(rs, "ns/op", []string{"Slice"}) != nil {
+		t.Error("Compare without matching groups is not nil")
+	}
+}
@@ -0,0 +1,101 @@
+package chart
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+)
+
+// Point is one value of a line series at X.
+type Point struct {
+	X float64
+	Value
+}
+
+// Series is one line of a Line chart, e.g. one benchmark pattern.
+type Series struct {
+	Name   string
+	Points []Point
+}
+
+// Line is a line chart over a parameter axis such as N, one line per
+// series, with an error bar at every point.
+type Line struct {
+	Title, XLabel, YLabel string
+	LogX, LogY            bool
+	Series                []Series
+}
+
+// SVG renders the chart. On a log axis, points with a non-positive
+// coordinate are left out.
+func (l *Line) SVG() []byte {
+	var xs []float64
+	var values []Value
+	for _, s := range l.Series {
+		for _, p := range s.Points {
+			if l.LogX && p.X <= 0 {
+				continue
+			}
+			xs = append(xs, p.X)
+			values = append(values, p.Value)
+		}
+	}
+	slices.Sort(xs)
+	xs = slices.Compact(xs)
+
+	c := newCanvas(l.Title)
+	y, yTicks := yAxis(values, l.LogY)
+	x, xTicks := l.xAxis(xs)
+	c.axes(y, yTicks, l.XLabel, l.YLabel)
+	bottom := float64(height - marginBottom)
+	for _, t := range xTicks {
+		p := x.pos(t)
+		c.line(p, bottom, p, bottom+5, "black", "")
+		c.text(p, bottom+18, "middle", "", formatTick(t))
+	}
+
+	names := make([]string, len(l.Series))
+	for i, s := range l.Series {
+		names[i] = s.Name
+		var path []string
+		for _, p := range s.Points {
+			if (l.LogX && !validLog(p.X)) || (l.LogY && !validLog(p.Y)) {
+				continue
+			}
+			px, py := x.pos(p.X), y.pos(p.Y)
+			path = append(path, fmt.Sprintf("%.1f,%.1f", px, py))
+			if !l.LogY || validLog(p.Lo) {
+				c.errorBar(px, y.pos(p.Lo), y.pos(p.Hi), color(i))
+			}
+			fmt.Fprintf(c, `<circle cx="%.1f" cy="%.1f" r="3" fill="%s"/>`+"\n", px, py, color(i))
+		}
+		if len(path) > 1 {
+			fmt.Fprintf(c, `<polyline points="%s" fill="none" stroke="%s" stroke-width="2"/>`+"\n",
+				strings.Join(path, " "), color(i))
+		}
+	}
+	c.legend(names)
+	return c.close()
+}
+
+// xAxis returns the scale and ticks of the x axis. Up to 12 distinct
+// values, typically the sizes of a sweep, are labelled themselves.
+func (l *Line) xAxis(xs []float64) (scale, []float64) {
+	lo, hi := 1.0, 10.0
+	if len(xs) > 0 {
+		lo, hi = xs[0], xs[len(xs)-1]
+	}
+	s := newScale(lo, hi, l.LogX, marginLeft, width-marginRight)
+	switch {
+	case len(xs) > 0 && len(xs) <= 12:
+		return s, xs
+	case l.LogX:
+		return s, logTicks(lo, hi)
+	default:
+		return s, niceTicks(lo, hi, 6)
+	}
+}
+
+// validLog reports whether v can be placed on a log axis.
+func validLog(v float64) bool { return v > 0 && !math.IsInf(v, 0) }
@@ -0,0 +1,184 @@
+// Package complexity checks asymptotic claims such as "O(N²) copy cost"
+// against measurements: it fits the values of a size sweep (ns/op, B/op or
+// allocs/op over the N axis) with the models 1, log N, N, N log N and N²
+// by least squares and reports the best model with its goodness of fit.
+package complexity
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+)
+
+// Model is a growth function of the size N.
+type Model struct {
+	Name string // e.g. "N log N"
+	g    func(n float64) float64
+}
+
+// The models fitted by Fit, in increasing order of growth.
+var (
+	Constant  = Model{"1", func(float64) float64 { return 1 }}
+	Log       = Model{"log N", math.Log2}
+	Linear    = Model{"N", func(n float64) float64 { return n }}
+	NLogN     = Model{"N log N", func(n float64) float64 { return n * math.Log2(n) }}
+	Quadratic = Model{"N²", func(n float64) float64 { return n * n }}
+
+	Models = []Model{Constant, Log, Linear, NLogN, Quadratic}
+)
+
+func (m Model) String() string { return "O(" + m.Name + ")" }
+
+// Point is one measurement of a sweep: the value Y at size N.
+type Point struct {
+	N float64
+	Y float64
+}
+
+// Points evaluates f at every size.
+func Points(sizes []int, f func(n int) float64) []Point {
+	pts := make([]Point, len(sizes))
+	for i, n := range sizes {
+		pts[i] = Point{N: float64(n), Y: f(n)}
+	}
+	return pts
+}
+
+// Fit is a least-squares fit Y ≈ A + C·g(N) of one model.
+type Fit struct {
+	Model Model
+	A, C  float64
+	// R2 is the coefficient of determination: the share of the variance
+	// of Y explained by the model, 1 for a perfect fit. The constant model
+	// explains none (0) unless Y does not vary at all (1).
+	R2 float64
+}
+
+func (f Fit) String() string {
+	return fmt.Sprintf("%v (R²=%.4f)", f.Model, f.R2)
+}
+
+// MinGrowth is the growth over the sweep, relative to the mean value,
+// below which a sweep counts as constant: a model whose fitted values rise
+// by less than this from the smallest to the largest N only fits noise.
+const MinGrowth = 0.10
+
+// FitModel fits pts with m by least squares.
+func FitModel(pts []Point, m Model) Fit {
+	var my float64
+	for _, p := range pts {
+		my += p.Y
+	}
+	my /= float64(len(pts))
+	var sst float64
+	for _, p := range pts {
+		sst += (p.Y - my) * (p.Y - my)
+	}
+	f := Fit{Model: m, A: my}
+	if m.Name != Constant.Name {
+		var mg float64
+		for _, p := range pts {
+			mg += m.g(p.N)
+		}
+		mg /= float64(len(pts))
+		var sxy, sxx float64
+		for _, p := range pts {
+			sxy += (m.g(p.N) - mg) * (p.Y - my)
+			sxx += (m.g(p.N) - mg) * (m.g(p.N) - mg)
+		}
+		if sxx > 0 {
+			f.C = sxy / sxx
+		}
+		f.A = my - f.C*mg
+	}
+	var sse float64
+	for _, p := range pts {
+		r := p.Y - (f.A + f.C*m.g(p.N))
+		sse += r * r
+	}
+	switch {
+	case sst > 0:
+		f.R2 = 1 - sse/sst
+	case sse == 0:
+		f.R2 = 1
+	}
+	return f
+}
+
+// FitAll fits pts with every model of Models, best first (see Best).
+// It needs at least three points with distinct positive sizes.
+func FitAll(pts []Point) ([]Fit, error) {
+	ns := make([]float64, 0, len(pts))
+	for _, p := range pts {
+		if p.N <= 0 {
+			return nil, fmt.Errorf("complexity: size %v is not positive", p.N)
+		}
+		if !slices.Contains(ns, p.N) {
+			ns = append(ns, p.N)
+		}
+	}
+	if len(ns) < 3 {
+		return nil, fmt.Errorf("complexity: %d distinct sizes, need 3", len(ns))
+	}
+	lo, hi := slices.Min(ns), slices.Max(ns)
+	var mean float64
+	for _, p := range pts {
+		mean += p.Y
+	}
+	mean /= float64(len(pts))
+
+	fits := make([]Fit, len(Models))
+	for i, m := range Models {
+		fits[i] = FitModel(pts, m)
+	}
+	// growing reports whether f describes a real increase over the sweep.
+	growing := func(f Fit) bool {
+		return f.C > 0 && f.C*(f.Model.g(hi)-f.Model.g(lo)) >= MinGrowth*math.Abs(mean)
+	}
+	anyGrowing := slices.ContainsFunc(fits, growing)
+	// Growing models by R², then Constant, then the models that only fit
+	// noise or a decrease.
+	tier := func(f Fit) int {
+		switch {
+		case growing(f):
+			return 0
+		case f.Model.Name == Constant.Name:
+			if anyGrowing {
+				return 1
+			}
+			return 0
+		}
+		return 2
+	}
+	slices.SortStableFunc(fits, func(a, b Fit) int {
+		return cmp.Or(cmp.Compare(tier(a), tier(b)), cmp.Compare(b.R2, a.R2))
+	})
+	return fits, nil
+}
+
+// Best returns the model that describes pts best: among the models whose
+// fitted values grow by at least MinGrowth over the sweep, the one with
+// the highest R²; Constant when none does.
+func Best(pts []Point) (Fit, error) {
+	fits, err := FitAll(pts)
+	if err != nil {
+		return Fit{}, err
+	}
+	return fits[0], nil
+}
+
+// Table renders fits as a markdown table, marking the first as best.
+func Table(fits []Fit) string {
+	var sb strings.Builder
+	sb.WriteString("| Model | A | C | R² |\n| :--- | ---: | ---: | ---: |\n")
+	for i, f := range fits {
+		name := f.Model.String()
+		if i == 0 {
+			name = "**" + name + "**"
+		}
+		fmt.Fprintf(&sb, "| %s | %.4g | %.4g | %.4f |\n", name, f.A, f.C, f.R2)
+	}
+	return sb.String()
+}
@@ -0,0 +1,69 @@
+package complexity
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"go-lab/pkg/benchfmt"
+)
+
+var sizes = []int{2, 4, 8, 16, 32, 64}
+
+func TestBest(t *testing.T) {
+	tests := []struct {
+		name string
+		f    func(n float64) float64
+		want Model
+	}{
+		{"constant", func(float64) float64 { return 7 }, Constant},
+		{"noisy constant", func(n float64) float64 { return 100 + math.Mod(n*7, 5) }, Constant},
+		{"log", func(n float64) float64 { return math.Log2(n) + 1 }, Log},
+		{"linear", func(n float64) float64 { return 16*n - 8 }, Linear},
+		{"n log n", func(n float64) float64 { return 3*n*math.Log2(n) + 50 }, NLogN},
+		{"quadratic", func(n float64) float64 { return 4*n*n + 12*n }, Quadratic},
+		{"decreasing", func(n float64) float64 { return 1000 / n }, Constant},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pts := Points(sizes, func(n int) float64 { return tt.f(float64(n)) })
+			got, err := Best(pts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Model.Name != tt.want.Name {
+				fits, _ := FitAll(pts)
+				t.Errorf("Best = %v, want %v\n%s", got, tt.want, Table(fits))
+			}
+		})
+	}
+}
+
+func TestFitModel(t *testing.T) {
+	f := FitModel(Points(sizes, func(n int) float64 { return 3 + 2*float64(n*n) }), Quadratic)
+	if math.Abs(f.A-3) > 1e-9 || math.Abs(f.C-2) > 1e-9 || math.Abs(f.R2-1) > 1e-12 {
+		t.Errorf("FitModel = %+v, want A=3 C=2 R²=1", f)
+	}
+	if _, err := Best(Points([]int{4, 4, 8}, func(int) float64 { return 1 })); err == nil {
+		t.Error("Best of two distinct sizes succeeded")
+	}
+}
+
+func TestFromResults(t *testing.T) {
+	var results []benchfmt.Result
+	for _, n := range sizes {
+		for _, base := range []string{"ConcatPlus", "ConcatBuilder"} {
+			results = append(results, benchfmt.Result{
+				Name:   benchfmt.ParseName(fmt.Sprintf("Benchmark%s/N=%d-8", base, n)),
+				Values: []benchfmt.Value{{Value: float64(n * n), Unit: "B/op"}},
+			})
+		}
+	}
+	results = append(results, benchfmt.Result{Name: benchfmt.ParseName("BenchmarkOther-8")})
+	groups, sweeps := FromResults(results, "B/op")
+	if strings.Join(groups, ",") != "ConcatPlus-8,ConcatBuilder-8" || len(sweeps["ConcatPlus-8"]) != len(sizes) {
+		t.Fatalf("FromResults = %v, %v", groups, sweeps)
+	}
+	Assert(t, "ConcatPlus", sweeps["ConcatPlus-8"], Quadratic)
+}
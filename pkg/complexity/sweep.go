@@ -0,0 +1,63 @@
+package complexity
+
+import (
+	"slices"
+	"testing"
+
+	"go-lab/pkg/benchfmt"
+)
+
+// SizeKey is the benchmark name axis holding the size, as written by
+// measure.SweepN.
+const SizeKey = "N"
+
+// FromResults collects the unit values of benchmark results along the N
+// axis, one sweep per benchmark group with N dropped (e.g.
+// "ConcatPlus-8"), in first-seen order. Every -count sample is a point.
+func FromResults(results []benchfmt.Result, unit string) (groups []string, sweeps map[string][]Point) {
+	sweeps = map[string][]Point{}
+	for _, r := range results {
+		n, ok := r.Name.Float(SizeKey)
+		if !ok {
+			continue
+		}
+		v, ok := r.Get(unit)
+		if !ok {
+			continue
+		}
+		k := r.Name.Group(SizeKey)
+		if !slices.Contains(groups, k) {
+			groups = append(groups, k)
+		}
+		sweeps[k] = append(sweeps[k], Point{N: n, Y: v})
+	}
+	return groups, sweeps
+}
+
+// Assert fails tb unless want is the best model for pts (see Best) and
+// logs the fit of every model. Use it for growth rates that follow from
+// the algorithm, e.g. the bytes copied by repeated concatenation.
+func Assert(tb testing.TB, name string, pts []Point, want Model) Fit {
+	tb.Helper()
+	fits, err := FitAll(pts)
+	if err != nil {
+		tb.Fatalf("%s: %v", name, err)
+	}
+	tb.Logf("%s: best %v, asserted %v\n%s", name, fits[0], want, Table(fits))
+	if fits[0].Model.Name != want.Name {
+		tb.Errorf("%s: grows as %v, want %v", name, fits[0], want)
+	}
+	return fits[0]
+}
+
+// Observe logs the fit of every model for pts without asserting and
+// returns the best one.
+func Observe(tb testing.TB, name string, pts []Point) Fit {
+	tb.Helper()
+	fits, err := FitAll(pts)
+	if err != nil {
+		tb.Fatalf("%s: %v", name, err)
+	}
+	tb.Logf("%s: best %v\n%s", name, fits[0], Table(fits))
+	return fits[0]
+}
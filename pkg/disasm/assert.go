@@ -0,0 +1,53 @@
+package disasm
+
+import "testing"
+
+// Load disassembles the test binary of the package in dir (usually "." from
+// a test) and fails tb when it cannot be built.
+func Load(tb testing.TB, dir string) *Report {
+	tb.Helper()
+	r, err := Disassemble(tb.Context(), dir)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return r
+}
+
+// AssertCalls fails tb unless fn calls target.
+func (r *Report) AssertCalls(tb testing.TB, fn, target string) {
+	tb.Helper()
+	f := r.mustFunc(tb, fn)
+	if f != nil && len(f.CallsTo(target)) == 0 {
+		tb.Errorf("%s: want a call to %s, got runtime calls %v", fn, target, f.Runtime())
+	}
+}
+
+// AssertNoCalls fails tb if fn calls target, reporting where it does.
+func (r *Report) AssertNoCalls(tb testing.TB, fn, target string) {
+	tb.Helper()
+	f := r.mustFunc(tb, fn)
+	if f == nil {
+		return
+	}
+	for _, c := range f.CallsTo(target) {
+		tb.Errorf("%s: want no call to %s, got one at %s:%d", fn, target, c.File, c.Line)
+	}
+}
+
+// LogRuntime logs the runtime functions fn calls without asserting.
+func (r *Report) LogRuntime(tb testing.TB, fn string) {
+	tb.Helper()
+	if f := r.mustFunc(tb, fn); f != nil {
+		tb.Logf("%s: runtime calls %v", fn, f.Runtime())
+	}
+}
+
+func (r *Report) mustFunc(tb testing.TB, fn string) *Func {
+	tb.Helper()
+	f, ok := r.Func(fn)
+	if !ok {
+		tb.Errorf("%s: no such symbol in %s (inlined everywhere? mark it //go:noinline)", fn, r.Pkg)
+		return nil
+	}
+	return &f
+}
@@ -0,0 +1,159 @@
+// Package disasm reports which functions each function of an experiment
+// package calls in the machine code of its test binary, so that claims such
+// as "this conversion does not call runtime.slicebytetostring" are backed by
+// the generated code rather than by allocs/op alone.
+package disasm
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Call is one call instruction.
+type Call struct {
+	Target string `json:"target"` // full symbol, e.g. "runtime.slicebytetostring"
+	File   string `json:"file"`   // source file of the call, base name
+	Line   int    `json:"line"`
+}
+
+// Func is one function symbol of the package.
+type Func struct {
+	Name  string `json:"name"` // symbol without the package path, e.g. "(*T).M" or "F.func1"
+	File  string `json:"file"`
+	Calls []Call `json:"calls,omitempty"`
+}
+
+// prologue is the prefix of the stack-growth calls every growable-stack
+// function makes; they say nothing about the body and are left out of Calls.
+const prologue = "runtime.morestack"
+
+// Runtime returns the distinct runtime functions f calls, in call order.
+func (f Func) Runtime() []string {
+	var out []string
+	for _, c := range f.Calls {
+		if strings.HasPrefix(c.Target, "runtime.") && !slices.Contains(out, c.Target) {
+			out = append(out, c.Target)
+		}
+	}
+	return out
+}
+
+// CallsTo returns the calls f makes to target.
+func (f Func) CallsTo(target string) []Call {
+	var out []Call
+	for _, c := range f.Calls {
+		if c.Target == target {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+var (
+	textLine = regexp.MustCompile(`^TEXT (\S+)\(SB\) (\S+)`)
+	// CALL on amd64/386, BL on arm64/arm, JMP and B for tail calls.
+	callLine = regexp.MustCompile(`^\s+(\S+):(\d+)\s.*\s(?:CALL|BL|JMP|B)\s+(\S+)\(SB\)`)
+)
+
+// Parse reads go tool objdump output and returns the functions whose symbol
+// is in package pkg (an import path), in address order.
+func Parse(r io.Reader, pkg string) ([]Func, error) {
+	var (
+		funcs []Func
+		cur   *Func
+	)
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	for sc.Scan() {
+		line := sc.Text()
+		if m := textLine.FindStringSubmatch(line); m != nil {
+			cur = nil
+			if name, ok := strings.CutPrefix(m[1], pkg+"."); ok {
+				funcs = append(funcs, Func{Name: name, File: filepath.Base(m[2])})
+				cur = &funcs[len(funcs)-1]
+			}
+			continue
+		}
+		if cur == nil {
+			continue
+		}
+		m := callLine.FindStringSubmatch(line)
+		if m == nil || strings.HasPrefix(m[3], prologue) {
+			continue
+		}
+		n, _ := strconv.Atoi(m[2])
+		cur.Calls = append(cur.Calls, Call{Target: m[3], File: m[1], Line: n})
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("disasm: %w", err)
+	}
+	return funcs, nil
+}
+
+// Report is the disassembly of one package's test binary.
+type Report struct {
+	Pkg   string `json:"pkg"`
+	Funcs []Func `json:"funcs"`
+}
+
+// Func returns the function named name ("F", "T.M", "(*T).M").
+func (r *Report) Func(name string) (Func, bool) {
+	for _, f := range r.Funcs {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return Func{}, false
+}
+
+// Disassemble builds the test binary of the package in dir with go test -c
+// and disassembles the functions of that package with go tool objdump.
+// Functions the compiler inlined everywhere have no symbol of their own;
+// mark them //go:noinline to inspect them.
+func Disassemble(ctx context.Context, dir string) (*Report, error) {
+	pkg, err := goOutput(ctx, dir, "list", "-f", "{{.ImportPath}}", ".")
+	if err != nil {
+		return nil, err
+	}
+	tmp, err := os.MkdirTemp("", "disasm")
+	if err != nil {
+		return nil, fmt.Errorf("disasm: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+	bin := filepath.Join(tmp, "pkg.test")
+	if _, err := goOutput(ctx, dir, "test", "-c", "-o", bin, "."); err != nil {
+		return nil, err
+	}
+	out, err := goOutput(ctx, dir, "tool", "objdump", "-s", "^"+regexp.QuoteMeta(pkg)+`\.`, bin)
+	if err != nil {
+		return nil, err
+	}
+	funcs, err := Parse(strings.NewReader(out), pkg)
+	if err != nil {
+		return nil, err
+	}
+	return &Report{Pkg: pkg, Funcs: funcs}, nil
+}
+
+// goOutput runs the go command in dir and returns its trimmed stdout.
+func goOutput(ctx context.Context, dir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "go", args...)
+	cmd.Dir = dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("go %s: %w\n%s", args[0], err, stderr.Bytes())
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
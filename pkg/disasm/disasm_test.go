@@ -0,0 +1,56 @@
+package disasm
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+const sampleOutput = `TEXT go-lab/experiments/string-zero-copy.BytesToStringAssign(SB) /root/module/experiments/string-zero-copy/conversion.go
+  conversion.go:10	0x544200		493b6610		CMPQ SP, 0x10(R14)
+  conversion.go:11	0x544220		e81b4ef2ff		CALL runtime.slicebytetostring(SB)
+  conversion.go:10	0x54423a		e8c16ef4ff		CALL runtime.morestack_noctxt.abi0(SB)
+TEXT go-lab/experiments/string-zero-copy.StringToBytesRange(SB) /root/module/experiments/string-zero-copy/conversion.go
+  conversion.go:36	0x544320		31c9			XORL CX, CX
+TEXT go-lab/experiments/string-zero-copy.(*T).Get.func1(SB) /root/module/experiments/string-zero-copy/conversion.go
+  conversion.go:60	0x544400		e8f448f2ff		CALL runtime.concatstring2(SB)
+  conversion.go:61	0x544420		e8f448f2ff		CALL strings.Repeat(SB)
+  conversion.go:62	0x544440		e9f448f2ff		JMP runtime.concatstring2(SB)
+TEXT go-lab/pkg/measure.SweepN(SB) /root/module/pkg/measure/sweep.go
+  sweep.go:30		0x544500		e8f448f2ff		CALL runtime.newobject(SB)
+`
+
+func TestParse(t *testing.T) {
+	funcs, err := Parse(strings.NewReader(sampleOutput), "go-lab/experiments/string-zero-copy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, f := range funcs {
+		names = append(names, f.Name)
+	}
+	if want := []string{"BytesToStringAssign", "StringToBytesRange", "(*T).Get.func1"}; !slices.Equal(names, want) {
+		t.Fatalf("names = %q, want %q", names, want)
+	}
+	if c := funcs[0].Calls; len(c) != 1 || c[0] != (Call{"runtime.slicebytetostring", "conversion.go", 11}) {
+		t.Errorf("BytesToStringAssign calls = %+v, want only slicebytetostring", c)
+	}
+	if len(funcs[1].Calls) != 0 || funcs[1].File != "conversion.go" {
+		t.Errorf("StringToBytesRange = %+v, want no calls", funcs[1])
+	}
+	if got := funcs[2].Runtime(); !slices.Equal(got, []string{"runtime.concatstring2"}) {
+		t.Errorf("Runtime() = %q, want concatstring2 once", got)
+	}
+	if got := funcs[2].CallsTo("runtime.concatstring2"); len(got) != 2 {
+		t.Errorf("CallsTo(concatstring2) = %+v, want the call and the tail call", got)
+	}
+}
+
+func TestDisassemble(t *testing.T) {
+	r := Load(t, "testdata/sample")
+	if r.Pkg != "go-lab/pkg/disasm/testdata/sample" {
+		t.Errorf("Pkg = %q", r.Pkg)
+	}
+	r.AssertCalls(t, "ToString", "runtime.slicebytetostring")
+	r.AssertNoCalls(t, "Compare", "runtime.slicebytetostring")
+}
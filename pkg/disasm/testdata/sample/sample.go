@@ -0,0 +1,7 @@
+package sample
+
+//go:noinline
+func ToString(b []byte) string { return string(b) }
+
+//go:noinline
+func Compare(b []byte, s string) bool { return string(b) == s }
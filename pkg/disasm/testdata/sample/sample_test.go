@@ -0,0 +1,9 @@
+package sample
+
+import "testing"
+
+func TestSample(t *testing.T) {
+	if ToString([]byte("a")) != "a" || !Compare([]byte("a"), "a") {
+		t.Fatal("sample")
+	}
+}
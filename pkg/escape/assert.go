@@ -0,0 +1,50 @@
+package escape
+
+import "testing"
+
+// Load compiles the package in dir (usually "." from a test) and fails tb
+// when the build does not succeed.
+func Load(tb testing.TB, dir string) *Report {
+	tb.Helper()
+	r, err := Compile(tb.Context(), dir)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return r
+}
+
+// AssertStack fails tb if variable name of fn is moved to the heap, and
+// reports the compiler's reasoning chain when it is. It also fails when fn
+// has no diagnostics at all, which means fn is misspelled or was renamed:
+// with -m=2 every compiled function gets at least an inlining decision.
+func (r *Report) AssertStack(tb testing.TB, fn, name string) {
+	tb.Helper()
+	if len(r.Func(fn)) == 0 {
+		tb.Errorf("%s: no such function in the diagnostics", fn)
+		return
+	}
+	if d, ok := r.Heap(fn, name); ok {
+		tb.Errorf("%s: want %s on the stack, got\n%s", fn, name, d.Explain())
+	}
+}
+
+// AssertHeap fails tb unless variable name of fn is moved to the heap.
+func (r *Report) AssertHeap(tb testing.TB, fn, name string) {
+	tb.Helper()
+	if _, ok := r.Heap(fn, name); !ok {
+		tb.Errorf("%s: want %s moved to heap, got no such diagnostic", fn, name)
+	}
+}
+
+// Log logs the escape diagnostics of fn (inlining decisions excluded)
+// without asserting, for patterns whose outcome is the research question.
+func (r *Report) Log(tb testing.TB, fn string) {
+	tb.Helper()
+	for _, d := range r.Func(fn) {
+		switch d.Kind {
+		case CanInline, CannotInline, InliningCall:
+			continue
+		}
+		tb.Logf("%s", d.Explain())
+	}
+}
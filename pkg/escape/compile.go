@@ -0,0 +1,163 @@
+package escape
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Report is the escape-analysis output of one package.
+type Report struct {
+	Dir         string       `json:"-"`
+	GoVersion   string       `json:"go_version"` // toolchain that compiled the package
+	Diagnostics []Diagnostic `json:"diagnostics"`
+}
+
+// Compile builds the package in dir with -gcflags=-m=2 and parses the
+// diagnostics. The build cache replays diagnostics, so repeated calls are
+// cheap. Func is resolved from the source for every position in a .go file.
+func Compile(ctx context.Context, dir string) (*Report, error) {
+	cmd := exec.CommandContext(ctx, "go", "build", "-o", os.DevNull, "-gcflags=-m=2", ".")
+	cmd.Dir = dir
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("go build -gcflags=-m=2 in %s: %w\n%s", dir, err, out.Bytes())
+	}
+	diags, err := Parse(&out)
+	if err != nil {
+		return nil, err
+	}
+	if err := resolveFuncs(dir, diags); err != nil {
+		return nil, err
+	}
+	v, err := goVersion(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+	return &Report{Dir: dir, GoVersion: v, Diagnostics: diags}, nil
+}
+
+// resolveFuncs sets Func to the declared function enclosing each position.
+func resolveFuncs(dir string, diags []Diagnostic) error {
+	decls := map[string][]funcRange{}
+	for i := range diags {
+		d := &diags[i]
+		if !strings.HasSuffix(d.Pos.File, ".go") || filepath.IsAbs(d.Pos.File) {
+			continue // autogenerated wrappers and instantiated GOROOT generics
+		}
+		// Replayed cache entries keep the paths of the first build's working
+		// directory, so the package files are identified by base name.
+		d.Pos.File = filepath.Base(d.Pos.File)
+		ranges, ok := decls[d.Pos.File]
+		if !ok {
+			var err error
+			if ranges, err = parseFuncs(filepath.Join(dir, d.Pos.File)); err != nil {
+				return err
+			}
+			decls[d.Pos.File] = ranges
+		}
+		for _, r := range ranges {
+			if r.contains(d.Pos) {
+				d.Func = r.name
+				break
+			}
+		}
+	}
+	return nil
+}
+
+type funcRange struct {
+	name       string
+	start, end token.Position
+}
+
+func (r funcRange) contains(p Pos) bool {
+	after := p.Line > r.start.Line || p.Line == r.start.Line && p.Col >= r.start.Column
+	before := p.Line < r.end.Line || p.Line == r.end.Line && p.Col <= r.end.Column
+	return after && before
+}
+
+// parseFuncs returns the declared functions of file with their compiler
+// names: "F", "T.M" or "(*T).M".
+func parseFuncs(file string) ([]funcRange, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, file, nil, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, fmt.Errorf("escape: %w", err)
+	}
+	var ranges []funcRange
+	for _, decl := range f.Decls {
+		fd, ok := decl.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		ranges = append(ranges, funcRange{
+			name:  FuncName(fd),
+			start: fset.Position(fd.Pos()),
+			end:   fset.Position(fd.End()),
+		})
+	}
+	return ranges, nil
+}
+
+// FuncName returns the compiler's name for fd, as used in -m output.
+func FuncName(fd *ast.FuncDecl) string {
+	if fd.Recv == nil || len(fd.Recv.List) == 0 {
+		return fd.Name.Name
+	}
+	t := fd.Recv.List[0].Type
+	ptr := false
+	if s, ok := t.(*ast.StarExpr); ok {
+		t, ptr = s.X, true
+	}
+	switch x := t.(type) {
+	case *ast.IndexExpr:
+		t = x.X
+	case *ast.IndexListExpr:
+		t = x.X
+	}
+	recv := "?"
+	if id, ok := t.(*ast.Ident); ok {
+		recv = id.Name
+	}
+	if ptr {
+		return "(*" + recv + ")." + fd.Name.Name
+	}
+	return recv + "." + fd.Name.Name
+}
+
+// Func returns the diagnostics of the declared function fn.
+func (r *Report) Func(fn string) []Diagnostic {
+	var out []Diagnostic
+	for _, d := range r.Diagnostics {
+		if d.Func == fn {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// Find returns the first diagnostic of kind about subject in fn.
+func (r *Report) Find(kind Kind, fn, subject string) (Diagnostic, bool) {
+	for _, d := range r.Diagnostics {
+		if d.Kind == kind && d.Func == fn && d.Subject == subject {
+			return d, true
+		}
+	}
+	return Diagnostic{}, false
+}
+
+// Heap reports whether variable name of fn is moved to the heap, with the
+// compiler's explanation when it is.
+func (r *Report) Heap(fn, name string) (Diagnostic, bool) {
+	return r.Find(MovedToHeap, fn, name)
+}
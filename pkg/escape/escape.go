@@ -0,0 +1,180 @@
+// Package escape captures the compiler's escape-analysis and inlining
+// diagnostics (go build -gcflags=-m=2) as structured data, so that
+// experiments can assert what escapes instead of inferring it from
+// allocation counts, and can show the compiler's reasoning when it does.
+package escape
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Kind classifies a diagnostic.
+type Kind string
+
+// Diagnostic kinds reported by -gcflags=-m=2.
+const (
+	MovedToHeap  Kind = "moved-to-heap"   // "moved to heap: x"
+	Escapes      Kind = "escapes"         // "func literal escapes to heap"
+	NoEscape     Kind = "does-not-escape" // "s does not escape"
+	LeakingParam Kind = "leaking-param"   // "leaking param: p", "leaking param content: p"
+	Capture      Kind = "capture"         // "F capturing by ref: x (...)"
+	CanInline    Kind = "can-inline"      // "can inline F with cost N as: ..."
+	CannotInline Kind = "cannot-inline"   // "cannot inline F: reason"
+	InliningCall Kind = "inlining-call"   // "inlining call to F"
+	OtherKind    Kind = "other"
+)
+
+// Pos is a source position. File is relative to the compiled package
+// directory, or a pseudo file such as "<autogenerated>".
+type Pos struct {
+	File string `json:"file"`
+	Line int    `json:"line"`
+	Col  int    `json:"col,omitempty"`
+}
+
+func (p Pos) String() string {
+	if p.Col == 0 {
+		return fmt.Sprintf("%s:%d", p.File, p.Line)
+	}
+	return fmt.Sprintf("%s:%d:%d", p.File, p.Line, p.Col)
+}
+
+// Diagnostic is one compiler message.
+type Diagnostic struct {
+	Pos     Pos    `json:"pos"`
+	Kind    Kind   `json:"kind"`
+	Subject string `json:"subject"` // variable, expression or function the message is about
+	Func    string `json:"func"`    // enclosing declared function, e.g. "PatternB" or "(*Small).PSum"
+	Message string `json:"message"`
+	// Flow is the compiler's explanation ("flow: ..." and "from ... at ..."
+	// lines) for escapes, moves to heap and leaking params.
+	Flow []string `json:"flow,omitempty"`
+}
+
+// Explain formats d with its flow, one line per step.
+func (d Diagnostic) Explain() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s: %s", d.Pos, d.Message)
+	for _, f := range d.Flow {
+		sb.WriteString("\n  ")
+		sb.WriteString(f)
+	}
+	return sb.String()
+}
+
+var (
+	posLine = regexp.MustCompile(`^(\S+?):(\d+)(?::(\d+))?: (.*)$`)
+
+	// Explanation headers that precede flow lines.
+	escapesIn = regexp.MustCompile(`^(.+) escapes to heap in (\S+):$`)
+	leaksFor  = regexp.MustCompile(`^parameter (\S+) leaks to .+ for (\S+) with derefs=-?\d+:$`)
+
+	movedToHeap  = regexp.MustCompile(`^moved to heap: (\S+)$`)
+	escapesHeap  = regexp.MustCompile(`^(.+) escapes to heap$`)
+	noEscape     = regexp.MustCompile(`^(.+) does not escape$`)
+	leakingParam = regexp.MustCompile(`^leaking param(?: content)?: (\S+)`)
+	capturing    = regexp.MustCompile(`^(\S+) capturing by (?:value|ref): (\S+)`)
+	canInline    = regexp.MustCompile(`^can inline (\S+)`)
+	cannotInline = regexp.MustCompile(`^cannot inline (\S+?):`)
+	inliningCall = regexp.MustCompile(`^inlining call to (\S+)`)
+)
+
+// Parse reads -gcflags=-m=2 output. Flow lines are attached to the
+// diagnostic they explain; Func is set only where the message names it
+// (Compile fills in the rest from the source).
+func Parse(r io.Reader) ([]Diagnostic, error) {
+	type key struct {
+		pos     Pos
+		subject string
+	}
+	var (
+		diags   []Diagnostic
+		flows   = map[key][]string{}
+		funcs   = map[key]string{}
+		current *key // explanation being read
+	)
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	for sc.Scan() {
+		m := posLine.FindStringSubmatch(sc.Text())
+		if m == nil {
+			continue // "# pkg" headers and the like
+		}
+		pos := Pos{File: filepath.ToSlash(filepath.Clean(m[1]))}
+		pos.Line, _ = strconv.Atoi(m[2])
+		pos.Col, _ = strconv.Atoi(m[3])
+		msg := m[4]
+
+		if strings.HasPrefix(msg, "  ") {
+			if current != nil {
+				flows[*current] = append(flows[*current], strings.TrimSpace(msg))
+			}
+			continue
+		}
+		current = nil
+		if h := escapesIn.FindStringSubmatch(msg); h != nil {
+			k := key{pos, h[1]}
+			current, funcs[k] = &k, h[2]
+			continue
+		}
+		if h := leaksFor.FindStringSubmatch(msg); h != nil {
+			k := key{pos, h[1]}
+			current, funcs[k] = &k, h[2]
+			continue
+		}
+
+		d := classify(msg)
+		d.Pos = pos
+		k := key{pos, d.Subject}
+		if f, ok := funcs[k]; ok && d.Func == "" {
+			d.Func = f
+		}
+		d.Flow = flows[k]
+		diags = append(diags, d)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("escape: %w", err)
+	}
+	return diags, nil
+}
+
+// classify sets Kind, Subject, Message and, for captures, Func.
+func classify(msg string) Diagnostic {
+	d := Diagnostic{Kind: OtherKind, Message: msg}
+	switch {
+	case match(movedToHeap, msg, &d.Subject):
+		d.Kind = MovedToHeap
+	case match(leakingParam, msg, &d.Subject):
+		d.Kind = LeakingParam
+	case match(canInline, msg, &d.Subject):
+		d.Kind = CanInline
+	case match(cannotInline, msg, &d.Subject):
+		d.Kind = CannotInline
+	case match(inliningCall, msg, &d.Subject):
+		d.Kind = InliningCall
+	case match(escapesHeap, msg, &d.Subject):
+		d.Kind = Escapes
+	case match(noEscape, msg, &d.Subject):
+		d.Kind = NoEscape
+	default:
+		if m := capturing.FindStringSubmatch(msg); m != nil {
+			d.Kind, d.Func, d.Subject = Capture, m[1], m[2]
+		}
+	}
+	return d
+}
+
+func match(re *regexp.Regexp, msg string, subject *string) bool {
+	m := re.FindStringSubmatch(msg)
+	if m == nil {
+		return false
+	}
+	*subject = m[1]
+	return true
+}
@@ -0,0 +1,101 @@
+package escape
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+const sampleOutput = `# go-lab/pkg/escape/testdata/sample
+./sample.go:6:6: cannot inline Stack: marked go:noinline
+./sample.go:8:7: can inline Stack.func1 with cost 3 as: func() { x++ }
+./sample.go:9:3: inlining call to Stack.func1
+./sample.go:15:2: Heap capturing by ref: x (addr=false assign=true width=8)
+./sample.go:16:9: func literal escapes to heap in Heap:
+./sample.go:16:9:   flow: ~r0 ← &{storage for func literal}:
+./sample.go:16:9:     from func literal (spill) at ./sample.go:16:9
+./sample.go:15:2: x escapes to heap in Heap:
+./sample.go:15:2:   flow: {storage for func literal} ← &x:
+./sample.go:15:2:     from x (captured by a closure) at ./sample.go:16:22
+./sample.go:15:2: moved to heap: x
+./sample.go:16:9: func literal escapes to heap
+./sample.go:19:7: parameter t leaks to ~r0 for (*T).Get with derefs=0:
+./sample.go:19:7:   flow: ~r0 ← t:
+./sample.go:19:7: leaking param: t to result ~r0 level=0
+./sample.go:21:7: s does not escape
+<autogenerated>:1: parameter ~p0 leaks to {heap} for Sumer.Sum with derefs=0:
+<autogenerated>:1:   flow: {heap} ← ~p0:
+<autogenerated>:1: leaking param: ~p0
+`
+
+func TestParse(t *testing.T) {
+	diags, err := Parse(strings.NewReader(sampleOutput))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		pos     string
+		kind    Kind
+		subject string
+		fn      string
+		flow    int
+	}{
+		{"sample.go:6:6", CannotInline, "Stack", "", 0},
+		{"sample.go:8:7", CanInline, "Stack.func1", "", 0},
+		{"sample.go:9:3", InliningCall, "Stack.func1", "", 0},
+		{"sample.go:15:2", Capture, "x", "Heap", 0},
+		{"sample.go:15:2", MovedToHeap, "x", "Heap", 2},
+		{"sample.go:16:9", Escapes, "func literal", "Heap", 2},
+		{"sample.go:19:7", LeakingParam, "t", "(*T).Get", 1},
+		{"sample.go:21:7", NoEscape, "s", "", 0},
+		{"<autogenerated>:1", LeakingParam, "~p0", "Sumer.Sum", 1},
+	}
+	if len(diags) != len(want) {
+		t.Fatalf("got %d diagnostics, want %d: %+v", len(diags), len(want), diags)
+	}
+	for i, w := range want {
+		d := diags[i]
+		if d.Pos.String() != w.pos || d.Kind != w.kind || d.Subject != w.subject || d.Func != w.fn || len(d.Flow) != w.flow {
+			t.Errorf("diags[%d] = %s %s %q in %q (%d flow lines), want %s %s %q in %q (%d)",
+				i, d.Pos, d.Kind, d.Subject, d.Func, len(d.Flow), w.pos, w.kind, w.subject, w.fn, w.flow)
+		}
+	}
+	if got := diags[4].Explain(); got != "sample.go:15:2: moved to heap: x\n  flow: {storage for func literal} ← &x:\n  from x (captured by a closure) at ./sample.go:16:22" {
+		t.Errorf("Explain() = %q", got)
+	}
+}
+
+// recorder captures Errorf calls so assertion helpers can be tested
+// without failing the enclosing test.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestCompile(t *testing.T) {
+	r := Load(t, "testdata/sample")
+
+	r.AssertStack(t, "Stack", "x")
+	r.AssertHeap(t, "Heap", "x")
+	rec := &recorder{TB: t}
+	r.AssertStack(rec, "Stak", "x")
+	if len(rec.errors) != 1 {
+		t.Errorf("AssertStack of an unknown function: errors %q, want one", rec.errors)
+	}
+	if d, ok := r.Heap("Heap", "x"); !ok || len(d.Flow) == 0 {
+		t.Errorf("Heap(Heap, x) = %+v, %v; want a flow", d, ok)
+	}
+	if _, ok := r.Find(LeakingParam, "(*T).Get", "t"); !ok {
+		t.Errorf("no leaking param t in (*T).Get")
+	}
+	// The closure of Stack is attributed to its declared function.
+	if _, ok := r.Find(CanInline, "Stack", "Stack.func1"); !ok {
+		t.Errorf("can inline Stack.func1 not attributed to Stack: %+v", r.Func("Stack"))
+	}
+}
@@ -0,0 +1,230 @@
+package escape
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// GoldenFile is the snapshot checked into each experiment directory.
+const GoldenFile = "escape.golden"
+
+// Fact is one position-independent compiler decision about a function,
+// e.g. {PatternD_EscapingMutating, moved-to-heap, x}. Facts survive edits
+// that only move code, so a diff of two snapshots shows changed decisions.
+type Fact struct {
+	Func    string
+	Kind    Kind
+	Subject string // repeated subjects in one function get a " #n" suffix
+	Detail  string // inlining reason, capture mode or leak destination
+}
+
+func (f Fact) String() string {
+	s := string(f.Kind) + " " + f.Subject
+	if f.Detail != "" {
+		s += " (" + f.Detail + ")"
+	}
+	return s
+}
+
+// Snapshot is the set of escape and inlining decisions of one package.
+type Snapshot struct {
+	GoVersion string
+	Facts     []Fact // sorted by Func, then in compiler output order
+}
+
+var (
+	inlineCost = regexp.MustCompile(`: cost \d+ exceeds budget \d+`)
+	leakDetail = regexp.MustCompile(`^leaking param( content)?: \S+ ?(.*)$`)
+)
+
+// Snapshot reduces r to its decisions. Diagnostics without a function or
+// of OtherKind are dropped; inlining costs are dropped because they change
+// between releases without changing the decision.
+func (r *Report) Snapshot() Snapshot {
+	s := Snapshot{GoVersion: r.GoVersion}
+	seen := map[Fact]int{}
+	for _, d := range r.Diagnostics {
+		if d.Func == "" || d.Kind == OtherKind {
+			continue
+		}
+		f := Fact{Func: d.Func, Kind: d.Kind, Subject: d.Subject, Detail: detail(d)}
+		seen[f]++
+		if n := seen[f]; n > 1 {
+			f.Subject += " #" + strconv.Itoa(n)
+		}
+		s.Facts = append(s.Facts, f)
+	}
+	slices.SortStableFunc(s.Facts, func(a, b Fact) int { return strings.Compare(a.Func, b.Func) })
+	return s
+}
+
+func detail(d Diagnostic) string {
+	switch d.Kind {
+	case CannotInline:
+		_, reason, _ := strings.Cut(d.Message, d.Subject+": ")
+		return inlineCost.ReplaceAllString(reason, "")
+	case Capture:
+		if strings.Contains(d.Message, "capturing by ref") {
+			return "by ref"
+		}
+		return "by value"
+	case LeakingParam:
+		m := leakDetail.FindStringSubmatch(d.Message)
+		if m == nil {
+			return ""
+		}
+		return strings.TrimSpace(strings.TrimPrefix(m[1], " ") + " " + m[2])
+	}
+	return ""
+}
+
+// Write writes s in the golden format: a "# go version" header followed by
+// one tab-separated fact per line.
+func (s Snapshot) Write(w io.Writer) error {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# %s\n", s.GoVersion)
+	for _, f := range s.Facts {
+		fmt.Fprintf(&buf, "%s\t%s\t%s\t%s\n", f.Func, f.Kind, f.Subject, f.Detail)
+	}
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("write snapshot: %w", err)
+	}
+	return nil
+}
+
+// ReadSnapshot parses the golden format written by Snapshot.Write.
+func ReadSnapshot(r io.Reader) (Snapshot, error) {
+	var s Snapshot
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		line := sc.Text()
+		if v, ok := strings.CutPrefix(line, "# "); ok {
+			s.GoVersion = v
+			continue
+		}
+		if line == "" {
+			continue
+		}
+		parts := strings.Split(line, "\t")
+		if len(parts) != 4 {
+			return Snapshot{}, fmt.Errorf("snapshot line %d: want 4 tab-separated fields, got %d", n, len(parts))
+		}
+		s.Facts = append(s.Facts, Fact{Func: parts[0], Kind: Kind(parts[1]), Subject: parts[2], Detail: parts[3]})
+	}
+	if err := sc.Err(); err != nil {
+		return Snapshot{}, fmt.Errorf("read snapshot: %w", err)
+	}
+	return s, nil
+}
+
+// ReadGolden reads the GoldenFile of dir. A missing file yields
+// an error wrapping fs.ErrNotExist.
+func ReadGolden(dir string) (Snapshot, error) {
+	f, err := os.Open(filepath.Join(dir, GoldenFile))
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("read golden: %w", err)
+	}
+	defer f.Close()
+	return ReadSnapshot(f)
+}
+
+// WriteGolden replaces the GoldenFile of dir with s.
+func WriteGolden(dir string, s Snapshot) error {
+	var buf bytes.Buffer
+	if err := s.Write(&buf); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, GoldenFile), buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("write golden: %w", err)
+	}
+	return nil
+}
+
+// Change lists the facts of one function that differ between snapshots.
+type Change struct {
+	Func    string
+	Removed []Fact // in the old snapshot only
+	Added   []Fact // in the new snapshot only
+}
+
+// Diff compares two snapshots fact by fact and groups the differences by
+// function, in function order.
+func Diff(old, cur Snapshot) []Change {
+	inOld := map[Fact]bool{}
+	for _, f := range old.Facts {
+		inOld[f] = true
+	}
+	inNew := map[Fact]bool{}
+	for _, f := range cur.Facts {
+		inNew[f] = true
+	}
+	byFunc := map[string]*Change{}
+	var funcs []string
+	change := func(fn string) *Change {
+		c, ok := byFunc[fn]
+		if !ok {
+			c = &Change{Func: fn}
+			byFunc[fn] = c
+			funcs = append(funcs, fn)
+		}
+		return c
+	}
+	for _, f := range old.Facts {
+		if !inNew[f] {
+			c := change(f.Func)
+			c.Removed = append(c.Removed, f)
+		}
+	}
+	for _, f := range cur.Facts {
+		if !inOld[f] {
+			c := change(f.Func)
+			c.Added = append(c.Added, f)
+		}
+	}
+	slices.Sort(funcs)
+	changes := make([]Change, 0, len(funcs))
+	for _, fn := range funcs {
+		changes = append(changes, *byFunc[fn])
+	}
+	return changes
+}
+
+// FormatChanges writes changes as "- fact" / "+ fact" lines under each
+// function name.
+func FormatChanges(w io.Writer, changes []Change) {
+	for _, c := range changes {
+		fmt.Fprintf(w, "%s\n", c.Func)
+		for _, f := range c.Removed {
+			fmt.Fprintf(w, "  - %s\n", f)
+		}
+		for _, f := range c.Added {
+			fmt.Fprintf(w, "  + %s\n", f)
+		}
+	}
+}
+
+// goVersion returns the toolchain version the go command selects in dir.
+func goVersion(ctx context.Context, dir string) (string, error) {
+	cmd := exec.CommandContext(ctx, "go", "env", "GOVERSION")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		var exit *exec.ExitError
+		if errors.As(err, &exit) {
+			return "", fmt.Errorf("go env GOVERSION: %w\n%s", err, exit.Stderr)
+		}
+		return "", fmt.Errorf("go env GOVERSION: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,93 @@
+package escape
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	diags, err := Parse(strings.NewReader(sampleOutput))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Parse leaves Func empty for positions it cannot attribute by message.
+	for i := range diags {
+		if diags[i].Func == "" {
+			diags[i].Func = "Stack"
+		}
+	}
+	r := &Report{GoVersion: "go1.26.0", Diagnostics: diags}
+	s := r.Snapshot()
+
+	var buf bytes.Buffer
+	if err := s.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"# go1.26.0\n",
+		"(*T).Get\tleaking-param\tt\tto result ~r0 level=0\n",
+		"Heap\tcapture\tx\tby ref\n",
+		"Stack\tcannot-inline\tStack\tmarked go:noinline\n",
+		"Stack\tinlining-call\tStack.func1\t\n",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("snapshot lacks %q:\n%s", want, buf.String())
+		}
+	}
+
+	got, err := ReadSnapshot(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.GoVersion != s.GoVersion || len(got.Facts) != len(s.Facts) {
+		t.Fatalf("ReadSnapshot = %+v, want %+v", got, s)
+	}
+	if d := Diff(s, got); len(d) != 0 {
+		t.Errorf("Diff after round trip = %+v, want none", d)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	old := Snapshot{Facts: []Fact{
+		{"PatternC", CanInline, "PatternC", ""},
+		{"PatternC", Escapes, "func literal", ""},
+		{"Sum", CannotInline, "Sum", "marked go:noinline"},
+	}}
+	cur := Snapshot{Facts: []Fact{
+		{"PatternC", CannotInline, "PatternC", "function too complex"},
+		{"PatternC", Escapes, "func literal", ""},
+		{"Sum", CannotInline, "Sum", "marked go:noinline"},
+	}}
+	changes := Diff(old, cur)
+	if len(changes) != 1 || changes[0].Func != "PatternC" || len(changes[0].Removed) != 1 || len(changes[0].Added) != 1 {
+		t.Fatalf("Diff = %+v", changes)
+	}
+	var buf bytes.Buffer
+	FormatChanges(&buf, changes)
+	want := "PatternC\n  - can-inline PatternC\n  + cannot-inline PatternC (function too complex)\n"
+	if buf.String() != want {
+		t.Errorf("FormatChanges =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestSnapshotDropsCost(t *testing.T) {
+	r := &Report{Diagnostics: []Diagnostic{
+		classifyAt("F", "cannot inline F: function too complex: cost 95 exceeds budget 80"),
+		classifyAt("F", "inlining call to G"),
+		classifyAt("F", "inlining call to G"),
+	}}
+	s := r.Snapshot()
+	if s.Facts[0].Detail != "function too complex" {
+		t.Errorf("detail = %q, want cost dropped", s.Facts[0].Detail)
+	}
+	if s.Facts[2].Subject != "G #2" {
+		t.Errorf("repeated subject = %q, want %q", s.Facts[2].Subject, "G #2")
+	}
+}
+
+func classifyAt(fn, msg string) Diagnostic {
+	d := classify(msg)
+	d.Func = fn
+	return d
+}
@@ -0,0 +1,19 @@
+package sample
+
+type T struct{ n int }
+
+//go:noinline
+func Stack() int {
+	x := 0
+	f := func() { x++ }
+	f()
+	return x
+}
+
+//go:noinline
+func Heap() func() int {
+	x := 0
+	return func() int { x++; return x }
+}
+
+func (t *T) Get() *int { return &t.n }
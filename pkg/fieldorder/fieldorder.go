@@ -0,0 +1,267 @@
+// Package fieldorder finds struct types whose fields could be reordered to
+// save memory. It type-checks a package with go/types and, for every named
+// struct, computes the size and pointer-prefix length of the declared order
+// and of the optimal order under the gc size rules of several GOARCHes.
+//
+// The pointer prefix ("ptrdata") is the number of leading bytes the garbage
+// collector has to scan; moving pointer fields to the front shortens it.
+package fieldorder
+
+import (
+	"cmp"
+	"fmt"
+	"go/token"
+	"go/types"
+	"slices"
+	"strings"
+
+	"go-lab/pkg/internal/typecheck"
+)
+
+// Arches are the GOARCH values analyzed by default: 64-bit, 32-bit and wasm.
+var Arches = []string{"amd64", "arm64", "386", "arm", "wasm"}
+
+// ArchSize compares the declared and optimal orders on one GOARCH.
+type ArchSize struct {
+	Arch            string
+	Size            int64    // unsafe.Sizeof of the declared order
+	OptimalSize     int64    // unsafe.Sizeof of Order
+	PtrBytes        int64    // pointer prefix of the declared order
+	OptimalPtrBytes int64    // pointer prefix of Order
+	Order           []string // optimal field order
+}
+
+// Saving reports the bytes saved per value by the optimal order.
+func (a ArchSize) Saving() int64 { return a.Size - a.OptimalSize }
+
+// Struct is the analysis of one named struct type.
+type Struct struct {
+	Name   string
+	Pos    token.Position
+	Fields []string // declared order
+	Sizes  []ArchSize
+}
+
+// Reorderable reports whether the optimal order is smaller, or has a
+// shorter pointer prefix, on any analyzed GOARCH.
+func (s Struct) Reorderable() bool {
+	return slices.ContainsFunc(s.Sizes, func(a ArchSize) bool {
+		return a.OptimalSize < a.Size || a.OptimalPtrBytes < a.PtrBytes
+	})
+}
+
+// Arch returns the sizes for arch.
+func (s Struct) Arch(arch string) (ArchSize, bool) {
+	i := slices.IndexFunc(s.Sizes, func(a ArchSize) bool { return a.Arch == arch })
+	if i < 0 {
+		return ArchSize{}, false
+	}
+	return s.Sizes[i], true
+}
+
+// Table renders the per-GOARCH comparison of s as a markdown table.
+func (s Struct) Table() string {
+	var sb strings.Builder
+	sb.WriteString("| GOARCH | Size | Optimal | Saving | Ptr prefix | Optimal ptr prefix |\n")
+	sb.WriteString("| :--- | ---: | ---: | ---: | ---: | ---: |\n")
+	for _, a := range s.Sizes {
+		fmt.Fprintf(&sb, "| %s | %d B | %d B | %d B | %d B | %d B |\n",
+			a.Arch, a.Size, a.OptimalSize, a.Saving(), a.PtrBytes, a.OptimalPtrBytes)
+	}
+	return sb.String()
+}
+
+// AnalyzeDir type-checks the package in dir (non-test files matching the
+// host build constraints) and analyzes its named struct types for arches
+// (Arches when none are given).
+func AnalyzeDir(dir string, arches ...string) ([]Struct, error) {
+	if err := checkArches(arches); err != nil {
+		return nil, err
+	}
+	pkg, fset, err := typecheck.Dir(dir, "", nil)
+	if err != nil {
+		return nil, fmt.Errorf("fieldorder: %w", err)
+	}
+	return Analyze(pkg, fset, arches...)
+}
+
+// Analyze analyzes the named struct types declared at package level in pkg.
+// It fails if an arch has no gc size model.
+func Analyze(pkg *types.Package, fset *token.FileSet, arches ...string) ([]Struct, error) {
+	if err := checkArches(arches); err != nil {
+		return nil, err
+	}
+	if len(arches) == 0 {
+		arches = Arches
+	}
+	scope := pkg.Scope()
+	var out []Struct
+	for _, name := range scope.Names() {
+		tn, ok := scope.Lookup(name).(*types.TypeName)
+		if !ok || tn.IsAlias() {
+			continue
+		}
+		st, ok := tn.Type().Underlying().(*types.Struct)
+		if !ok || st.NumFields() == 0 {
+			continue
+		}
+		if named, ok := tn.Type().(*types.Named); ok && named.TypeParams().Len() > 0 {
+			continue // sizes depend on the instantiation
+		}
+		s := Struct{Name: name, Pos: fset.Position(tn.Pos()), Fields: fieldNames(st, indices(st.NumFields()))}
+		for _, arch := range arches {
+			s.Sizes = append(s.Sizes, analyzeArch(st, arch))
+		}
+		out = append(out, s)
+	}
+	slices.SortFunc(out, func(a, b Struct) int {
+		return cmp.Or(cmp.Compare(a.Pos.Filename, b.Pos.Filename), cmp.Compare(a.Pos.Offset, b.Pos.Offset))
+	})
+	return out, nil
+}
+
+// checkArches reports the first arch that types.SizesFor does not know.
+func checkArches(arches []string) error {
+	for _, arch := range arches {
+		if types.SizesFor("gc", arch) == nil {
+			return fmt.Errorf("fieldorder: unknown GOARCH %q", arch)
+		}
+	}
+	return nil
+}
+
+func analyzeArch(st *types.Struct, arch string) ArchSize {
+	sizes := types.SizesFor("gc", arch)
+	declared := indices(st.NumFields())
+	order := optimalOrder(st, sizes)
+	return ArchSize{
+		Arch:            arch,
+		Size:            structSize(st, declared, sizes),
+		OptimalSize:     structSize(st, order, sizes),
+		PtrBytes:        structPtrdata(st, declared, sizes),
+		OptimalPtrBytes: structPtrdata(st, order, sizes),
+		Order:           fieldNames(st, order),
+	}
+}
+
+// optimalOrder sorts the fields of st for minimal size first and minimal
+// pointer prefix second: zero-size fields first, then by decreasing
+// alignment; within an alignment class pointer-bearing fields come first,
+// those with the least trailing pointer-free bytes leading.
+func optimalOrder(st *types.Struct, sizes types.Sizes) []int {
+	n := st.NumFields()
+	type info struct {
+		align, size, ptrdata int64
+	}
+	fields := make([]info, n)
+	for i := range n {
+		t := st.Field(i).Type()
+		fields[i] = info{sizes.Alignof(t), sizes.Sizeof(t), ptrdata(t, sizes)}
+	}
+	order := indices(n)
+	slices.SortStableFunc(order, func(i, j int) int {
+		a, b := fields[i], fields[j]
+		if c := cmp.Compare(b2i(a.size == 0), b2i(b.size == 0)); c != 0 {
+			return -c
+		}
+		if c := cmp.Compare(b.align, a.align); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(b2i(a.ptrdata != 0), b2i(b.ptrdata != 0)); c != 0 {
+			return -c
+		}
+		if a.ptrdata != 0 {
+			return cmp.Compare(a.size-a.ptrdata, b.size-b.ptrdata)
+		}
+		return cmp.Compare(b.size, a.size)
+	})
+	return order
+}
+
+// structSize computes unsafe.Sizeof of st with its fields in order.
+func structSize(st *types.Struct, order []int, sizes types.Sizes) int64 {
+	_, end, maxAlign := layout(st, order, sizes)
+	if n := len(order); n > 0 && end > 0 && sizes.Sizeof(st.Field(order[n-1]).Type()) == 0 {
+		end++ // a trailing zero-size field is padded, as gc does
+	}
+	return align(end, maxAlign)
+}
+
+// structPtrdata computes the pointer prefix of st with its fields in order.
+func structPtrdata(st *types.Struct, order []int, sizes types.Sizes) int64 {
+	offsets, _, _ := layout(st, order, sizes)
+	var p int64
+	for k, i := range order {
+		if d := ptrdata(st.Field(i).Type(), sizes); d > 0 {
+			p = offsets[k] + d
+		}
+	}
+	return p
+}
+
+func layout(st *types.Struct, order []int, sizes types.Sizes) (offsets []int64, end, maxAlign int64) {
+	maxAlign = 1
+	for _, i := range order {
+		t := st.Field(i).Type()
+		a := sizes.Alignof(t)
+		maxAlign = max(maxAlign, a)
+		end = align(end, a)
+		offsets = append(offsets, end)
+		end += sizes.Sizeof(t)
+	}
+	return offsets, end, maxAlign
+}
+
+// ptrdata returns the length of the prefix of t that contains pointers.
+func ptrdata(t types.Type, sizes types.Sizes) int64 {
+	word := sizes.Sizeof(types.Typ[types.Uintptr])
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		switch u.Kind() {
+		case types.String, types.UnsafePointer:
+			return word
+		}
+		return 0
+	case *types.Pointer, *types.Signature, *types.Map, *types.Chan, *types.Slice:
+		return word
+	case *types.Interface:
+		return 2 * word
+	case *types.Array:
+		if u.Len() == 0 {
+			return 0
+		}
+		d := ptrdata(u.Elem(), sizes)
+		if d == 0 {
+			return 0
+		}
+		return (u.Len()-1)*sizes.Sizeof(u.Elem()) + d
+	case *types.Struct:
+		return structPtrdata(u, indices(u.NumFields()), sizes)
+	}
+	return 0
+}
+
+func b2i(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func align(x, a int64) int64 { return (x + a - 1) / a * a }
+
+func indices(n int) []int {
+	idx := make([]int, n)
+	for i := range idx {
+		idx[i] = i
+	}
+	return idx
+}
+
+func fieldNames(st *types.Struct, order []int) []string {
+	names := make([]string, len(order))
+	for k, i := range order {
+		names[k] = st.Field(i).Name()
+	}
+	return names
+}
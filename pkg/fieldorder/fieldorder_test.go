@@ -0,0 +1,104 @@
+package fieldorder
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestAnalyzeDir(t *testing.T) {
+	structs, err := AnalyzeDir("testdata/sample")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, s := range structs {
+		names = append(names, s.Name)
+	}
+	if !slices.Equal(names, []string{"Unpadded", "Padded", "Mixed"}) {
+		t.Fatalf("structs = %q, want source order", names)
+	}
+
+	tests := []struct {
+		arch        string
+		size, opt   int64
+		ptr, optPtr int64
+	}{
+		{"amd64", 24, 16, 0, 0},
+		{"arm64", 24, 16, 0, 0},
+		{"386", 20, 16, 0, 0}, // int64 is 4-aligned on 32-bit arches
+		{"arm", 20, 16, 0, 0},
+		{"wasm", 24, 16, 0, 0},
+	}
+	for _, tt := range tests {
+		a, ok := structs[0].Arch(tt.arch)
+		if !ok || a.Size != tt.size || a.OptimalSize != tt.opt || a.PtrBytes != tt.ptr || a.OptimalPtrBytes != tt.optPtr {
+			t.Errorf("Unpadded on %s = %+v, want size %d→%d", tt.arch, a, tt.size, tt.opt)
+		}
+	}
+	if a, _ := structs[0].Arch("amd64"); !slices.Equal(a.Order, []string{"b", "d", "a", "c"}) {
+		t.Errorf("optimal order = %q, want the hand-made Padded order", a.Order)
+	}
+	if got := structs[0].Table(); !strings.Contains(got, "| amd64 | 24 B | 16 B | 8 B | 0 B | 0 B |\n") {
+		t.Errorf("Table() =\n%s", got)
+	}
+	if !structs[0].Reorderable() || structs[1].Reorderable() {
+		t.Errorf("Reorderable = %v, %v; want true, false", structs[0].Reorderable(), structs[1].Reorderable())
+	}
+
+	// Mixed: pointers move to the front, shortening the GC scan prefix.
+	// The size is already minimal (sync.Mutex is 8 B).
+	m, _ := structs[2].Arch("amd64")
+	if m.Size != 40 || m.OptimalSize != 40 || m.PtrBytes != 40 || m.OptimalPtrBytes != 16 {
+		t.Errorf("Mixed on amd64 = %+v, want 40 B, ptr prefix 40 → 16", m)
+	}
+	if !slices.Equal(m.Order, []string{"next", "name", "n", "mu"}) {
+		t.Errorf("Mixed order = %q", m.Order)
+	}
+}
+
+func TestUnknownArch(t *testing.T) {
+	_, err := AnalyzeDir("testdata/sample", "amd64", "foo")
+	if err == nil || !strings.Contains(err.Error(), `unknown GOARCH "foo"`) {
+		t.Errorf("AnalyzeDir(amd64, foo) error = %v, want unknown GOARCH", err)
+	}
+}
+
+// TestSizesMatchGoTypes checks the declared-order computation against
+// types.Sizes for a range of field kinds.
+func TestSizesMatchGoTypes(t *testing.T) {
+	const src = `package p
+type Z struct{}
+type A struct { a byte; z Z }
+type B struct { s []int; b bool; i interface{}; f func(); arr [3]*int; u uint16 }
+type C struct { x [0]int64; b bool; c complex128; m map[int]int }
+`
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "p.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg, err := new(types.Config).Check("p", fset, []*ast.File{f}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	structs, err := Analyze(pkg, fset)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range structs {
+		st := pkg.Scope().Lookup(s.Name).Type().Underlying()
+		for _, a := range s.Sizes {
+			if want := types.SizesFor("gc", a.Arch).Sizeof(st); a.Size != want {
+				t.Errorf("%s on %s: size %d, types.Sizes says %d", s.Name, a.Arch, a.Size, want)
+			}
+			if a.OptimalSize > a.Size {
+				t.Errorf("%s on %s: optimal %d > declared %d", s.Name, a.Arch, a.OptimalSize, a.Size)
+			}
+		}
+	}
+}
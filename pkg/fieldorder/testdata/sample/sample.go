@@ -0,0 +1,24 @@
+package sample
+
+import "sync"
+
+type Unpadded struct {
+	a bool
+	b int64
+	c bool
+	d int32
+}
+
+type Padded struct {
+	b int64
+	d int32
+	a bool
+	c bool
+}
+
+type Mixed struct {
+	n    int64
+	name string
+	mu   sync.Mutex
+	next *Mixed
+}
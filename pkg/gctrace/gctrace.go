@@ -0,0 +1,130 @@
+// Package gctrace runs benchmarks under GODEBUG=gctrace=1 and parses the
+// runtime's per-cycle GC trace lines into typed records, so that memory
+// layout differences show up as GC work and not only as ns/op.
+//
+// A trace line has the form
+//
+//	gc 7 @0.035s 3%: 0.009+0.14+0.002 ms clock, 0.009+0.073/0/0+0.002 ms cpu, 32->32->16 MB, 32 MB goal, 0 MB stacks, 0 MB globals, 8 P (forced)
+//
+// see the GODEBUG section of the runtime package documentation.
+package gctrace
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// mib is the unit of the heap sizes in trace lines, printed as "MB".
+const mib = 1 << 20
+
+// Cycle is one GC cycle of a gctrace=1 trace.
+type Cycle struct {
+	Num        int           // GC number, incremented at each GC
+	At         time.Duration // time since program start
+	CPUPercent float64       // share of CPU time spent in GC since program start
+
+	// Wall-clock phase times.
+	SweepTerm time.Duration // stop-the-world sweep termination
+	Mark      time.Duration // concurrent mark and scan
+	MarkTerm  time.Duration // stop-the-world mark termination
+
+	// CPU phase times.
+	SweepTermCPU  time.Duration
+	AssistCPU     time.Duration // mark assists performed by allocating goroutines
+	BackgroundCPU time.Duration // dedicated and fractional background marking
+	IdleCPU       time.Duration // idle-time marking
+	MarkTermCPU   time.Duration
+
+	// Sizes in bytes; the trace prints them in whole MiB.
+	HeapStart uint64 // heap at GC start
+	HeapEnd   uint64 // heap at GC end
+	HeapLive  uint64 // live heap after marking
+	HeapGoal  uint64
+	Stacks    uint64 // scannable stacks
+	Globals   uint64 // scannable globals
+
+	Procs  int  // GOMAXPROCS
+	Forced bool // forced by runtime.GC or debug.FreeOSMemory
+}
+
+// STW returns the total stop-the-world time of the cycle.
+func (c Cycle) STW() time.Duration { return c.SweepTerm + c.MarkTerm }
+
+// CPU returns the total CPU time of the cycle.
+func (c Cycle) CPU() time.Duration {
+	return c.SweepTermCPU + c.AssistCPU + c.BackgroundCPU + c.IdleCPU + c.MarkTermCPU
+}
+
+var lineRE = regexp.MustCompile(`^gc (\d+) @([\d.]+)s (\d+)%: ` +
+	`([\d.]+)\+([\d.]+)\+([\d.]+) ms clock, ` +
+	`([\d.]+)\+([\d.]+)/([\d.]+)/([\d.]+)\+([\d.]+) ms cpu, ` +
+	`(\d+)->(\d+)->(\d+) MB, (\d+) MB goal, ` +
+	`(?:(\d+) MB stacks, )?(?:(\d+) MB globals, )?(\d+) P( \(forced\))?$`)
+
+// ParseLine parses one gctrace line. Lines that are not GC cycle records,
+// such as scavenger traces or benchmark output, are reported as not ok.
+func ParseLine(line string) (Cycle, bool) {
+	m := lineRE.FindStringSubmatch(line)
+	if m == nil {
+		return Cycle{}, false
+	}
+	num, _ := strconv.Atoi(m[1])
+	procs, _ := strconv.Atoi(m[18])
+	c := Cycle{
+		Num:           num,
+		At:            seconds(m[2]),
+		CPUPercent:    float(m[3]),
+		SweepTerm:     millis(m[4]),
+		Mark:          millis(m[5]),
+		MarkTerm:      millis(m[6]),
+		SweepTermCPU:  millis(m[7]),
+		AssistCPU:     millis(m[8]),
+		BackgroundCPU: millis(m[9]),
+		IdleCPU:       millis(m[10]),
+		MarkTermCPU:   millis(m[11]),
+		HeapStart:     mb(m[12]),
+		HeapEnd:       mb(m[13]),
+		HeapLive:      mb(m[14]),
+		HeapGoal:      mb(m[15]),
+		Stacks:        mb(m[16]),
+		Globals:       mb(m[17]),
+		Procs:         procs,
+		Forced:        m[19] != "",
+	}
+	return c, true
+}
+
+// Parse reads a trace and returns its GC cycles in order; other lines are
+// skipped.
+func Parse(r io.Reader) ([]Cycle, error) {
+	var cycles []Cycle
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	for sc.Scan() {
+		if c, ok := ParseLine(sc.Text()); ok {
+			cycles = append(cycles, c)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("read gctrace: %w", err)
+	}
+	return cycles, nil
+}
+
+func float(s string) float64 {
+	f, _ := strconv.ParseFloat(s, 64) // the regexp admits only numbers
+	return f
+}
+
+func seconds(s string) time.Duration { return time.Duration(float(s) * float64(time.Second)) }
+
+func millis(s string) time.Duration { return time.Duration(float(s) * float64(time.Millisecond)) }
+
+func mb(s string) uint64 {
+	n, _ := strconv.ParseUint(s, 10, 64)
+	return n * mib
+}
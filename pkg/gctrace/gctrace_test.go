@@ -0,0 +1,79 @@
+package gctrace
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const trace = `BenchmarkAllocUnpadded
+gc 1 @0.004s 2%: 0.026+0.39+0.003 ms clock, 0.21+0.11/0.32/0.10+0.024 ms cpu, 24->24->0 MB, 25 MB goal, 0 MB stacks, 0 MB globals, 8 P
+scvg: 0 MB released
+gc 2 @0.012s 3%: 0.009+0.14+0.002 ms clock, 0.009+0.073/0/0+0.002 ms cpu, 48->48->24 MB, 49 MB goal, 8 P
+gc 3 @0.038s 3%: 0.009+0.082+0.003 ms clock, 0.009+0/0.014/0.058+0.003 ms cpu, 24->24->16 MB, 32 MB goal, 0 MB stacks, 0 MB globals, 8 P (forced)
+`
+
+func TestParse(t *testing.T) {
+	cycles, err := Parse(strings.NewReader(trace))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cycles) != 3 {
+		t.Fatalf("parsed %d cycles, want 3", len(cycles))
+	}
+	c := cycles[0]
+	want := Cycle{
+		Num: 1, At: 4 * time.Millisecond, CPUPercent: 2,
+		SweepTerm: 26 * time.Microsecond, Mark: 390 * time.Microsecond, MarkTerm: 3 * time.Microsecond,
+		SweepTermCPU: 210 * time.Microsecond, AssistCPU: 110 * time.Microsecond,
+		BackgroundCPU: 320 * time.Microsecond, IdleCPU: 100 * time.Microsecond, MarkTermCPU: 24 * time.Microsecond,
+		HeapStart: 24 << 20, HeapEnd: 24 << 20, HeapLive: 0, HeapGoal: 25 << 20,
+		Procs: 8,
+	}
+	if c != want {
+		t.Errorf("cycle 1 = %+v\nwant      %+v", c, want)
+	}
+	if c.STW() != 29*time.Microsecond || c.CPU() != 764*time.Microsecond {
+		t.Errorf("STW = %v, CPU = %v; want 29µs, 764µs", c.STW(), c.CPU())
+	}
+	if cycles[1].HeapLive != 24<<20 || cycles[1].Stacks != 0 {
+		t.Errorf("cycle 2 (no stacks/globals) = %+v", cycles[1])
+	}
+	if !cycles[2].Forced || cycles[1].Forced {
+		t.Error("forced flag not parsed")
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	cycles, err := Parse(strings.NewReader(trace))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := Summarize(Bench{Name: "BenchmarkAllocUnpadded", Cycles: cycles})
+	if s.Name != "AllocUnpadded" || s.Cycles != 2 || s.Forced != 1 || s.PeakHeap != 48<<20 ||
+		s.MeanLive != 12<<20 || s.CPUPercent != 3 {
+		t.Errorf("Summarize = %+v", s)
+	}
+	if got := Table([]Summary{s}); !strings.Contains(got, "| AllocUnpadded | 0 | 2 | 0 | 20µs | 265µs | 424µs | 3% | 48 MiB | 12 MiB | 37 MiB |") {
+		t.Errorf("Table:\n%s", got)
+	}
+}
+
+func TestRun(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds and runs a test binary")
+	}
+	benchs, err := Run(t.Context(), "testdata/alloc", Options{Benchtime: "20x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(benchs) != 2 || benchs[0].Name != "BenchmarkAlloc" || benchs[1].Name != "BenchmarkNoAlloc" {
+		t.Fatalf("Run = %+v", benchs)
+	}
+	if len(benchs[0].Results) != 1 || len(benchs[0].Cycles) == 0 {
+		t.Errorf("BenchmarkAlloc: %d results, %d GC cycles; want 1 and some", len(benchs[0].Results), len(benchs[0].Cycles))
+	}
+	if s := Summarize(benchs[1]); s.Cycles != 0 || s.Forced == 0 {
+		t.Errorf("BenchmarkNoAlloc: %d GC cycles, %d forced; want 0 and the testing package's", s.Cycles, s.Forced)
+	}
+}
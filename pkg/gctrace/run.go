@@ -0,0 +1,195 @@
+package gctrace
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-lab/pkg/benchfmt"
+	"go-lab/pkg/stats"
+)
+
+// Options select the benchmarks to trace and how long they run.
+type Options struct {
+	Bench     string // regexp over top-level benchmark names; empty for all
+	Count     int    // -count; 0 for 1
+	Benchtime string // -benchtime, e.g. "1s" or "100x"
+}
+
+// Bench is the trace of one top-level benchmark, which runs in a process of
+// its own so that every GC cycle of the process belongs to it. The cycles
+// include the runs that determine b.N; with -benchtime=Nx there is only one
+// such run, of a single iteration.
+type Bench struct {
+	Name    string            // e.g. BenchmarkAllocUnpadded
+	Results []benchfmt.Result // result lines, including sub-benchmarks
+	Cycles  []Cycle
+}
+
+// Run compiles the test binary of the package in dir and runs each
+// top-level benchmark matching opts.Bench under GODEBUG=gctrace=1.
+func Run(ctx context.Context, dir string, opts Options) ([]Bench, error) {
+	filter, err := regexp.Compile(opts.Bench)
+	if err != nil {
+		return nil, fmt.Errorf("gctrace: bench: %w", err)
+	}
+	tmp, err := os.MkdirTemp("", "gctrace")
+	if err != nil {
+		return nil, fmt.Errorf("gctrace: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+	bin := filepath.Join(tmp, "pkg.test")
+	build := exec.CommandContext(ctx, "go", "test", "-c", "-o", bin, ".")
+	build.Dir = dir
+	if out, err := build.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("go test -c: %w\n%s", err, out)
+	}
+	list := exec.CommandContext(ctx, bin, "-test.list", "^Benchmark")
+	list.Dir = dir
+	out, err := list.Output()
+	if err != nil {
+		return nil, fmt.Errorf("gctrace: list benchmarks: %w", err)
+	}
+
+	var benchs []Bench
+	for name := range strings.FieldsSeq(string(out)) {
+		if !strings.HasPrefix(name, "Benchmark") || !filter.MatchString(name) {
+			continue
+		}
+		b, err := runOne(ctx, bin, dir, name, opts)
+		if err != nil {
+			return nil, err
+		}
+		benchs = append(benchs, b)
+	}
+	return benchs, nil
+}
+
+func runOne(ctx context.Context, bin, dir, name string, opts Options) (Bench, error) {
+	args := []string{"-test.run", "^$", "-test.bench", "^" + regexp.QuoteMeta(name) + "$", "-test.benchmem"}
+	if opts.Count > 0 {
+		args = append(args, "-test.count", strconv.Itoa(opts.Count))
+	}
+	if opts.Benchtime != "" {
+		args = append(args, "-test.benchtime", opts.Benchtime)
+	}
+	cmd := exec.CommandContext(ctx, bin, args...)
+	cmd.Dir = dir
+	godebug := "gctrace=1"
+	if v := os.Getenv("GODEBUG"); v != "" {
+		godebug = v + "," + godebug
+	}
+	cmd.Env = append(os.Environ(), "GODEBUG="+godebug)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return Bench{}, fmt.Errorf("%s: %w\n%s", name, err, stdout.Bytes())
+	}
+	results, err := benchfmt.Parse(&stdout)
+	if err != nil {
+		return Bench{}, fmt.Errorf("%s: %w", name, err)
+	}
+	cycles, err := Parse(&stderr)
+	if err != nil {
+		return Bench{}, fmt.Errorf("%s: %w", name, err)
+	}
+	return Bench{Name: name, Results: results, Cycles: cycles}, nil
+}
+
+// Summary condenses the trace of one benchmark. The testing package forces
+// a GC before every run of a benchmark function; forced cycles are counted
+// in Forced and left out of every other figure.
+type Summary struct {
+	Name       string
+	NsPerOp    float64 // median over the result lines
+	Ops        int64   // iterations summed over the result lines
+	Cycles     int     // GC cycles triggered by the heap goal
+	Forced     int
+	STW        time.Duration // total stop-the-world time
+	Mark       time.Duration // total concurrent mark time
+	CPU        time.Duration // total GC CPU time
+	CPUPercent float64       // share of CPU spent in GC by the end of the run
+	PeakHeap   uint64        // largest heap at GC start
+	MeanLive   uint64        // mean live heap after marking
+	MeanGoal   uint64        // mean heap goal
+}
+
+// Summarize condenses b.
+func Summarize(b Bench) Summary {
+	s := Summary{Name: strings.TrimPrefix(b.Name, "Benchmark")}
+	var ns []float64
+	for _, r := range b.Results {
+		s.Ops += r.Iterations
+		if v, ok := r.Get("ns/op"); ok {
+			ns = append(ns, v)
+		}
+	}
+	if len(ns) > 0 {
+		s.NsPerOp = stats.Summarize(ns, stats.DefaultConfidence).Median
+	}
+	var live, goal uint64
+	for _, c := range b.Cycles {
+		if c.Forced {
+			s.Forced++
+			continue
+		}
+		s.Cycles++
+		s.STW += c.STW()
+		s.Mark += c.Mark
+		s.CPU += c.CPU()
+		s.CPUPercent = c.CPUPercent
+		s.PeakHeap = max(s.PeakHeap, c.HeapStart)
+		live += c.HeapLive
+		goal += c.HeapGoal
+	}
+	if n := uint64(s.Cycles); n > 0 {
+		s.MeanLive, s.MeanGoal = live/n, goal/n
+	}
+	return s
+}
+
+// OpsPerCycle returns the benchmark iterations per GC cycle, an upper
+// bound on the GC-free work between cycles (see Bench).
+func (s Summary) OpsPerCycle() float64 {
+	if s.Cycles == 0 {
+		return 0
+	}
+	return float64(s.Ops) / float64(s.Cycles)
+}
+
+// Table renders summaries as a markdown table with per-cycle averages.
+func Table(ss []Summary) string {
+	var sb strings.Builder
+	sb.WriteString("| Benchmark | ns/op | GCs | ops/GC | STW/GC | mark/GC | GC CPU/GC | GC CPU % | peak heap | live heap | goal |\n")
+	sb.WriteString("| :--- | ---: | ---: | ---: | ---: | ---: | ---: | ---: | ---: | ---: | ---: |\n")
+	for _, s := range ss {
+		per := func(d time.Duration) string {
+			if s.Cycles == 0 {
+				return "-"
+			}
+			return (d / time.Duration(s.Cycles)).Round(time.Microsecond).String()
+		}
+		fmt.Fprintf(&sb, "| %s | %s | %d | %.4g | %s | %s | %s | %.0f%% | %s | %s | %s |\n",
+			s.Name, formatNs(s.NsPerOp), s.Cycles, s.OpsPerCycle(),
+			per(s.STW), per(s.Mark), per(s.CPU), s.CPUPercent,
+			mibString(s.PeakHeap), mibString(s.MeanLive), mibString(s.MeanGoal))
+	}
+	return sb.String()
+}
+
+func formatNs(v float64) string {
+	if v >= 1000 {
+		return strconv.FormatFloat(v, 'f', 0, 64)
+	}
+	return strconv.FormatFloat(v, 'g', 4, 64)
+}
+
+func mibString(b uint64) string { return strconv.FormatUint(b/mib, 10) + " MiB" }
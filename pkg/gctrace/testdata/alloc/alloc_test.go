@@ -0,0 +1,16 @@
+package alloc
+
+import "testing"
+
+var sink []byte
+
+func BenchmarkAlloc(b *testing.B) {
+	for b.Loop() {
+		sink = make([]byte, 8<<20)
+	}
+}
+
+func BenchmarkNoAlloc(b *testing.B) {
+	for b.Loop() {
+	}
+}
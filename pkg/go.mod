@@ -0,0 +1,3 @@
+module go-lab/pkg
+
+go 1.26.0
@@ -0,0 +1,164 @@
+// Package history keeps an append-only record of benchmark results per
+// experiment, one JSON line per run, keyed by commit, Go version, GOAMD64
+// level and CPU fingerprint. Series and Compare turn the record into
+// evolution tables and flag significant regressions and improvements.
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"go-lab/pkg/benchfmt"
+	"go-lab/pkg/runner"
+)
+
+// Dir is the workspace-relative default directory of the history files.
+const Dir = ".lab/history"
+
+// Key identifies what a set of results was measured with.
+type Key struct {
+	Commit    string `json:"commit"`
+	Dirty     bool   `json:"dirty,omitempty"`
+	GoVersion string `json:"go_version"`
+	GOAMD64   string `json:"goamd64,omitempty"`
+	CPU       string `json:"cpu"`             // sysinfo.Info.Fingerprint
+	Build     string `json:"build,omitempty"` // runner.Config.Build of matrix cells
+}
+
+// ShortCommit returns the abbreviated commit, with "+" when dirty.
+func (k Key) ShortCommit() string {
+	c := k.Commit
+	if len(c) > 7 {
+		c = c[:7]
+	}
+	if c == "" {
+		c = "-"
+	}
+	if k.Dirty {
+		c += "+"
+	}
+	return c
+}
+
+// Entry is the record of one experiment in one run.
+type Entry struct {
+	Run        string            `json:"run"` // runner.Run.ID
+	Time       time.Time         `json:"time"`
+	Topic      string            `json:"topic"`
+	Key        Key               `json:"key"`
+	GOOS       string            `json:"goos"`
+	GOARCH     string            `json:"goarch"`
+	CPUModel   string            `json:"cpu_model,omitempty"`
+	Benchmarks []benchfmt.Result `json:"benchmarks"`
+}
+
+// FromRun returns one entry per experiment of run that produced benchmarks.
+func FromRun(run *runner.Run) []Entry {
+	key := Key{
+		Commit:    run.Commit,
+		Dirty:     run.Dirty,
+		GoVersion: run.GoVersion,
+		GOAMD64:   run.GOAMD64,
+		CPU:       run.System.Fingerprint(),
+	}
+	if run.Config != nil {
+		key.Build = run.Config.Build()
+	}
+	var entries []Entry
+	for _, res := range run.Results {
+		if len(res.Benchmarks) == 0 {
+			continue
+		}
+		entries = append(entries, Entry{
+			Run:        run.ID,
+			Time:       run.Start,
+			Topic:      res.Topic,
+			Key:        key,
+			GOOS:       run.GOOS,
+			GOARCH:     run.GOARCH,
+			CPUModel:   run.System.CPU,
+			Benchmarks: res.Benchmarks,
+		})
+	}
+	return entries
+}
+
+func file(dir, topic string) string { return filepath.Join(dir, topic+".jsonl") }
+
+// Append adds e to the history file of its topic under dir. Entries are
+// never rewritten; an entry whose run is already recorded is skipped, so
+// importing the same run twice is harmless. It reports whether e was added.
+func Append(dir string, e Entry) (bool, error) {
+	existing, err := Load(dir, e.Topic)
+	if err != nil {
+		return false, err
+	}
+	if slices.ContainsFunc(existing, func(x Entry) bool { return x.Run == e.Run }) {
+		return false, nil
+	}
+	b, err := json.Marshal(e)
+	if err != nil {
+		return false, fmt.Errorf("encode history entry: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return false, fmt.Errorf("create history directory: %w", err)
+	}
+	f, err := os.OpenFile(file(dir, e.Topic), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return false, fmt.Errorf("open history: %w", err)
+	}
+	if _, err := f.Write(append(b, '\n')); err != nil {
+		f.Close()
+		return false, fmt.Errorf("append history: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return false, fmt.Errorf("append history: %w", err)
+	}
+	return true, nil
+}
+
+// Load reads the history of topic in recorded order. A topic without
+// history yields no entries and no error.
+func Load(dir, topic string) ([]Entry, error) {
+	f, err := os.Open(file(dir, topic))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open history: %w", err)
+	}
+	defer f.Close()
+	var entries []Entry
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 64*1024), 64<<20)
+	for n := 1; sc.Scan(); n++ {
+		if len(sc.Bytes()) == 0 {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("%s line %d: %w", file(dir, topic), n, err)
+		}
+		entries = append(entries, e)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("read history: %w", err)
+	}
+	return entries, nil
+}
+
+// Find returns the entry of run id.
+func Find(entries []Entry, id string) (Entry, bool) {
+	i := slices.IndexFunc(entries, func(e Entry) bool { return e.Run == id })
+	if i < 0 {
+		return Entry{}, false
+	}
+	return entries[i], true
+}
@@ -0,0 +1,113 @@
+package history
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"go-lab/pkg/benchfmt"
+)
+
+// entry builds an entry of run id with benchmark ConcatPlus measured at the
+// given ns/op values.
+func entry(t *testing.T, id, cpu string, ns ...float64) Entry {
+	t.Helper()
+	var sb strings.Builder
+	sb.WriteString("goos: linux\ngoarch: amd64\npkg: go-lab/experiments/string-concat\n")
+	for _, v := range ns {
+		fmt.Fprintf(&sb, "BenchmarkConcatPlus-8 \t 1000\t %g ns/op\t 64 B/op\n", v)
+	}
+	results, err := benchfmt.Parse(strings.NewReader(sb.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return Entry{
+		Run:        id,
+		Topic:      "string-concat",
+		Key:        Key{Commit: "0123456789abcdef", GoVersion: "go1.26.0", CPU: cpu},
+		GOOS:       "linux",
+		GOARCH:     "amd64",
+		Benchmarks: results,
+	}
+}
+
+func TestAppendLoad(t *testing.T) {
+	dir := t.TempDir()
+	if got, err := Load(dir, "string-concat"); err != nil || got != nil {
+		t.Fatalf("Load of missing history = %v, %v; want nil, nil", got, err)
+	}
+	a := entry(t, "20261017T100000Z", "cpu1", 100, 101, 102)
+	b := entry(t, "20261017T110000Z", "cpu1", 150, 151, 152)
+	for _, e := range []Entry{a, b, a} {
+		if _, err := Append(dir, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got, err := Load(dir, "string-concat")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].Run != a.Run || got[1].Run != b.Run {
+		t.Fatalf("Load = %d entries, want runs %s, %s (duplicate skipped)", len(got), a.Run, b.Run)
+	}
+	if len(got[1].Benchmarks) != 3 {
+		t.Errorf("entry has %d benchmark results, want 3", len(got[1].Benchmarks))
+	}
+	if _, ok := Find(got, b.Run); !ok {
+		t.Errorf("Find(%s) failed", b.Run)
+	}
+}
+
+func TestSeries(t *testing.T) {
+	entries := []Entry{
+		entry(t, "r1", "cpu1", 100, 101, 102, 103, 104),
+		entry(t, "r2", "cpu1", 100, 102, 101, 104, 103),
+		entry(t, "r3", "cpu1", 150, 151, 152, 153, 154),
+		entry(t, "r4", "cpu1", 90, 91, 92, 93, 94),
+		entry(t, "r5", "cpu2", 50, 51, 52, 53, 54),
+	}
+	points := Series(entries, "BenchmarkConcatPlus", "ns/op", 0.05)
+	want := []Verdict{"", Same, Regression, Improvement, Incomparable}
+	if len(points) != len(want) {
+		t.Fatalf("Series = %d points, want %d", len(points), len(want))
+	}
+	for i, p := range points {
+		if p.Verdict != want[i] {
+			t.Errorf("point %s verdict = %q, want %q", p.Entry.Run, p.Verdict, want[i])
+		}
+	}
+	if points[0].Change != nil {
+		t.Error("first point has a comparison")
+	}
+	table := SeriesTable(points, "ns/op")
+	if !strings.Contains(table, "| r3 | 0123456 | go1.26.0 | - | cpu1 | 152 ±1% | +49.02% | 0.008 | regression |") {
+		t.Errorf("SeriesTable:\n%s", table)
+	}
+
+	// B/op did not change; the same entries show no regression.
+	for _, p := range Series(entries[:4], "ConcatPlus", "B/op", 0.05)[1:] {
+		if p.Verdict != Same {
+			t.Errorf("B/op %s verdict = %q, want %q", p.Entry.Run, p.Verdict, Same)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	a := entry(t, "r1", "cpu1", 100, 101, 102, 103, 104)
+	b := entry(t, "r2", "cpu1", 150, 151, 152, 153, 154)
+	changes := Compare(a, b, "ns/op", 0.05)
+	if len(changes) != 1 || changes[0].Bench != "ConcatPlus" || changes[0].Verdict != Regression {
+		t.Fatalf("Compare = %+v, want one ConcatPlus regression", changes)
+	}
+	if got := Compare(b, a, "ns/op", 0.05)[0].Verdict; got != Improvement {
+		t.Errorf("reverse verdict = %q, want %q", got, Improvement)
+	}
+}
+
+func TestLowerIsBetter(t *testing.T) {
+	for unit, want := range map[string]bool{"ns/op": true, "B/op": true, "allocs/op": true, "MB/s": false} {
+		if got := LowerIsBetter(unit); got != want {
+			t.Errorf("LowerIsBetter(%q) = %v, want %v", unit, got, want)
+		}
+	}
+}
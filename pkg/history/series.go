@@ -0,0 +1,170 @@
+package history
+
+import (
+	"fmt"
+	"strings"
+
+	"go-lab/pkg/benchfmt"
+	"go-lab/pkg/stats"
+)
+
+// Verdict classifies the change of a benchmark between two entries.
+type Verdict string
+
+// Verdicts. Incomparable marks entries measured on different CPUs or
+// GOARCHes, whose difference says nothing about the code.
+const (
+	Same         Verdict = "~"
+	Regression   Verdict = "regression"
+	Improvement  Verdict = "improvement"
+	Incomparable Verdict = "incomparable"
+)
+
+// LowerIsBetter reports whether smaller values of unit are better: true for
+// per-op costs (ns/op, B/op, allocs/op), false for rates such as MB/s.
+func LowerIsBetter(unit string) bool { return !strings.HasSuffix(unit, "/s") }
+
+// judge classifies c for unit.
+func judge(c stats.Comparison, unit string) Verdict {
+	if !c.Significant() {
+		return Same
+	}
+	if (c.Delta > 0) == LowerIsBetter(unit) {
+		return Regression
+	}
+	return Improvement
+}
+
+func comparable(a, b Entry) bool {
+	return a.Key.CPU == b.Key.CPU && a.GOOS == b.GOOS && a.GOARCH == b.GOARCH
+}
+
+// benchKey names a result without its "Benchmark" prefix and GOMAXPROCS
+// suffix, e.g. "Insert_StringKey" or "ConcatPlus/N=64".
+func benchKey(r benchfmt.Result) string { return r.Name.Group(benchfmt.ProcsKey) }
+
+func samples(e Entry, bench, unit string) []float64 {
+	bench = strings.TrimPrefix(bench, "Benchmark")
+	var xs []float64
+	for _, r := range e.Benchmarks {
+		if benchKey(r) != bench {
+			continue
+		}
+		if v, ok := r.Get(unit); ok {
+			xs = append(xs, v)
+		}
+	}
+	return xs
+}
+
+// Point is one entry of a benchmark's series.
+type Point struct {
+	Entry   Entry
+	Summary stats.Summary
+	// Change compares the point with the previous one; nil for the first.
+	Change  *stats.Comparison
+	Verdict Verdict
+}
+
+// Series follows benchmark bench (with or without the "Benchmark" prefix,
+// without the GOMAXPROCS suffix) through entries, comparing every entry
+// with the previous one that measured it.
+func Series(entries []Entry, bench, unit string, alpha float64) []Point {
+	var (
+		points []Point
+		prev   []float64
+		last   Entry
+	)
+	for _, e := range entries {
+		xs := samples(e, bench, unit)
+		if len(xs) == 0 {
+			continue
+		}
+		p := Point{Entry: e, Summary: stats.Summarize(xs, stats.DefaultConfidence)}
+		if prev != nil {
+			c := stats.Compare(prev, xs, alpha)
+			p.Change = &c
+			p.Verdict = judge(c, unit)
+			if !comparable(last, e) {
+				p.Verdict = Incomparable
+			}
+		}
+		points = append(points, p)
+		prev, last = xs, e
+	}
+	return points
+}
+
+// Change is the comparison of one benchmark between two entries.
+type Change struct {
+	Bench      string
+	Comparison stats.Comparison
+	Verdict    Verdict
+}
+
+// Compare compares every benchmark measured in both a and b.
+func Compare(a, b Entry, unit string, alpha float64) []Change {
+	var (
+		keys []string
+		seen = map[string]bool{}
+	)
+	for _, r := range a.Benchmarks {
+		if k := benchKey(r); !seen[k] {
+			seen[k] = true
+			keys = append(keys, k)
+		}
+	}
+	var changes []Change
+	for _, k := range keys {
+		xa, xb := samples(a, k, unit), samples(b, k, unit)
+		if len(xa) == 0 || len(xb) == 0 {
+			continue
+		}
+		c := stats.Compare(xa, xb, alpha)
+		v := judge(c, unit)
+		if !comparable(a, b) {
+			v = Incomparable
+		}
+		changes = append(changes, Change{Bench: k, Comparison: c, Verdict: v})
+	}
+	return changes
+}
+
+// SeriesTable renders points as a markdown table.
+func SeriesTable(points []Point, unit string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "| Run | Commit | Go | GOAMD64 | CPU | %s | Δ prev | p | Verdict |\n", unit)
+	sb.WriteString("| :--- | :--- | :--- | :--- | :--- | ---: | ---: | ---: | :--- |\n")
+	for _, p := range points {
+		delta, pv := "", ""
+		if p.Change != nil {
+			delta = stats.FormatDelta(p.Change.Delta)
+			pv = fmt.Sprintf("%.3f", p.Change.P)
+		}
+		k := p.Entry.Key
+		fmt.Fprintf(&sb, "| %s | %s | %s | %s | %s | %s | %s | %s | %s |\n",
+			p.Entry.Run, k.ShortCommit(), k.GoVersion, dash(k.GOAMD64), k.CPU,
+			stats.FormatSummary(p.Summary), delta, pv, p.Verdict)
+	}
+	return sb.String()
+}
+
+// CompareTable renders changes between runs a and b as a markdown table.
+func CompareTable(a, b Entry, unit string, changes []Change) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "| %s | %s | %s | Diff | p | Verdict |\n", unit, a.Run, b.Run)
+	sb.WriteString("| :--- | ---: | ---: | ---: | ---: | :--- |\n")
+	for _, c := range changes {
+		fmt.Fprintf(&sb, "| %s | %s | %s | %s | %.3f | %s |\n", c.Bench,
+			stats.FormatSummary(c.Comparison.A), stats.FormatSummary(c.Comparison.B),
+			stats.FormatDelta(c.Comparison.Delta), c.Comparison.P, c.Verdict)
+	}
+	return sb.String()
+}
+
+func dash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,176 @@
+package hypothesis
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// Mode decides what a mismatch between prediction and observation means.
+type Mode int
+
+const (
+	// Assert marks a prediction fixed by the Go spec or well-established
+	// escape analysis rules. A mismatch fails the test.
+	Assert Mode = iota
+	// Observe marks a prediction that is the research question itself.
+	// A mismatch is reported and drives the label, but never fails the test.
+	Observe
+)
+
+func (m Mode) String() string {
+	switch m {
+	case Assert:
+		return "assert"
+	case Observe:
+		return "observe"
+	default:
+		return "Mode(" + strconv.Itoa(int(m)) + ")"
+	}
+}
+
+type kind int
+
+const (
+	kindAny kind = iota
+	kindExact
+	kindApprox
+	kindAtLeast
+	kindAtMost
+	kindBetween
+)
+
+// Expect is the predicted value of one cell of the Expected Outcome table.
+// The zero value is Any.
+type Expect struct {
+	kind   kind
+	value  float64
+	lo, hi float64
+	mode   Mode
+}
+
+// Any records an observation without predicting its value.
+// It is always in Observe mode and never influences the verdict.
+func Any() Expect { return Expect{kind: kindAny, mode: Observe} }
+
+// Exactly predicts the observed value equals v.
+func Exactly(v float64) Expect { return Expect{kind: kindExact, value: v} }
+
+// Approx predicts the observed value is within a relative tolerance of v
+// (tol=0.1 accepts ±10%).
+func Approx(v, tol float64) Expect {
+	d := math.Abs(v * tol)
+	return Expect{kind: kindApprox, value: v, lo: v - d, hi: v + d}
+}
+
+// AtLeast predicts the observed value is ≥ v.
+func AtLeast(v float64) Expect { return Expect{kind: kindAtLeast, lo: v} }
+
+// AtMost predicts the observed value is ≤ v.
+func AtMost(v float64) Expect { return Expect{kind: kindAtMost, hi: v} }
+
+// Between predicts the observed value lies in [lo, hi].
+func Between(lo, hi float64) Expect { return Expect{kind: kindBetween, lo: lo, hi: hi} }
+
+// Observe returns a copy of e in Observe mode.
+func (e Expect) Observe() Expect {
+	e.mode = Observe
+	return e
+}
+
+// Mode reports whether e is a hard assertion or an observation.
+func (e Expect) Mode() Mode { return e.mode }
+
+// Predicts reports whether e makes a falsifiable prediction.
+func (e Expect) Predicts() bool { return e.kind != kindAny }
+
+// Match reports whether v satisfies the prediction.
+func (e Expect) Match(v float64) bool {
+	switch e.kind {
+	case kindAny:
+		return true
+	case kindExact:
+		return v == e.value
+	case kindApprox, kindBetween:
+		return v >= e.lo && v <= e.hi
+	case kindAtLeast:
+		return v >= e.lo
+	case kindAtMost:
+		return v <= e.hi
+	default:
+		return false
+	}
+}
+
+// bounds returns the interval of values e accepts.
+func (e Expect) bounds() (lo, hi float64) {
+	switch e.kind {
+	case kindExact:
+		return e.value, e.value
+	case kindApprox, kindBetween:
+		return e.lo, e.hi
+	case kindAtLeast:
+		return e.lo, math.Inf(1)
+	case kindAtMost:
+		return math.Inf(-1), e.hi
+	default:
+		return math.Inf(-1), math.Inf(1)
+	}
+}
+
+// disjoint reports whether no value satisfies both e and f, that is,
+// whether the pair predicts a difference.
+func (e Expect) disjoint(f Expect) bool {
+	elo, ehi := e.bounds()
+	flo, fhi := f.bounds()
+	return ehi < flo || fhi < elo
+}
+
+// center is the representative value of e used for the predicted Diff column.
+func (e Expect) center() (float64, bool) {
+	switch e.kind {
+	case kindExact, kindApprox:
+		return e.value, true
+	case kindBetween:
+		return (e.lo + e.hi) / 2, true
+	case kindAny, kindAtLeast, kindAtMost:
+		return 0, false
+	default:
+		return 0, false
+	}
+}
+
+// Format renders e with the given unit, e.g. "24 B", "≥1", "100 ns ±10%".
+func (e Expect) Format(unit string) string {
+	switch e.kind {
+	case kindAny:
+		return "?"
+	case kindExact:
+		return withUnit(formatValue(e.value), unit)
+	case kindApprox:
+		tol := 0.0
+		if e.value != 0 {
+			tol = (e.hi - e.value) / math.Abs(e.value) * 100
+		}
+		return withUnit(formatValue(e.value), unit) + fmt.Sprintf(" ±%s%%", formatValue(tol))
+	case kindAtLeast:
+		return "≥" + withUnit(formatValue(e.lo), unit)
+	case kindAtMost:
+		return "≤" + withUnit(formatValue(e.hi), unit)
+	case kindBetween:
+		return withUnit(formatValue(e.lo)+"–"+formatValue(e.hi), unit)
+	default:
+		return "?"
+	}
+}
+
+func formatValue(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func withUnit(s, unit string) string {
+	if unit == "" {
+		return s
+	}
+	return s + " " + unit
+}
@@ -0,0 +1,104 @@
+package hypothesis
+
+import "testing"
+
+func TestExpectMatch(t *testing.T) {
+	tests := []struct {
+		name string
+		e    Expect
+		v    float64
+		want bool
+	}{
+		{"Exactly hit", Exactly(16), 16, true},
+		{"Exactly miss", Exactly(16), 17, false},
+		{"Approx inside", Approx(100, 0.1), 109, true},
+		{"Approx outside", Approx(100, 0.1), 111, false},
+		{"AtLeast", AtLeast(1), 1, true},
+		{"AtLeast miss", AtLeast(1), 0, false},
+		{"AtMost", AtMost(1), 0, true},
+		{"Between", Between(2, 4), 3, true},
+		{"Between miss", Between(2, 4), 5, false},
+		{"Any", Any(), -1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.e.Match(tt.v); got != tt.want {
+				t.Errorf("Match(%v) = %v, want %v", tt.v, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpectMode(t *testing.T) {
+	if m := Exactly(0).Mode(); m != Assert {
+		t.Errorf("default mode = %s, want assert", m)
+	}
+	if m := Exactly(0).Observe().Mode(); m != Observe {
+		t.Errorf("Observe() mode = %s, want observe", m)
+	}
+	if m := Any().Mode(); m != Observe {
+		t.Errorf("Any() mode = %s, want observe", m)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		a, b float64
+		want string
+	}{
+		{24, 16, "-33%"},
+		{3, 1, "-66%"},
+		{100, 150, "+50%"},
+		{1, 1, "0%"},
+		{0, 0, "0%"},
+		{0, 1, "n/a"},
+	}
+	for _, tt := range tests {
+		if got := Diff(tt.a, tt.b); got != tt.want {
+			t.Errorf("Diff(%v, %v) = %q, want %q", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestParseExpect(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		want Expect
+		unit string
+	}{
+		{"24 B", Exactly(24), "B"},
+		{"`16 B`", Exactly(16), "B"},
+		{"1", Exactly(1), ""},
+		{"3 allocs", Exactly(3), "allocs"},
+		{"≥1", AtLeast(1), ""},
+		{">= 24 MiB", AtLeast(24 << 20), "B"},
+		{"≥ 25,165,824 B", AtLeast(25165824), "B"},
+		{"≤ 5", AtMost(5), ""},
+		{"<1.5 µs", AtMost(1500), "ns"},
+		{"100 ns ±10%", Approx(100, 0.1), "ns"},
+		{"~2 ms", Approx(2e6, DefaultTolerance), "ns"},
+		{"≈ 150", Approx(150, DefaultTolerance), ""},
+		{"2–4 B", Between(2, 4), "B"},
+		{"-1 - 3", Between(-1, 3), ""},
+		{"?", Any(), ""},
+		{"-", Any(), ""},
+		{"", Any(), ""},
+	} {
+		e, unit, err := ParseExpect(tc.in)
+		if err != nil || e != tc.want || unit != tc.unit {
+			t.Errorf("ParseExpect(%q) = %+v, %q, %v; want %+v, %q", tc.in, e, unit, err, tc.want, tc.unit)
+		}
+	}
+	for _, in := range []string{"fast", "≥ ~3", "≥ 3 ±10%"} {
+		if _, _, err := ParseExpect(in); err == nil {
+			t.Errorf("ParseExpect(%q) succeeded", in)
+		}
+	}
+	// Format and ParseExpect round-trip.
+	for _, e := range []Expect{Exactly(24), AtLeast(1), AtMost(7), Approx(100, 0.1), Between(2, 4), Any()} {
+		got, unit, err := ParseExpect(e.Format("B"))
+		if err != nil || got != e || (e.Predicts() && unit != "B") {
+			t.Errorf("ParseExpect(%q) = %+v, %q, %v; want %+v", e.Format("B"), got, unit, err, e)
+		}
+	}
+}
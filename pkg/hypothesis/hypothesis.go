@@ -0,0 +1,234 @@
+// Package hypothesis declares an experiment's Expected Outcome table in Go,
+// compares it against measured values, and derives the result label
+// (result:verified, result:unexpected or result:inconclusive).
+//
+// A typical experiment test:
+//
+//	h := hypothesis.New("Struct padding", "Unpadded", "Padded")
+//	h.Predict("Size", "B", hypothesis.Exactly(24), hypothesis.Exactly(16))
+//	h.Record("Size", float64(unsafe.Sizeof(Unpadded{})), float64(unsafe.Sizeof(Padded{})))
+//	h.Check(t)
+package hypothesis
+
+import (
+	"fmt"
+	"testing"
+
+	"go-lab/pkg/stats"
+)
+
+// Label is the outcome label attached to an experiment issue.
+type Label string
+
+const (
+	Verified     Label = "result:verified"
+	Unexpected   Label = "result:unexpected"
+	Inconclusive Label = "result:inconclusive"
+)
+
+// Prediction is one row of the Expected Outcome table.
+type Prediction struct {
+	Metric string // e.g. "Size", "Allocs/op", "ns/op"
+	Unit   string // e.g. "B", "ns"; empty for dimensionless metrics
+	A, B   Expect
+}
+
+// Hypothesis is an Expected Outcome table for Pattern A vs Pattern B
+// together with the values observed for it.
+type Hypothesis struct {
+	Title    string
+	PatternA string
+	PatternB string
+
+	rows     []Prediction
+	observed map[string][2]float64
+	compared map[string]stats.Comparison
+}
+
+// New returns an empty hypothesis comparing patternA against patternB.
+func New(title, patternA, patternB string) *Hypothesis {
+	return &Hypothesis{
+		Title:    title,
+		PatternA: patternA,
+		PatternB: patternB,
+		observed: make(map[string][2]float64),
+		compared: make(map[string]stats.Comparison),
+	}
+}
+
+// Predict adds a row to the Expected Outcome table.
+// Predicting the same metric twice panics.
+func (h *Hypothesis) Predict(metric, unit string, a, b Expect) *Hypothesis {
+	for _, r := range h.rows {
+		if r.Metric == metric {
+			panic(fmt.Sprintf("hypothesis: metric %q predicted twice", metric))
+		}
+	}
+	h.rows = append(h.rows, Prediction{Metric: metric, Unit: unit, A: a, B: b})
+	return h
+}
+
+// Predictions returns the rows of the Expected Outcome table in order.
+func (h *Hypothesis) Predictions() []Prediction {
+	return append([]Prediction(nil), h.rows...)
+}
+
+// Record stores the observed values of metric for Pattern A and Pattern B.
+// Recording a metric that was never predicted panics.
+func (h *Hypothesis) Record(metric string, a, b float64) {
+	if _, ok := h.row(metric); !ok {
+		panic(fmt.Sprintf("hypothesis: metric %q was not predicted", metric))
+	}
+	h.observed[metric] = [2]float64{a, b}
+}
+
+// RecordSamples stores repeated measurements (e.g. the -count samples of a
+// benchmark) of metric. The medians are compared against the predictions,
+// and a Mann-Whitney U test decides whether A and B differ at all: when
+// they do not (benchstat's "~"), the row cannot falsify a prediction of a
+// difference and the hypothesis becomes result:inconclusive.
+func (h *Hypothesis) RecordSamples(metric string, a, b []float64) {
+	c := stats.Compare(a, b, stats.DefaultAlpha)
+	h.Record(metric, c.A.Median, c.B.Median)
+	h.compared[metric] = c
+}
+
+func (h *Hypothesis) row(metric string) (Prediction, bool) {
+	for _, r := range h.rows {
+		if r.Metric == metric {
+			return r, true
+		}
+	}
+	return Prediction{}, false
+}
+
+// Outcome classifies a single row after measurement.
+type Outcome int
+
+const (
+	// Missing means the metric was predicted but never recorded.
+	Missing Outcome = iota
+	// Match means every prediction in the row was satisfied.
+	Match
+	// Mismatch means at least one prediction in the row was falsified.
+	Mismatch
+	// NoDifference means the samples of A and B are statistically
+	// indistinguishable, so a predicted difference was neither confirmed
+	// nor falsified.
+	NoDifference
+)
+
+func (o Outcome) String() string {
+	switch o {
+	case Missing:
+		return "missing"
+	case Match:
+		return "match"
+	case Mismatch:
+		return "mismatch"
+	case NoDifference:
+		return "no difference"
+	default:
+		return fmt.Sprintf("Outcome(%d)", int(o))
+	}
+}
+
+// RowResult is the verdict for one row of the table.
+type RowResult struct {
+	Prediction
+	ObservedA, ObservedB float64
+	Outcome              Outcome
+	// Failed is set when a mismatching cell is in Assert mode.
+	Failed bool
+	// Comparison is set when the row was recorded with RecordSamples.
+	Comparison *stats.Comparison
+}
+
+// Result is the verdict for the whole hypothesis.
+type Result struct {
+	Title    string
+	PatternA string
+	PatternB string
+	Rows     []RowResult
+	Label    Label
+}
+
+// Evaluate compares every prediction against the recorded observations.
+//
+// The label is derived as follows:
+//   - result:unexpected if any falsifiable prediction was not met;
+//   - result:inconclusive if any falsifiable prediction was not measured,
+//     or if a row predicts a difference between A and B (no value
+//     satisfies both cells) and its samples show no significant difference;
+//   - result:verified otherwise.
+//
+// Every cell is matched first, so an Assert-mode miss sets Failed even in
+// a row relabelled NoDifference. Cells predicted with Any never influence
+// the label.
+func (h *Hypothesis) Evaluate() Result {
+	res := Result{Title: h.Title, PatternA: h.PatternA, PatternB: h.PatternB, Label: Verified}
+	var missing, mismatch, nodiff bool
+	for _, p := range h.rows {
+		rr := RowResult{Prediction: p}
+		obs, ok := h.observed[p.Metric]
+		switch {
+		case !ok:
+			rr.Outcome = Missing
+			if p.A.Predicts() || p.B.Predicts() {
+				missing = true
+			}
+		default:
+			rr.ObservedA, rr.ObservedB = obs[0], obs[1]
+			rr.Outcome = Match
+			for _, c := range []struct {
+				e Expect
+				v float64
+			}{{p.A, obs[0]}, {p.B, obs[1]}} {
+				if c.e.Match(c.v) {
+					continue
+				}
+				rr.Outcome = Mismatch
+				if c.e.Mode() == Assert {
+					rr.Failed = true
+				}
+			}
+			if c, ok := h.compared[p.Metric]; ok {
+				rr.Comparison = &c
+				if !c.Significant() && p.A.disjoint(p.B) {
+					rr.Outcome = NoDifference
+				}
+			}
+			switch rr.Outcome {
+			case Mismatch:
+				mismatch = true
+			case NoDifference:
+				nodiff = true
+			}
+		}
+		res.Rows = append(res.Rows, rr)
+	}
+	switch {
+	case mismatch:
+		res.Label = Unexpected
+	case missing, nodiff:
+		res.Label = Inconclusive
+	}
+	return res
+}
+
+// Check evaluates h, fails tb for every Assert-mode mismatch, and logs the
+// observed table together with the result label.
+func (h *Hypothesis) Check(tb testing.TB) Result {
+	tb.Helper()
+	res := h.Evaluate()
+	for _, r := range res.Rows {
+		if r.Failed {
+			tb.Errorf("%s: predicted %s=%s / %s=%s, observed %s / %s",
+				r.Metric,
+				h.PatternA, r.A.Format(r.Unit), h.PatternB, r.B.Format(r.Unit),
+				formatObserved(r.ObservedA, r.Unit), formatObserved(r.ObservedB, r.Unit))
+		}
+	}
+	tb.Logf("%s\n%s\nResult: %s", h.Title, res.Table(), res.Label)
+	return res
+}
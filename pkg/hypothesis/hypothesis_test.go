@@ -0,0 +1,186 @@
+package hypothesis
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// recorder captures Errorf/Logf calls so Check can be tested without
+// failing the enclosing test.
+type recorder struct {
+	testing.TB
+	errors []string
+	logs   []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Logf(format string, args ...any) {
+	r.logs = append(r.logs, fmt.Sprintf(format, args...))
+}
+
+func padding() *Hypothesis {
+	h := New("Struct padding", "Unpadded", "Padded")
+	h.Predict("Size", "B", Exactly(24), Exactly(16))
+	h.Predict("Allocs/op", "", Exactly(1), Exactly(1).Observe())
+	return h
+}
+
+func TestEvaluateLabel(t *testing.T) {
+	tests := []struct {
+		name   string
+		record func(h *Hypothesis)
+		want   Label
+	}{
+		{"all match", func(h *Hypothesis) {
+			h.Record("Size", 24, 16)
+			h.Record("Allocs/op", 1, 1)
+		}, Verified},
+		{"observe mismatch", func(h *Hypothesis) {
+			h.Record("Size", 24, 16)
+			h.Record("Allocs/op", 1, 0)
+		}, Unexpected},
+		{"missing row", func(h *Hypothesis) {
+			h.Record("Size", 24, 16)
+		}, Inconclusive},
+		{"mismatch wins over missing", func(h *Hypothesis) {
+			h.Record("Size", 24, 24)
+		}, Unexpected},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := padding()
+			tt.record(h)
+			if got := h.Evaluate().Label; got != tt.want {
+				t.Errorf("Label = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnyDoesNotAffectLabel(t *testing.T) {
+	h := New("x", "A", "B")
+	h.Predict("Size", "B", Exactly(8), Exactly(8))
+	h.Predict("ns/op", "ns", Any(), Any())
+	h.Record("Size", 8, 8)
+	if got := h.Evaluate().Label; got != Verified {
+		t.Errorf("Label = %s, want %s", got, Verified)
+	}
+}
+
+func TestCheckModes(t *testing.T) {
+	h := padding()
+	h.Record("Size", 24, 24)    // Assert mismatch: fails.
+	h.Record("Allocs/op", 1, 0) // Observe mismatch: reported only.
+	r := &recorder{TB: t}
+	res := h.Check(r)
+
+	if len(r.errors) != 1 || !strings.HasPrefix(r.errors[0], "Size:") {
+		t.Fatalf("errors = %q, want exactly one Size failure", r.errors)
+	}
+	if res.Rows[1].Outcome != Mismatch || res.Rows[1].Failed {
+		t.Errorf("observe row: outcome=%s failed=%v, want mismatch without failure", res.Rows[1].Outcome, res.Rows[1].Failed)
+	}
+	if len(r.logs) != 1 || !strings.Contains(r.logs[0], "Result: result:unexpected") {
+		t.Errorf("logs = %q, want result label", r.logs)
+	}
+}
+
+func TestExpectedTable(t *testing.T) {
+	h := New("Struct padding", "Pattern A", "Pattern B")
+	h.Predict("Size", "B", Exactly(24), Exactly(16))
+	h.Predict("Allocs/op", "", Exactly(3), Exactly(1))
+	h.Predict("ns/op", "", Approx(150, 0.1), AtMost(100))
+	want := `| Metric | Pattern A | Pattern B | Diff |
+| :--- | :--- | :--- | :--- |
+| Size | 24 B | 16 B | -33% |
+| Allocs/op | 3 | 1 | -66% |
+| ns/op | 150 ±10% | ≤100 | ? |
+`
+	if got := h.ExpectedTable(); got != want {
+		t.Errorf("ExpectedTable() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestResultTable(t *testing.T) {
+	h := padding()
+	h.Record("Size", 24, 16)
+	want := `| Metric | Unpadded | Padded | Diff |
+| :--- | :--- | :--- | :--- |
+| Size | 24 B | 16 B | -33% |
+| Allocs/op | - | - | - |
+`
+	if got := h.Evaluate().Table(); got != want {
+		t.Errorf("Table() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRecordUnknownMetricPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Record of an unpredicted metric must panic")
+		}
+	}()
+	New("x", "A", "B").Record("Size", 1, 1)
+}
+
+func TestRecordSamples(t *testing.T) {
+	unbuffered := []float64{410, 412, 415, 409, 411}
+	buffered := []float64{380, 382, 379, 381, 383}
+	noisy := []float64{405, 420, 398, 414, 409}
+
+	tests := []struct {
+		name  string
+		a     Expect
+		b     []float64
+		want  Label
+		wantO Outcome
+		diff  string
+	}{
+		{"significant", AtLeast(405).Observe(), buffered, Verified, Match, "-7%"},
+		{"indistinguishable", AtLeast(405).Observe(), noisy, Inconclusive, NoDifference, "~"},
+		// Without a predicted difference, a miss is a miss however noisy.
+		{"absolute miss", Any(), noisy, Unexpected, Mismatch, "~ ✗"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := New("Goroutine spawn", "SpawnUnbuffered", "SpawnBuffered")
+			h.Predict("ns/op", "ns", tt.a, AtMost(400).Observe())
+			h.RecordSamples("ns/op", unbuffered, tt.b)
+			res := h.Evaluate()
+			if res.Label != tt.want || res.Rows[0].Outcome != tt.wantO {
+				t.Errorf("label=%s outcome=%s, want %s %s", res.Label, res.Rows[0].Outcome, tt.want, tt.wantO)
+			}
+			if !strings.Contains(res.Table(), "| "+tt.diff+" |") {
+				t.Errorf("table lacks Diff %q:\n%s", tt.diff, res.Table())
+			}
+		})
+	}
+}
+
+// TestRecordSamplesAssert checks that a control missing its value fails even
+// when A and B are not significantly different.
+func TestRecordSamplesAssert(t *testing.T) {
+	h := New("Escape", "Value", "Pointer")
+	h.Predict("Allocs/op", "", Exactly(0), Exactly(0))
+	h.RecordSamples("Allocs/op", []float64{1, 1, 1, 1, 1}, []float64{1, 1, 1, 1, 1})
+	res := h.Evaluate()
+	if r := res.Rows[0]; !r.Failed || r.Outcome != Mismatch || res.Label != Unexpected {
+		t.Errorf("failed=%v outcome=%s label=%s, want a failed mismatch and %s", r.Failed, r.Outcome, res.Label, Unexpected)
+	}
+
+	// A predicted difference that the samples cannot show is inconclusive,
+	// but its missed controls still fail.
+	h = New("Escape", "Value", "Pointer")
+	h.Predict("Allocs/op", "", Exactly(0), Exactly(1))
+	h.RecordSamples("Allocs/op", []float64{1, 1, 1, 1, 1}, []float64{1, 1, 1, 1, 1})
+	res = h.Evaluate()
+	if r := res.Rows[0]; !r.Failed || r.Outcome != NoDifference || res.Label != Inconclusive {
+		t.Errorf("failed=%v outcome=%s label=%s, want a failed row without difference and %s", r.Failed, r.Outcome, res.Label, Inconclusive)
+	}
+}
@@ -0,0 +1,103 @@
+package hypothesis
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DefaultTolerance is the relative tolerance of cells such as "~100 ns"
+// that state no ±.
+const DefaultTolerance = 0.10
+
+// unitScales converts size and time units to B and ns.
+var unitScales = map[string]struct {
+	base  string
+	scale float64
+}{
+	"B": {"B", 1}, "KiB": {"B", 1 << 10}, "MiB": {"B", 1 << 20}, "GiB": {"B", 1 << 30},
+	"KB": {"B", 1e3}, "MB": {"B", 1e6}, "GB": {"B", 1e9},
+	"ns": {"ns", 1}, "µs": {"ns", 1e3}, "us": {"ns", 1e3}, "ms": {"ns", 1e6}, "s": {"ns", 1e9},
+}
+
+const number = `[+-]?(?:\d+(?:\.\d*)?|\.\d+)(?:[eE][+-]?\d+)?`
+
+var (
+	tolRE    = regexp.MustCompile(`\s*±\s*(` + number + `)\s*%$`)
+	rangeRE  = regexp.MustCompile(`^(` + number + `)\s*(?:–|—|-|\.\.)\s*(` + number + `)\s*(.*)$`)
+	numberRE = regexp.MustCompile(`^(` + number + `)\s*(.*)$`)
+)
+
+// ParseExpect parses a cell of an Expected Outcome table, as written in an
+// issue or rendered by Format: "24 B", "≥1", ">= 24 MiB", "≤ 5", "<5",
+// "100 ns ±10%", "~100 ns" (±DefaultTolerance), "2–4 B", and "?", "-" or
+// an empty cell for Any. Sizes are converted to B (KiB, MiB, GiB, KB, MB,
+// GB) and times to ns (µs, us, ms, s); any other unit, such as "allocs",
+// is returned as written. The result is in Assert mode, except Any.
+func ParseExpect(s string) (e Expect, unit string, err error) {
+	orig := s
+	s = strings.TrimSpace(strings.NewReplacer("`", "", "**", "", ",", "").Replace(s))
+	switch strings.ToLower(s) {
+	case "", "?", "-", "–", "—", "n/a":
+		return Any(), "", nil
+	}
+
+	tol := -1.0
+	if m := tolRE.FindStringSubmatch(s); m != nil {
+		t, _ := strconv.ParseFloat(m[1], 64)
+		tol = t / 100
+		s = s[:len(s)-len(m[0])]
+	}
+	op := ""
+	for _, p := range []string{"≥", ">=", ">", "≤", "<=", "<", "~", "≈"} {
+		if strings.HasPrefix(s, p) {
+			op, s = p, strings.TrimSpace(s[len(p):])
+			break
+		}
+	}
+
+	value := func(num, u string) (float64, string, error) {
+		v, err := strconv.ParseFloat(num, 64)
+		if err != nil {
+			return 0, "", err
+		}
+		u = strings.TrimSpace(u)
+		if sc, ok := unitScales[u]; ok {
+			return v * sc.scale, sc.base, nil
+		}
+		return v, u, nil
+	}
+	if m := rangeRE.FindStringSubmatch(s); m != nil && op == "" && tol < 0 {
+		lo, unit, err := value(m[1], m[3])
+		if err != nil {
+			return Expect{}, "", fmt.Errorf("parse %q: %w", orig, err)
+		}
+		hi, _, _ := value(m[2], m[3])
+		return Between(lo, hi), unit, nil
+	}
+	m := numberRE.FindStringSubmatch(s)
+	if m == nil {
+		return Expect{}, "", fmt.Errorf("parse %q: not a predicted value", orig)
+	}
+	v, unit, err := value(m[1], m[2])
+	if err != nil {
+		return Expect{}, "", fmt.Errorf("parse %q: %w", orig, err)
+	}
+	switch {
+	case op == "~" || op == "≈":
+		if tol < 0 {
+			tol = DefaultTolerance
+		}
+		return Approx(v, tol), unit, nil
+	case tol >= 0 && op == "":
+		return Approx(v, tol), unit, nil
+	case tol >= 0:
+		return Expect{}, "", fmt.Errorf("parse %q: ± after %s", orig, op)
+	case op == "≥" || op == ">=" || op == ">":
+		return AtLeast(v), unit, nil
+	case op == "≤" || op == "<=" || op == "<":
+		return AtMost(v), unit, nil
+	}
+	return Exactly(v), unit, nil
+}
@@ -0,0 +1,78 @@
+package hypothesis
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ExpectedTable renders the Expected Outcome table in the layout used by the
+// experiment issue template.
+func (h *Hypothesis) ExpectedTable() string {
+	var sb strings.Builder
+	writeHeader(&sb, h.PatternA, h.PatternB)
+	for _, p := range h.rows {
+		diff := "?"
+		a, okA := p.A.center()
+		b, okB := p.B.center()
+		if okA && okB {
+			diff = Diff(a, b)
+		}
+		fmt.Fprintf(&sb, "| %s | %s | %s | %s |\n", p.Metric, p.A.Format(p.Unit), p.B.Format(p.Unit), diff)
+	}
+	return sb.String()
+}
+
+// Table renders the observed values in the Summary Table layout used by the
+// pull request template. Mismatching rows are marked with ✗, and sampled
+// rows without a significant difference show "~" as Diff.
+func (r Result) Table() string {
+	var sb strings.Builder
+	writeHeader(&sb, r.PatternA, r.PatternB)
+	for _, row := range r.Rows {
+		if row.Outcome == Missing {
+			fmt.Fprintf(&sb, "| %s | - | - | - |\n", row.Metric)
+			continue
+		}
+		diff := Diff(row.ObservedA, row.ObservedB)
+		if row.Comparison != nil && !row.Comparison.Significant() {
+			diff = "~"
+		}
+		if row.Outcome == Mismatch {
+			diff += " ✗"
+		}
+		fmt.Fprintf(&sb, "| %s | %s | %s | %s |\n", row.Metric,
+			formatObserved(row.ObservedA, row.Unit), formatObserved(row.ObservedB, row.Unit), diff)
+	}
+	return sb.String()
+}
+
+func writeHeader(sb *strings.Builder, a, b string) {
+	fmt.Fprintf(sb, "| Metric | %s | %s | Diff |\n", a, b)
+	sb.WriteString("| :--- | :--- | :--- | :--- |\n")
+}
+
+// Diff formats the relative change from a to b as a signed percentage,
+// e.g. Diff(24, 16) = "-33%". A zero baseline yields "0%" or "n/a".
+func Diff(a, b float64) string {
+	if a == 0 {
+		if b == 0 {
+			return "0%"
+		}
+		return "n/a"
+	}
+	pct := (b - a) / math.Abs(a) * 100
+	s := strconv.FormatFloat(math.Trunc(pct), 'f', 0, 64)
+	if pct > 0 {
+		s = "+" + s
+	}
+	if s == "-0" {
+		s = "0"
+	}
+	return s + "%"
+}
+
+func formatObserved(v float64, unit string) string {
+	return withUnit(strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64), unit)
+}
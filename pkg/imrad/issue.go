@@ -0,0 +1,300 @@
+package imrad
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"go-lab/pkg/hypothesis"
+)
+
+// placeholderItems are the checklist items of the issue template, left
+// out when parsing an issue that kept them. Every wording the template has
+// had is listed, so that issues written from an older template parse too.
+var placeholderItems = []string{
+	"- [ ] **Implementation**: `experiments/xxx` にコードを作成",
+	"- [ ] **Variables**: 操作変数（変えるもの）と制御変数（固定するもの）",
+	"- [ ] **Measurement**: `testing.B` / `runtime/metrics`",
+	"- [ ] **Measurement**: `testing.B` / `runtime.ReadMemStats`", // before runtime/metrics became the rule
+	"- [ ] 新たな疑問（次のIssueへ）",
+}
+
+var (
+	commentRE  = regexp.MustCompile(`(?s)<!--.*?-->`)
+	titleRE    = regexp.MustCompile(`^\[([^\]]+)\]\s*(.*)$`)
+	checkboxRE = regexp.MustCompile(`^[-*]\s+\[([ xX])\]\s+(.*)$`)
+	boldRE     = regexp.MustCompile(`^\*\*(.+?):?\*\*:?\s*(.*)$`)
+	labelRE    = regexp.MustCompile(`result:(verified|unexpected|inconclusive)`)
+	resultRE   = regexp.MustCompile(`^[-*]\s+\*\*Result\*\*:`)
+	blanksRE   = regexp.MustCompile(`\n{3,}`)
+)
+
+// LoadIssue reads an exported issue from path; see ParseIssue.
+func LoadIssue(path string) (*Spec, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("load issue: %w", err)
+	}
+	return ParseIssue(string(src))
+}
+
+// ParseIssue reads an issue body written in the layout of
+// .github/ISSUE_TEMPLATE/experiment.md back into a Spec. The body may be
+// preceded by the header of `gh issue view` (title:, number:, ... up to
+// "--") or by a "# Title: [topic] ..." line; either provides Title, Topic
+// and Issue. Sections are recognized by the English part of their
+// headings, template placeholders are dropped, fenced code blocks of the
+// Hypothesis section become Code, the Expected Outcome table rows become
+// Expected, checklist items keep their checked state, and a result label
+// in the Conclusion becomes Label. The Results section is not read.
+func ParseIssue(src string) (*Spec, error) {
+	s := &Spec{}
+	lines := strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n")
+	lines = s.parseHeader(lines)
+
+	sections := map[string][]string{}
+	var h2, h3 string
+	fence := false
+	for _, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			fence = !fence
+		}
+		switch {
+		case !fence && strings.HasPrefix(line, "## "):
+			h2, h3 = sectionKey(line[3:]), ""
+			continue
+		case !fence && strings.HasPrefix(line, "### "):
+			if k := sectionKey(line[4:]); slices.Contains([]string{"context", "objective", "hypothesis", "expected outcome", "analysis", "conclusion"}, k) {
+				h3 = k
+				continue
+			}
+			h3 = "" // other subsections belong to their section
+		case !fence && strings.HasPrefix(line, "# ") && h2 == "":
+			s.setTitle(strings.TrimPrefix(strings.TrimSpace(line[2:]), "Title:"))
+			continue
+		}
+		k := h2
+		if h3 != "" {
+			k = h3
+		}
+		sections[k] = append(sections[k], line)
+	}
+	if len(sections) == 0 || (len(sections) == 1 && sections[""] != nil) {
+		return nil, errors.New("parse issue: no IMRaD sections")
+	}
+
+	s.Context = text(sections["context"])
+	s.Objective = text(sections["objective"])
+	s.Hypothesis, s.Code = splitCode(sections["hypothesis"])
+	s.Expected = tableRows(sections["expected outcome"])
+	s.Analysis = text(sections["analysis"])
+	for _, item := range checklist(sections["methods"]) {
+		m := Method{Text: item.text, Done: item.done}
+		if b := boldRE.FindStringSubmatch(item.text); b != nil {
+			m.Name, m.Text = b[1], b[2]
+		}
+		s.Methods = append(s.Methods, m)
+	}
+	for _, item := range checklist(sections["next actions"]) {
+		s.Next = append(s.Next, item.text)
+	}
+	var conclusion []string
+	for _, line := range sections["conclusion"] {
+		if resultRE.MatchString(strings.TrimSpace(line)) {
+			if m := labelRE.FindString(line); m != "" {
+				s.Label = hypothesis.Label(m)
+			}
+			continue
+		}
+		conclusion = append(conclusion, line)
+	}
+	s.Conclusion = text(conclusion)
+	return s, nil
+}
+
+// parseHeader consumes a `gh issue view` header and returns the rest.
+func (s *Spec) parseHeader(lines []string) []string {
+	if len(lines) == 0 || !strings.HasPrefix(lines[0], "title:") {
+		return lines
+	}
+	for i, line := range lines {
+		if line == "--" {
+			return lines[i+1:]
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return lines // not a header after all
+		}
+		switch value = strings.TrimSpace(value); key {
+		case "title":
+			s.setTitle(value)
+		case "number":
+			s.Issue, _ = strconv.Atoi(value)
+		}
+	}
+	return lines
+}
+
+// setTitle splits an issue title such as "[memory] Struct padding" into
+// Topic and Title.
+func (s *Spec) setTitle(title string) {
+	title = strings.TrimSpace(title)
+	if m := titleRE.FindStringSubmatch(title); m != nil {
+		s.Topic = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(m[1])), "topic:")
+		title = m[2]
+	}
+	s.Title = title
+}
+
+// sectionKey returns the lower-case English name of a heading such as
+// "2. Hypothesis (仮説)" or "Context(背景)".
+func sectionKey(heading string) string {
+	heading = strings.TrimSpace(heading)
+	if i := strings.IndexAny(heading, "(（"); i >= 0 {
+		heading = heading[:i]
+	}
+	if num, rest, ok := strings.Cut(heading, ". "); ok {
+		if _, err := strconv.Atoi(num); err == nil {
+			heading = rest
+		}
+	}
+	return strings.ToLower(strings.TrimSpace(heading))
+}
+
+// text joins lines without HTML comments and surrounding blank lines.
+func text(lines []string) string {
+	s := commentRE.ReplaceAllString(strings.Join(lines, "\n"), "")
+	return strings.TrimSpace(blanksRE.ReplaceAllString(s, "\n\n"))
+}
+
+// splitCode separates the fenced code blocks of lines from the prose.
+func splitCode(lines []string) (prose string, code []string) {
+	var rest, block []string
+	fence := false
+	for _, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			if fence {
+				code = append(code, strings.Trim(strings.Join(block, "\n"), "\n"))
+				block = nil
+			}
+			fence = !fence
+			continue
+		}
+		if fence {
+			block = append(block, line)
+		} else {
+			rest = append(rest, line)
+		}
+	}
+	return text(rest), code
+}
+
+// tableRows returns the body rows of the first Markdown table in lines.
+// Rows without any value, such as the template's empty row, are skipped.
+func tableRows(lines []string) []Expectation {
+	var rows []Expectation
+	header := true
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "|") {
+			if len(rows) > 0 || !header {
+				break
+			}
+			continue
+		}
+		cells := splitRow(line)
+		if header || isSeparator(cells) {
+			header = false
+			continue
+		}
+		var e Expectation
+		for i, p := range []*string{&e.Metric, &e.A, &e.B, &e.Diff} {
+			if i < len(cells) {
+				*p = cells[i]
+			}
+		}
+		if e != (Expectation{}) {
+			rows = append(rows, e)
+		}
+	}
+	return rows
+}
+
+// splitRow splits a table row into trimmed cells, honoring "\|".
+func splitRow(line string) []string {
+	line = strings.TrimSuffix(strings.TrimPrefix(line, "|"), "|")
+	var cells []string
+	var cell strings.Builder
+	for i := 0; i < len(line); i++ {
+		switch {
+		case line[i] == '\\' && i+1 < len(line) && line[i+1] == '|':
+			cell.WriteByte('|')
+			i++
+		case line[i] == '|':
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+		default:
+			cell.WriteByte(line[i])
+		}
+	}
+	return append(cells, strings.TrimSpace(cell.String()))
+}
+
+func isSeparator(cells []string) bool {
+	for _, c := range cells {
+		if strings.Trim(c, ":- ") != "" {
+			return false
+		}
+	}
+	return true
+}
+
+type checkItem struct {
+	text string
+	done bool
+}
+
+// checklist returns the task list items of lines, without the template's
+// placeholder items.
+func checklist(lines []string) []checkItem {
+	var items []checkItem
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		m := checkboxRE.FindStringSubmatch(line)
+		if m == nil || slices.Contains(placeholderItems, line) {
+			continue
+		}
+		items = append(items, checkItem{text: strings.TrimSpace(m[2]), done: m[1] != " "})
+	}
+	return items
+}
+
+// NewHypothesis returns the Expected Outcome table of s as a hypothesis
+// comparing patternA against patternB, ready for Record and Check. Every
+// cell is parsed with hypothesis.ParseExpect and put in Observe mode: an
+// issue table does not say which predictions are controls, so a mismatch
+// turns the label into result:unexpected instead of failing the test.
+func (s *Spec) NewHypothesis(patternA, patternB string) (*hypothesis.Hypothesis, error) {
+	h := hypothesis.New(s.Title, patternA, patternB)
+	for _, e := range s.Expected {
+		a, unitA, err := hypothesis.ParseExpect(e.A)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", e.Metric, err)
+		}
+		b, unitB, err := hypothesis.ParseExpect(e.B)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", e.Metric, err)
+		}
+		unit := cmp.Or(unitA, unitB)
+		if unitA != "" && unitB != "" && unitA != unitB {
+			return nil, fmt.Errorf("%s: units %s and %s differ", e.Metric, unitA, unitB)
+		}
+		h.Predict(e.Metric, unit, a.Observe(), b.Observe())
+	}
+	return h, nil
+}
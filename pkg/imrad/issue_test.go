@@ -0,0 +1,234 @@
+package imrad
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"go-lab/pkg/hypothesis"
+)
+
+func TestParseIssueTemplate(t *testing.T) {
+	tmpl, err := os.ReadFile("../../.github/ISSUE_TEMPLATE/experiment.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := ParseIssue(strings.SplitN(string(tmpl), "---\n", 3)[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(s, &Spec{}) {
+		t.Errorf("untouched template parses to %+v, want an empty spec", s)
+	}
+	// Issues opened from the original template name ReadMemStats instead.
+	const measurement = "- [ ] **Measurement**: `testing.B` / `runtime/metrics`"
+	body := strings.SplitN(string(tmpl), "---\n", 3)[2]
+	if !strings.Contains(body, measurement) {
+		t.Fatalf("template lacks %q", measurement)
+	}
+	body = strings.Replace(body, measurement, "- [ ] **Measurement**: `testing.B` / `runtime.ReadMemStats`", 1)
+	if s, err := ParseIssue(body); err != nil || len(s.Methods) != 0 {
+		t.Errorf("original template parses to methods %+v, %v", s.Methods, err)
+	}
+	if _, err := ParseIssue("just a note\n"); err == nil {
+		t.Error("ParseIssue without sections succeeded")
+	}
+}
+
+// TestParseIssueRoundTrip checks that a rendered issue reads back into the
+// spec it was rendered from.
+func TestParseIssueRoundTrip(t *testing.T) {
+	want, err := Parse("experiment.toml", sampleSpec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sb strings.Builder
+	if err := Render(&sb, want, nil); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ParseIssue("# Title: " + want.IssueTitle() + "\n\n" + sb.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The body holds neither the issue number nor the benchmark names.
+	want.Issue, want.PatternA, want.PatternB = 0, "", ""
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip:\n got %+v\nwant %+v", got, want)
+	}
+}
+
+// exported is a hand-written issue in the format of gh issue view.
+const exported = "title:\t[Memory] Map growth\nstate:\tCLOSED\nlabels:\ttopic:memory, result:unexpected\nnumber:\t42\n--\n" + `## 1. Introduction (目的と背景)
+
+### Context(背景)
+
+Maps grow by doubling.
+<!-- 何を調べるきっかけになったか -->
+
+### Objective(目的)
+
+Count allocations per insert.
+
+## 2. Hypothesis (仮説)
+
+### Hypothesis(仮説)
+
+Presizing avoids growth:
+
+` + "```go" + `
+m := make(map[int]int)
+## not a heading
+` + "```" + `
+
+and
+
+` + "```go" + `
+m := make(map[int]int, n)
+` + "```" + `
+
+### Expected Outcome(期待される結果)
+
+Per 1000 inserts:
+
+| Metric | Pattern A | Pattern B | Diff |
+| :--- | :--- | :--- | :--- |
+| Allocs/op | ≥ 10 | 1 | -90% |
+| ns/op \| insert | ~50 ns | 30–40 ns | |
+
+## 3. Methods (手法)
+
+- [x] **Implementation**: ` + "`experiments/map-growth`" + `
+- [x] **Variables**: n = 1000 (controlled)
+- [ ] **Measurement**: ` + "`testing.B`" + `
+- [ ] ad-hoc note
+
+## 4. Results (結果)
+
+### Raw
+
+` + "```text\nBenchmarkGrow 100 5000 ns/op\n```" + `
+
+## 5. Discussion (考察)
+
+### Analysis(分析)
+
+Growth happened 8 times.
+
+### Conclusion(結論)
+
+- **Result**: ` + "`result:unexpected`" + `
+
+Buckets are allocated in groups.
+
+## 6. Next Actions
+
+- [ ] Check swiss tables
+- [x] Compare with slices
+`
+
+func TestParseIssue(t *testing.T) {
+	s, err := ParseIssue(exported)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Title != "Map growth" || s.Topic != "memory" || s.Issue != 42 {
+		t.Errorf("title %q, topic %q, issue %d", s.Title, s.Topic, s.Issue)
+	}
+	if s.Context != "Maps grow by doubling." || s.Objective != "Count allocations per insert." {
+		t.Errorf("context %q, objective %q", s.Context, s.Objective)
+	}
+	if s.Hypothesis != "Presizing avoids growth:\n\nand" {
+		t.Errorf("hypothesis %q", s.Hypothesis)
+	}
+	if want := []string{"m := make(map[int]int)\n## not a heading", "m := make(map[int]int, n)"}; !slices.Equal(s.Code, want) {
+		t.Errorf("code %q, want %q", s.Code, want)
+	}
+	want := []Expectation{{"Allocs/op", "≥ 10", "1", "-90%"}, {"ns/op | insert", "~50 ns", "30–40 ns", ""}}
+	if !slices.Equal(s.Expected, want) {
+		t.Errorf("expected %+v, want %+v", s.Expected, want)
+	}
+	methods := []Method{
+		{"Implementation", "`experiments/map-growth`", true},
+		{"Variables", "n = 1000 (controlled)", true},
+		{"Measurement", "`testing.B`", false},
+		{"", "ad-hoc note", false},
+	}
+	if !slices.Equal(s.Methods, methods) {
+		t.Errorf("methods %+v, want %+v", s.Methods, methods)
+	}
+	if v, _ := s.Method("variables"); v != "n = 1000 (controlled)" {
+		t.Errorf("variables %q", v)
+	}
+	if s.Analysis != "Growth happened 8 times." || s.Conclusion != "Buckets are allocated in groups." || s.Label != hypothesis.Unexpected {
+		t.Errorf("analysis %q, conclusion %q, label %q", s.Analysis, s.Conclusion, s.Label)
+	}
+	if want := []string{"Check swiss tables", "Compare with slices"}; !slices.Equal(s.Next, want) {
+		t.Errorf("next %q, want %q", s.Next, want)
+	}
+}
+
+// TestExperimentRoundTrip renders the issue of a real experiment spec,
+// reads it back and checks that its Expected Outcome table accepts the
+// results the experiment documents.
+func TestExperimentRoundTrip(t *testing.T) {
+	spec, err := Load("../../experiments/struct-padding/" + SpecFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sb strings.Builder
+	if err := Render(&sb, spec, nil); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "issue.md")
+	if err := os.WriteFile(path, []byte("# Title: "+spec.IssueTitle()+"\n\n"+sb.String()), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := LoadIssue(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(got.Expected, spec.Expected) {
+		t.Fatalf("expected %+v, want %+v", got.Expected, spec.Expected)
+	}
+	h, err := got.NewHypothesis(spec.PatternA, spec.PatternB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.Record("Size", 24, 16)
+	h.Record("B/op (1M slice)", 24<<20, 16<<20)
+	if res := h.Evaluate(); res.Label != hypothesis.Verified {
+		t.Errorf("label %s, want %s\n%s", res.Label, hypothesis.Verified, res.Table())
+	}
+}
+
+func TestNewHypothesis(t *testing.T) {
+	s, err := ParseIssue(exported)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := s.NewHypothesis("Grow", "Presized")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := h.Predictions()
+	if len(p) != 2 || p[0].A != hypothesis.AtLeast(10).Observe() || p[1].Unit != "ns" || p[1].B != hypothesis.Between(30, 40).Observe() {
+		t.Fatalf("predictions %+v", p)
+	}
+	h.Record("Allocs/op", 12, 1)
+	h.Record("ns/op | insert", 52, 35)
+	if res := h.Evaluate(); res.Label != hypothesis.Verified {
+		t.Errorf("label %s, want %s\n%s", res.Label, hypothesis.Verified, res.Table())
+	}
+	h.Record("Allocs/op", 12, 2)
+	if res := h.Evaluate(); res.Label != hypothesis.Unexpected || res.Rows[0].Failed {
+		t.Errorf("mismatch: label %s, failed %v; want an observed mismatch", res.Label, res.Rows[0].Failed)
+	}
+
+	s.Expected = append(s.Expected, Expectation{Metric: "Speed", A: "fast", B: "slow"})
+	if _, err := s.NewHypothesis("A", "B"); err == nil {
+		t.Error("NewHypothesis with an unparsable cell succeeded")
+	}
+}
@@ -0,0 +1,124 @@
+package imrad
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+
+	"go-lab/pkg/hypothesis"
+	"go-lab/pkg/report"
+)
+
+// Render writes the issue body of s in the layout of
+// .github/ISSUE_TEMPLATE/experiment.md. Empty fields keep the template's
+// placeholder comments. With results, the data of a stored run (see
+// report.Build), the Methods items are checked and sections 4–6 are
+// filled in: the benchmark output, the Pattern A vs B summary table, the
+// result label logged by TestHypothesis and the spec's discussion and
+// next actions. Without results, sections 4–6 are the template's.
+func Render(w io.Writer, s *Spec, results *report.Data) error {
+	if err := issueTmpl.Execute(w, view{s, results}); err != nil {
+		return fmt.Errorf("render issue: %w", err)
+	}
+	return nil
+}
+
+type view struct {
+	*Spec
+	Results *report.Data
+}
+
+// Done reports whether the Methods item m is checked.
+func (v view) Done(m Method) bool { return m.Done || v.Results != nil }
+
+// ResultLabel returns the label logged in the results, or else the label
+// of the spec.
+func (v view) ResultLabel() hypothesis.Label {
+	if v.Results != nil && v.Results.Label != "" {
+		return v.Results.Label
+	}
+	return v.Label
+}
+
+var issueTmpl = template.Must(template.New("issue").Funcs(template.FuncMap{
+	"cell": func(s string) string { return strings.ReplaceAll(s, "|", `\|`) },
+}).Parse(issueTemplate))
+
+const issueTemplate = `## 1. Introduction (目的と背景)
+
+### Context(背景)
+
+{{if .Context}}{{.Context}}{{else}}<!-- 何を調べるきっかけになったか、既存の知見や問題点を記述 -->{{end}}
+
+### Objective(目的)
+
+{{if .Objective}}{{.Objective}}{{else}}<!-- この実験で明らかにしたいことを具体的に記述 -->{{end}}
+
+## 2. Hypothesis (仮説)
+
+### Hypothesis(仮説)
+
+{{if or .Hypothesis .Code}}{{.Hypothesis}}{{range .Code}}
+
+` + "```go" + `
+{{.}}
+` + "```" + `{{end}}{{else}}<!-- どのような結果が得られると予想するか、その根拠とともに記述 -->{{end}}
+
+### Expected Outcome(期待される結果)
+
+{{if .Expected}}| Metric | Pattern A | Pattern B | Diff |
+| :--- | :--- | :--- | :--- |
+{{- range .Expected}}
+| {{cell .Metric}} | {{cell .A}} | {{cell .B}} | {{cell .Diff}} |
+{{- end}}{{else}}<!-- 具体的な数値や測定可能な変化を記述 -->{{end}}
+
+## 3. Methods (手法)
+{{if .Methods}}
+{{range .Methods}}- [{{if $.Done .}}x{{else}} {{end}}] **{{.Name}}**: {{.Text}}
+{{end}}{{else}}
+- [ ] **Implementation**: ` + "`experiments/xxx`" + ` にコードを作成
+- [ ] **Variables**: 操作変数（変えるもの）と制御変数（固定するもの）
+- [ ] **Measurement**: ` + "`testing.B` / `runtime/metrics`" + `
+{{end}}
+## 4. Results (結果)
+{{with .Results}}
+Go ` + "`{{.GoVersion}}`" + ` on ` + "`{{.GOOS}}/{{.GOARCH}}`" + `{{if .CPU}}, {{.CPU}}{{end}}
+
+` + "```text" + `
+{{.Benchmark}}` + "```" + `
+{{- if .Summary}}
+
+| Metric | {{.PatternA}} | {{.PatternB}} | Diff |
+|:---|---:|---:|:---|
+{{- range .Summary}}
+| {{.Metric}} | {{.A}} | {{.B}} | {{.Diff}} |
+{{- end}}
+{{- end}}
+{{- range .Charts}}
+
+![{{.}}]({{.}})
+{{- end}}
+{{else}}
+` + "```text" + `
+(ここにベンチマーク結果を貼り付け)
+` + "```" + `
+{{end}}
+## 5. Discussion (考察)
+
+### Analysis(分析)
+
+{{if .Analysis}}{{.Analysis}}{{else}}<!-- 結果から読み取れる重要なポイントを記述 -->{{end}}
+
+### Conclusion(結論)
+
+{{with .ResultLabel}}- **Result**: ` + "`{{.}}`" + `
+
+{{end}}{{if .Conclusion}}{{.Conclusion}}{{else}}<!-- 仮説が検証されたか、次に何をすべきかを記述 -->{{end}}
+
+## 6. Next Actions
+{{if .Next}}
+{{range .Next}}- [ ] {{.}}
+{{end}}{{else}}
+- [ ] 新たな疑問（次のIssueへ）
+{{end}}`
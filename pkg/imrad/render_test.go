@@ -0,0 +1,79 @@
+package imrad
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"go-lab/pkg/hypothesis"
+	"go-lab/pkg/report"
+)
+
+// TestRenderTemplate checks that an empty spec renders the issue template
+// itself, placeholders included.
+func TestRenderTemplate(t *testing.T) {
+	tmpl, err := os.ReadFile("../../.github/ISSUE_TEMPLATE/experiment.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Drop the front matter.
+	parts := strings.SplitN(string(tmpl), "---\n", 3)
+	want := strings.TrimPrefix(parts[2], "\n")
+	var sb strings.Builder
+	if err := Render(&sb, &Spec{}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := sb.String(); got != want {
+		t.Errorf("empty spec renders\n%s\nwant the template body\n%s", got, want)
+	}
+}
+
+func TestRender(t *testing.T) {
+	s, err := Parse("experiment.toml", sampleSpec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sb strings.Builder
+	if err := Render(&sb, s, nil); err != nil {
+		t.Fatal(err)
+	}
+	before := sb.String()
+	for _, want := range []string{
+		"### Context(背景)\n\nFields are aligned.\nPadding is \"wasted\".\n\n### Objective",
+		"Reordering shrinks the struct.\n\n```go\ntype A struct{ s string }\n```\n\n```go\nvar re = \"\\d+\"\n```\n\n### Expected",
+		"| Metric | Pattern A | Pattern B | Diff |\n| :--- | :--- | :--- | :--- |\n| Size | 24 B | 16 B | -33% |\n| Allocs/op | 1 | 1 |  |\n\n## 3.",
+		"- [ ] **Implementation**: `experiments/struct-padding`\n- [ ] **Independent Variables**: field order\n\n## 4.",
+		"(ここにベンチマーク結果を貼り付け)",
+		"### Conclusion(結論)\n\nVerified: C:\\path stays.\n\n## 6. Next Actions\n\n- [ ] Nested structs?\n- [ ] Arrays of bools?\n",
+	} {
+		if !strings.Contains(before, want) {
+			t.Errorf("issue lacks %q:\n%s", want, before)
+		}
+	}
+
+	sb.Reset()
+	d := &report.Data{
+		GoVersion: "go1.26.0", GOOS: "linux", GOARCH: "amd64", CPU: "Intel(R) Xeon(R) Processor",
+		Benchmark: "BenchmarkAllocUnpadded-8 \t 10\t 1200 ns/op\n",
+		PatternA:  "AllocUnpadded", PatternB: "AllocPadded",
+		Summary: []report.Row{{Metric: "ns/op", A: "1200 ±1%", B: "800 ±1%", Diff: "-33.33% (p=0.008 n=5)"}},
+		Label:   hypothesis.Verified,
+	}
+	if err := Render(&sb, s, d); err != nil {
+		t.Fatal(err)
+	}
+	after := sb.String()
+	for _, want := range []string{
+		"- [x] **Implementation**",
+		"## 4. Results (結果)\n\nGo `go1.26.0` on `linux/amd64`, Intel(R) Xeon(R) Processor\n\n```text\nBenchmarkAllocUnpadded-8 \t 10\t 1200 ns/op\n```\n\n| Metric | AllocUnpadded | AllocPadded | Diff |\n|:---|---:|---:|:---|\n| ns/op | 1200 ±1% | 800 ±1% | -33.33% (p=0.008 n=5) |\n\n## 5.",
+		"### Conclusion(結論)\n\n- **Result**: `result:verified`\n\nVerified:",
+	} {
+		if !strings.Contains(after, want) {
+			t.Errorf("issue with results lacks %q:\n%s", want, after)
+		}
+	}
+	// Sections 1–3 are unchanged apart from the checked methods.
+	if i := strings.Index(after, "## 3."); before[:i] != after[:i] {
+		t.Error("results changed sections 1–2")
+	}
+}
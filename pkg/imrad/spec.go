@@ -0,0 +1,221 @@
+// Package imrad holds the IMRaD experiment issue of .github/ISSUE_TEMPLATE/
+// experiment.md as structured data. A spec file per experiment
+// (experiments/<topic>/experiment.toml) states the introduction,
+// hypothesis, expected outcome and methods before any code is written;
+// Render turns it into the issue markdown and, given a stored run, fills
+// in the Results, Discussion and Next Actions sections, so the hypothesis
+// and its result live in one source of truth. ParseIssue reads issues
+// written by hand back into a Spec, and Spec.NewHypothesis turns their
+// Expected Outcome table into predictions to re-verify.
+package imrad
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"go-lab/pkg/hypothesis"
+)
+
+// SpecFile is the name of the spec file in an experiment directory.
+const SpecFile = "experiment.toml"
+
+// Spec is an experiment issue in IMRaD form.
+type Spec struct {
+	Title string // experiment title, without the [topic] prefix
+	Topic string // label topic: memory, cpu, data-structure, algorithm or io
+	Issue int    // issue number, 0 before the issue exists
+
+	// PatternA and PatternB are the benchmark base names compared in the
+	// Results section; empty when the experiment has exactly two.
+	PatternA, PatternB string
+
+	Context    string
+	Objective  string
+	Hypothesis string
+	Code       []string // Go code blocks of the hypothesis, e.g. Bad vs Good
+	Expected   []Expectation
+
+	Methods []Method
+
+	Analysis   string
+	Conclusion string
+	Label      hypothesis.Label // result label of a closed issue, read by ParseIssue
+	Next       []string         // open questions for follow-up issues
+}
+
+// Expectation is one row of the Expected Outcome table.
+type Expectation struct {
+	Metric string // e.g. "Allocs/op"
+	A, B   string // predicted values, e.g. "24 B"
+	Diff   string // e.g. "-33%"
+}
+
+// Method is one item of the Methods checklist.
+type Method struct {
+	Name string // e.g. "Variables"
+	Text string
+	Done bool
+}
+
+// IssueTitle returns the issue title, e.g. "[memory] Struct padding".
+func (s *Spec) IssueTitle() string {
+	if s.Topic == "" {
+		return s.Title
+	}
+	return "[" + s.Topic + "] " + s.Title
+}
+
+// Method returns the text of the method named name (case-insensitive).
+func (s *Spec) Method(name string) (string, bool) {
+	for _, m := range s.Methods {
+		if strings.EqualFold(m.Name, name) {
+			return m.Text, true
+		}
+	}
+	return "", false
+}
+
+// Load reads the spec file at path.
+func Load(path string) (*Spec, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("load spec: %w", err)
+	}
+	return Parse(path, string(src))
+}
+
+// Parse parses the spec file src; file names it in errors. The layout is:
+//
+//	title = "Struct padding"
+//	topic = "memory"
+//	issue = 1
+//	pattern_a = "AllocUnpadded"
+//	pattern_b = "AllocPadded"
+//
+//	[introduction]
+//	context = """..."""
+//	objective = """..."""
+//
+//	[hypothesis]
+//	text = """..."""
+//	code = '''...'''             # or an array of code blocks
+//
+//	[[expected]]                  # one per Expected Outcome row
+//	metric = "Size"
+//	a = "24 B"
+//	b = "16 B"
+//	diff = "-33%"
+//
+//	[methods]                     # checklist items, in file order
+//	implementation = "`experiments/struct-padding`"
+//	variables = "..."
+//	measurement = "`testing.B` / `runtime/metrics`"
+//
+//	[discussion]
+//	analysis = """..."""
+//	conclusion = """..."""
+//
+//	[next]
+//	actions = ["...", "..."]
+//
+// Unknown tables and keys are errors, so typos do not silently drop text.
+func Parse(file, src string) (*Spec, error) {
+	d, err := parseTOML(file, src)
+	if err != nil {
+		return nil, err
+	}
+	s := &Spec{}
+	var issue int64
+	fields := map[string]map[string]any{
+		"": {
+			"title": &s.Title, "topic": &s.Topic, "issue": &issue,
+			"pattern_a": &s.PatternA, "pattern_b": &s.PatternB,
+		},
+		"introduction": {"context": &s.Context, "objective": &s.Objective},
+		"hypothesis":   {"text": &s.Hypothesis, "code": &s.Code},
+		"discussion":   {"analysis": &s.Analysis, "conclusion": &s.Conclusion},
+		"next":         {"actions": &s.Next},
+	}
+	for _, t := range d.order {
+		switch {
+		case t.name == "methods" && d.tables[t.name] == t:
+			for _, k := range t.keys {
+				text, ok := t.values[k].(string)
+				if !ok {
+					return nil, d.errorf(t.lines[k], "methods.%s: want a string", k)
+				}
+				s.Methods = append(s.Methods, Method{Name: methodName(k), Text: strings.TrimSpace(text)})
+			}
+		case t.name == "expected" && d.tables[t.name] == nil:
+			var e Expectation
+			if err := d.decode(t, map[string]any{"metric": &e.Metric, "a": &e.A, "b": &e.B, "diff": &e.Diff}); err != nil {
+				return nil, err
+			}
+			s.Expected = append(s.Expected, e)
+		case fields[t.name] != nil && d.arrays[t.name] == nil:
+			if err := d.decode(t, fields[t.name]); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, d.errorf(t.line, "unknown table %s", t.name)
+		}
+	}
+	s.Issue = int(issue)
+	for _, p := range []*string{&s.Context, &s.Objective, &s.Hypothesis, &s.Analysis, &s.Conclusion} {
+		*p = strings.TrimSpace(*p)
+	}
+	s.Code = slices.DeleteFunc(s.Code, func(c string) bool { return strings.TrimSpace(c) == "" })
+	for i, c := range s.Code {
+		s.Code[i] = strings.Trim(c, "\n")
+	}
+	return s, nil
+}
+
+// decode stores the values of t into the pointers of fields by key.
+func (d *document) decode(t *table, fields map[string]any) error {
+	for _, k := range t.keys {
+		v, line := t.values[k], t.lines[k]
+		name := k
+		if t.name != "" {
+			name = t.name + "." + k
+		}
+		switch dst := fields[k].(type) {
+		case nil:
+			return d.errorf(line, "unknown key %s", name)
+		case *string:
+			str, ok := v.(string)
+			if !ok {
+				return d.errorf(line, "%s: want a string", name)
+			}
+			*dst = str
+		case *int64:
+			n, ok := v.(int64)
+			if !ok {
+				return d.errorf(line, "%s: want an integer", name)
+			}
+			*dst = n
+		case *[]string:
+			switch v := v.(type) {
+			case string:
+				*dst = []string{v}
+			case []string:
+				*dst = v
+			default:
+				return d.errorf(line, "%s: want a string or an array of strings", name)
+			}
+		}
+	}
+	return nil
+}
+
+// methodName turns a methods key such as "implementation" into the bold
+// checklist label "Implementation".
+func methodName(key string) string {
+	words := strings.FieldsFunc(key, func(r rune) bool { return r == '_' || r == '-' })
+	for i, w := range words {
+		words[i] = strings.ToUpper(w[:1]) + w[1:]
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,110 @@
+package imrad
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+const sampleSpec = `# comment
+title = "Struct padding"
+topic = "memory"   # label topic:memory
+issue = 12
+pattern_a = "AllocUnpadded"
+pattern_b = "AllocPadded"
+
+[introduction]
+context = """
+Fields are aligned.
+Padding is "wasted"."""
+objective = "Measure the \"waste\"\tper struct."
+
+[hypothesis]
+text = """
+Reordering shrinks the struct.
+"""
+code = ['type A struct{ s string }', 'var re = "\d+"']
+
+[[expected]]
+metric = "Size"
+a = "24 B"
+b = "16 B"
+diff = "-33%"
+
+[[expected]]
+metric = "Allocs/op"
+a = "1"
+b = "1"
+
+[methods]
+implementation = "` + "`experiments/struct-padding`" + `"
+independent_variables = "field order"
+
+[discussion]
+conclusion = '''
+Verified: C:\path stays.
+'''
+
+[next]
+actions = ["Nested structs?", 'Arrays of bools?']
+`
+
+func TestParse(t *testing.T) {
+	s, err := Parse("experiment.toml", sampleSpec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.IssueTitle() != "[memory] Struct padding" || s.Issue != 12 || s.PatternA != "AllocUnpadded" || s.PatternB != "AllocPadded" {
+		t.Errorf("header = %q, issue %d, patterns %q %q", s.IssueTitle(), s.Issue, s.PatternA, s.PatternB)
+	}
+	if want := "Fields are aligned.\nPadding is \"wasted\"."; s.Context != want {
+		t.Errorf("Context = %q, want %q", s.Context, want)
+	}
+	if want := "Measure the \"waste\"\tper struct."; s.Objective != want {
+		t.Errorf("Objective = %q, want %q", s.Objective, want)
+	}
+	if s.Hypothesis != "Reordering shrinks the struct." {
+		t.Errorf("Hypothesis = %q", s.Hypothesis)
+	}
+	if want := []string{"type A struct{ s string }", `var re = "\d+"`}; !slices.Equal(s.Code, want) {
+		t.Errorf("Code = %q, want %q", s.Code, want)
+	}
+	if want := []Expectation{{"Size", "24 B", "16 B", "-33%"}, {"Allocs/op", "1", "1", ""}}; !slices.Equal(s.Expected, want) {
+		t.Errorf("Expected = %+v, want %+v", s.Expected, want)
+	}
+	want := []Method{{Name: "Implementation", Text: "`experiments/struct-padding`"}, {Name: "Independent Variables", Text: "field order"}}
+	if !slices.Equal(s.Methods, want) {
+		t.Errorf("Methods = %+v, want %+v", s.Methods, want)
+	}
+	if v, ok := s.Method("independent variables"); !ok || v != "field order" {
+		t.Errorf("Method(independent variables) = %q, %v", v, ok)
+	}
+	if s.Conclusion != `Verified: C:\path stays.` {
+		t.Errorf("Conclusion = %q", s.Conclusion)
+	}
+	if want := []string{"Nested structs?", "Arrays of bools?"}; !slices.Equal(s.Next, want) {
+		t.Errorf("Next = %q, want %q", s.Next, want)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, tc := range []struct {
+		src, want string
+	}{
+		{"titel = \"x\"", "x.toml:1: unknown key titel"},
+		{"[intro]\n", "x.toml:1: unknown table intro"},
+		{"[introduction]\ncontext = 1", "x.toml:2: introduction.context: want a string"},
+		{"issue = \"12\"", "x.toml:1: issue: want an integer"},
+		{"title = \"a\"\ntitle = \"b\"", "x.toml:2: duplicate key title"},
+		{"[hypothesis]\ntext = \"\"\"\nnever closed", "x.toml:2: text: unterminated string"},
+		{"title = \"a\" trailing", "x.toml:1: title: unexpected \" trailing\" after string"},
+		{"[[methods]]\n", "x.toml:1: unknown table methods"},
+		{"[expected]\n", "x.toml:1: unknown table expected"},
+		{"just text", "x.toml:1: expected key = value"},
+	} {
+		_, err := Parse("x.toml", tc.src)
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("Parse(%q) error = %v, want %q", tc.src, err, tc.want)
+		}
+	}
+}
@@ -0,0 +1,289 @@
+package imrad
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// The spec format is the subset of TOML the specs need, parsed with the
+// standard library: key = value pairs, [table] and [[array]] headers,
+// comments, basic ("...") and literal ('...') strings, their multi-line
+// forms ("""...""", '''...'''), integers, booleans and single-line
+// arrays of strings. Literal strings keep backslashes, which suits code.
+
+// table is one [table] or [[array]] entry with its keys in file order.
+type table struct {
+	name   string
+	line   int
+	keys   []string
+	values map[string]any // string, int64, bool or []string
+	lines  map[string]int
+}
+
+func newTable(name string, line int) *table {
+	return &table{name: name, line: line, values: map[string]any{}, lines: map[string]int{}}
+}
+
+// document is a parsed spec file.
+type document struct {
+	file   string
+	root   *table
+	tables map[string]*table
+	arrays map[string][]*table
+	order  []*table // every table in file order, root first
+}
+
+// errorf returns a syntax or schema error at a line of the spec file.
+func (d *document) errorf(line int, format string, args ...any) error {
+	return fmt.Errorf("%s:%d: %s", d.file, line, fmt.Sprintf(format, args...))
+}
+
+func parseTOML(file string, src string) (*document, error) {
+	d := &document{file: file, root: newTable("", 1), tables: map[string]*table{}, arrays: map[string][]*table{}}
+	d.order = append(d.order, d.root)
+	cur := d.root
+	lines := strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		n := i + 1
+		line := strings.TrimSpace(lines[i])
+		switch {
+		case line == "" || line[0] == '#':
+			continue
+		case strings.HasPrefix(line, "[["):
+			name, ok := header(line, "[[", "]]")
+			if !ok {
+				return nil, d.errorf(n, "invalid array header %q", line)
+			}
+			if d.tables[name] != nil {
+				return nil, d.errorf(n, "%s is already a table", name)
+			}
+			cur = newTable(name, n)
+			d.arrays[name] = append(d.arrays[name], cur)
+			d.order = append(d.order, cur)
+			continue
+		case line[0] == '[':
+			name, ok := header(line, "[", "]")
+			if !ok {
+				return nil, d.errorf(n, "invalid table header %q", line)
+			}
+			if d.tables[name] != nil || d.arrays[name] != nil {
+				return nil, d.errorf(n, "duplicate table %s", name)
+			}
+			cur = newTable(name, n)
+			d.tables[name] = cur
+			d.order = append(d.order, cur)
+			continue
+		}
+		key, rest, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || !isBareKey(key) {
+			return nil, d.errorf(n, "expected key = value, got %q", line)
+		}
+		if _, dup := cur.values[key]; dup {
+			return nil, d.errorf(n, "duplicate key %s", key)
+		}
+		v, consumed, err := parseValue(strings.TrimSpace(rest), lines[i+1:])
+		if err != nil {
+			return nil, d.errorf(n, "%s: %v", key, err)
+		}
+		i += consumed
+		cur.keys = append(cur.keys, key)
+		cur.values[key] = v
+		cur.lines[key] = n
+	}
+	return d, nil
+}
+
+// header returns the name of a table header line such as "[methods]".
+func header(line, open, close string) (string, bool) {
+	line = stripComment(line)
+	if !strings.HasPrefix(line, open) || !strings.HasSuffix(line, close) {
+		return "", false
+	}
+	name := strings.TrimSpace(line[len(open) : len(line)-len(close)])
+	return name, isBareKey(name)
+}
+
+func isBareKey(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+// stripComment drops a trailing comment and spaces from the part of a line
+// that follows a value.
+func stripComment(s string) string {
+	if i := strings.IndexByte(s, '#'); i >= 0 {
+		s = s[:i]
+	}
+	return strings.TrimSpace(s)
+}
+
+// parseValue parses the value starting at s. Multi-line strings continue
+// into next; consumed is the number of those lines used.
+func parseValue(s string, next []string) (v any, consumed int, err error) {
+	switch {
+	case strings.HasPrefix(s, `"""`), strings.HasPrefix(s, "'''"):
+		return parseMultiline(s, next)
+	case strings.HasPrefix(s, `"`), strings.HasPrefix(s, "'"):
+		str, rest, err := parseString(s)
+		if err != nil {
+			return nil, 0, err
+		}
+		if stripComment(rest) != "" {
+			return nil, 0, fmt.Errorf("unexpected %q after string", rest)
+		}
+		return str, 0, nil
+	case strings.HasPrefix(s, "["):
+		return parseArray(s)
+	}
+	s = stripComment(s)
+	switch s {
+	case "true":
+		return true, 0, nil
+	case "false":
+		return false, 0, nil
+	}
+	if n, err := strconv.ParseInt(strings.ReplaceAll(s, "_", ""), 10, 64); err == nil {
+		return n, 0, nil
+	}
+	return nil, 0, fmt.Errorf("unsupported value %q", s)
+}
+
+// parseString parses the single-line string at the start of s and returns
+// it with the rest of s.
+func parseString(s string) (str, rest string, err error) {
+	quote := s[0]
+	for i := 1; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && quote == '"':
+			i++
+		case s[i] == quote:
+			if quote == '\'' {
+				return s[1:i], s[i+1:], nil
+			}
+			str, err := unescape(s[1:i])
+			return str, s[i+1:], err
+		}
+	}
+	return "", "", fmt.Errorf("unterminated string")
+}
+
+// parseMultiline parses a triple-quoted basic or literal string. A newline
+// right after the opening delimiter is trimmed, as in TOML.
+func parseMultiline(s string, next []string) (any, int, error) {
+	delim := s[:3]
+	body := s[3:]
+	consumed := 0
+	var sb strings.Builder
+	for {
+		if end := strings.Index(body, delim); end >= 0 {
+			if rest := stripComment(body[end+3:]); rest != "" {
+				return nil, 0, fmt.Errorf("unexpected %q after string", rest)
+			}
+			sb.WriteString(body[:end])
+			break
+		}
+		sb.WriteString(body)
+		if consumed == len(next) {
+			return nil, 0, fmt.Errorf("unterminated string")
+		}
+		if consumed > 0 || body != "" {
+			sb.WriteByte('\n')
+		}
+		body = next[consumed]
+		consumed++
+	}
+	str := sb.String()
+	if delim == `"""` {
+		var err error
+		if str, err = unescape(str); err != nil {
+			return nil, 0, err
+		}
+	}
+	return str, consumed, nil
+}
+
+// parseArray parses a single-line array of strings.
+func parseArray(s string) (any, int, error) {
+	items := []string{}
+	s = strings.TrimSpace(s[1:])
+	for {
+		if strings.HasPrefix(s, "]") {
+			if rest := stripComment(s[1:]); rest != "" {
+				return nil, 0, fmt.Errorf("unexpected %q after array", rest)
+			}
+			return items, 0, nil
+		}
+		if s == "" || (s[0] != '"' && s[0] != '\'') {
+			return nil, 0, fmt.Errorf("arrays may only hold strings on one line")
+		}
+		str, rest, err := parseString(s)
+		if err != nil {
+			return nil, 0, err
+		}
+		items = append(items, str)
+		s = strings.TrimSpace(rest)
+		if strings.HasPrefix(s, ",") {
+			s = strings.TrimSpace(s[1:])
+		} else if !strings.HasPrefix(s, "]") {
+			return nil, 0, fmt.Errorf("expected , or ] in array")
+		}
+	}
+}
+
+// unescape resolves the escape sequences of a basic string.
+func unescape(s string) (string, error) {
+	if !strings.Contains(s, `\`) {
+		return s, nil
+	}
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' {
+			sb.WriteByte(s[i])
+			continue
+		}
+		if i++; i == len(s) {
+			return "", fmt.Errorf("trailing backslash")
+		}
+		switch c := s[i]; c {
+		case 'b':
+			sb.WriteByte('\b')
+		case 't':
+			sb.WriteByte('\t')
+		case 'n':
+			sb.WriteByte('\n')
+		case 'f':
+			sb.WriteByte('\f')
+		case 'r':
+			sb.WriteByte('\r')
+		case '"', '\\':
+			sb.WriteByte(c)
+		case 'u', 'U':
+			size := 4
+			if c == 'U' {
+				size = 8
+			}
+			if i+size >= len(s) {
+				return "", fmt.Errorf("short \\%c escape", c)
+			}
+			r, err := strconv.ParseUint(s[i+1:i+1+size], 16, 32)
+			if err != nil || !utf8.ValidRune(rune(r)) {
+				return "", fmt.Errorf("invalid \\%c escape", c)
+			}
+			sb.WriteRune(rune(r))
+			i += size
+		default:
+			return "", fmt.Errorf("invalid escape \\%c", c)
+		}
+	}
+	return sb.String(), nil
+}
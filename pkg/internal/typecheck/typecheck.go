@@ -0,0 +1,44 @@
+// Package typecheck loads a single package from source with go/types, the
+// one loader shared by the lab's static analyses.
+package typecheck
+
+import (
+	"go/ast"
+	"go/build"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+)
+
+// Dir parses and type-checks the non-test Go files of the package in dir.
+// Files are selected with the build constraints of goarch, or of the host
+// when goarch is empty; a non-nil sizes replaces the host's size model.
+// Imported packages are type-checked from source for the host.
+func Dir(dir, goarch string, sizes types.Sizes) (*types.Package, *token.FileSet, error) {
+	ctxt := build.Default
+	if goarch != "" {
+		ctxt.GOARCH = goarch
+		ctxt.CgoEnabled = false
+	}
+	bp, err := ctxt.ImportDir(dir, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, name := range bp.GoFiles {
+		f, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, nil, err
+		}
+		files = append(files, f)
+	}
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil), Sizes: sizes}
+	pkg, err := conf.Check(bp.ImportPath, fset, files, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pkg, fset, nil
+}
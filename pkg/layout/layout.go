@@ -0,0 +1,194 @@
+// Package layout inspects the memory layout of struct types with reflect:
+// the offset, size and alignment of every field and the padding the
+// compiler inserts after it. Layouts render as the ASCII diagrams used in
+// experiment doc comments and as markdown tables for reports.
+package layout
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Field is one field of a struct layout. Fields of nested and embedded
+// structs follow their parent with Depth+1; arrays are not expanded.
+type Field struct {
+	Name     string  // field name; the type name for embedded fields
+	Type     string  // type as written in the declaring package, e.g. "int64", "[4]int32", "Inner"
+	Offset   uintptr // offset from the start of the outermost struct
+	Size     uintptr // unsafe.Sizeof
+	Align    uintptr // unsafe.Alignof
+	Padding  uintptr // bytes between the end of the field and the next sibling (or parent end)
+	Reason   string  // why Padding is there, e.g. "align next int64 to 8"
+	Depth    int     // 0 for fields of the outermost struct
+	Embedded bool    // anonymous (embedded) field
+}
+
+// Layout is the layout of one struct type.
+type Layout struct {
+	Name   string
+	Size   uintptr
+	Align  uintptr
+	Fields []Field
+}
+
+// For returns the layout of struct type T.
+func For[T any]() Layout {
+	return Of(reflect.TypeFor[T]())
+}
+
+// Of returns the layout of struct type t. It panics if t is not a struct.
+func Of(t reflect.Type) Layout {
+	if t.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("layout: %v is not a struct", t))
+	}
+	l := Layout{Name: t.Name(), Size: t.Size(), Align: uintptr(t.Align())}
+	if l.Name == "" {
+		l.Name = t.String()
+	}
+	// Types of the declaring package are written without their qualifier.
+	qual := strings.TrimSuffix(t.String(), t.Name())
+	l.Fields = fields(reflectShape(t, qual), 0, 0)
+	return l
+}
+
+// shape describes a struct type independently of whether its layout
+// comes from reflect or from a go/types size model, so that both share
+// one layout walk.
+type shape struct {
+	size, align uintptr
+	members     []member
+}
+
+// member is one field of a shape.
+type member struct {
+	name, typ           string
+	offset, size, align uintptr
+	embedded            bool
+	inner               *shape // non-nil for fields of struct type
+}
+
+// reflectShape returns the shape of struct type t.
+func reflectShape(t reflect.Type, qual string) *shape {
+	s := &shape{size: t.Size(), align: uintptr(t.Align())}
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		m := member{
+			name:     sf.Name,
+			typ:      typeName(sf.Type, qual),
+			offset:   sf.Offset,
+			size:     sf.Type.Size(),
+			align:    uintptr(sf.Type.Align()),
+			embedded: sf.Anonymous,
+		}
+		if sf.Type.Kind() == reflect.Struct {
+			m.inner = reflectShape(sf.Type, qual)
+		}
+		s.members = append(s.members, m)
+	}
+	return s
+}
+
+// fields flattens s into Fields with the padding after each member.
+// Offsets are relative to base, the offset of s in the outermost struct.
+func fields(s *shape, base uintptr, depth int) []Field {
+	var out []Field
+	for i, m := range s.members {
+		f := Field{
+			Name:     m.name,
+			Type:     m.typ,
+			Offset:   base + m.offset,
+			Size:     m.size,
+			Align:    m.align,
+			Depth:    depth,
+			Embedded: m.embedded,
+		}
+		end := m.offset + m.size
+		if i+1 < len(s.members) {
+			next := s.members[i+1]
+			f.Padding = next.offset - end
+			f.Reason = fmt.Sprintf("align next %s to %d", next.typ, next.align)
+		} else {
+			f.Padding = s.size - end
+			f.Reason = fmt.Sprintf("struct tail alignment to %d", s.align)
+			if f.Size == 0 && f.Padding > 0 {
+				// A trailing zero-size field is padded so that taking its
+				// address does not point past the struct.
+				f.Reason = "trailing zero-size field"
+			}
+		}
+		if f.Padding == 0 {
+			f.Reason = ""
+		}
+		out = append(out, f)
+		if m.inner != nil {
+			out = append(out, fields(m.inner, f.Offset, depth+1)...)
+		}
+	}
+	return out
+}
+
+// typeName writes t as in the source of the declaring package.
+func typeName(t reflect.Type, qual string) string {
+	if qual == "" || !strings.HasSuffix(qual, ".") {
+		return t.String()
+	}
+	return strings.ReplaceAll(t.String(), qual, "")
+}
+
+// Padding returns the total padding bytes of l, including padding inside
+// nested structs.
+func (l Layout) Padding() uintptr {
+	var p uintptr
+	for _, f := range l.Fields {
+		p += f.Padding
+	}
+	return p
+}
+
+// Diagram renders l in the style of the experiment doc comments:
+//
+//	a bool    offset 0:  1 B + 7 B padding  (align next int64 to 8)
+//	b int64   offset 8:  8 B
+//	c bool    offset 16: 1 B + 3 B padding  (align next int32 to 4)
+//	d int32   offset 20: 4 B
+//	                      Total: 24 B
+//
+// Nested fields are indented by two spaces per level.
+func (l Layout) Diagram() string {
+	names := make([]string, len(l.Fields))
+	offsets := make([]string, len(l.Fields))
+	nameW, offW := 0, 0
+	for i, f := range l.Fields {
+		names[i] = strings.Repeat("  ", f.Depth) + f.Name + " " + f.Type
+		offsets[i] = fmt.Sprintf("offset %d:", f.Offset)
+		nameW = max(nameW, len(names[i]))
+		offW = max(offW, len(offsets[i]))
+	}
+	nameW += 3
+	offW++
+
+	var sb strings.Builder
+	for i, f := range l.Fields {
+		fmt.Fprintf(&sb, "%-*s%-*s%d B", nameW, names[i], offW, offsets[i], f.Size)
+		if f.Padding > 0 {
+			fmt.Fprintf(&sb, " + %d B padding  (%s)", f.Padding, f.Reason)
+		}
+		sb.WriteByte('\n')
+	}
+	fmt.Fprintf(&sb, "%*sTotal: %d B\n", nameW+offW+1, "", l.Size)
+	return sb.String()
+}
+
+// Table renders l as a markdown table.
+func (l Layout) Table() string {
+	var sb strings.Builder
+	sb.WriteString("| Field | Type | Offset | Size | Align | Padding |\n")
+	sb.WriteString("| :--- | :--- | ---: | ---: | ---: | ---: |\n")
+	for _, f := range l.Fields {
+		fmt.Fprintf(&sb, "| %s%s | %s | %d | %d | %d | %d |\n",
+			strings.Repeat("&nbsp;&nbsp;", f.Depth), f.Name, f.Type, f.Offset, f.Size, f.Align, f.Padding)
+	}
+	fmt.Fprintf(&sb, "| **Total** | | | %d | %d | %d |\n", l.Size, l.Align, l.Padding())
+	return sb.String()
+}
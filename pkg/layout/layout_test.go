@@ -0,0 +1,153 @@
+package layout
+
+import (
+	"strings"
+	"testing"
+	"unsafe"
+)
+
+type unpadded struct {
+	a bool
+	b int64
+	c bool
+	d int32
+}
+
+type inner struct {
+	x int32
+	y bool
+}
+
+type Embedded struct{ n int16 }
+
+type outer struct {
+	Embedded
+	flag bool
+	in   inner
+	arr  [3]int16
+	p    *inner
+}
+
+func TestDiagram(t *testing.T) {
+	if unsafe.Sizeof(uintptr(0)) != 8 {
+		t.Skip("diagram is for 64-bit platforms")
+	}
+	// The diagram of the struct-padding experiment's Unpadded doc comment.
+	want := "" +
+		"a bool    offset 0:  1 B + 7 B padding  (align next int64 to 8)\n" +
+		"b int64   offset 8:  8 B\n" +
+		"c bool    offset 16: 1 B + 3 B padding  (align next int32 to 4)\n" +
+		"d int32   offset 20: 4 B\n" +
+		"                      Total: 24 B\n"
+	if got := For[unpadded]().Diagram(); got != want {
+		t.Errorf("Diagram() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestNested(t *testing.T) {
+	l := For[outer]()
+	want := []struct {
+		name     string
+		typ      string
+		offset   uintptr
+		size     uintptr
+		padding  uintptr
+		depth    int
+		embedded bool
+	}{
+		{"Embedded", "Embedded", 0, 2, 0, 0, true},
+		{"n", "int16", 0, 2, 0, 1, false},
+		{"flag", "bool", 2, 1, 1, 0, false},
+		{"in", "inner", 4, 8, 0, 0, false},
+		{"x", "int32", 4, 4, 0, 1, false},
+		{"y", "bool", 8, 1, 3, 1, false},
+		{"arr", "[3]int16", 12, 6, unsafe.Alignof(uintptr(0)) - 2, 0, false},
+		{"p", "*inner", 18 + unsafe.Alignof(uintptr(0)) - 2, unsafe.Sizeof(uintptr(0)), 0, 0, false},
+	}
+	if len(l.Fields) != len(want) {
+		t.Fatalf("got %d fields, want %d:\n%s", len(l.Fields), len(want), l.Diagram())
+	}
+	for i, w := range want {
+		f := l.Fields[i]
+		if f.Name != w.name || f.Type != w.typ || f.Offset != w.offset || f.Size != w.size ||
+			f.Padding != w.padding || f.Depth != w.depth || f.Embedded != w.embedded {
+			t.Errorf("fields[%d] = %+v, want %+v", i, f, w)
+		}
+	}
+	if l.Size != unsafe.Sizeof(outer{}) || l.Align != unsafe.Alignof(outer{}) {
+		t.Errorf("size/align = %d/%d, want %d/%d", l.Size, l.Align, unsafe.Sizeof(outer{}), unsafe.Alignof(outer{}))
+	}
+	if !strings.Contains(l.Diagram(), "\n  x int32 ") {
+		t.Errorf("nested fields not indented:\n%s", l.Diagram())
+	}
+	t.Logf("\n%s\n%s", l.Diagram(), l.Table())
+}
+
+func TestTailPadding(t *testing.T) {
+	type padded struct {
+		b int64
+		d int32
+		a bool
+		c bool
+	}
+	l := For[padded]()
+	last := l.Fields[len(l.Fields)-1]
+	if last.Padding != l.Size-14 || !strings.HasPrefix(last.Reason, "struct tail alignment") {
+		t.Errorf("last field = %+v, want tail padding", last)
+	}
+	if l.Padding() != l.Size-14 {
+		t.Errorf("Padding() = %d, want %d", l.Padding(), l.Size-14)
+	}
+}
+
+func TestOfPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("For[int]() did not panic")
+		}
+	}()
+	For[int]()
+}
+
+func TestStatic(t *testing.T) {
+	tests := []struct {
+		arch     string
+		unpadded uintptr
+		node     uintptr
+	}{
+		{"amd64", 24, 16},
+		{"arm64", 24, 16},
+		{"386", 20, 12},
+		{"arm", 20, 12},
+		{"wasm", 24, 16},
+	}
+	for _, tt := range tests {
+		t.Run(tt.arch, func(t *testing.T) {
+			layouts, err := Static("testdata/static", tt.arch)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := layouts["Unpadded"].Size; got != tt.unpadded {
+				t.Errorf("Unpadded = %d B, want %d B\n%s", got, tt.unpadded, layouts["Unpadded"].Diagram())
+			}
+			if got := layouts["Node"].Size; got != tt.node {
+				t.Errorf("Node = %d B, want %d B", got, tt.node)
+			}
+			if f := layouts["Node"].Fields[1]; f.Type != "*Node" {
+				t.Errorf("next type = %q, want unqualified *Node", f.Type)
+			}
+		})
+	}
+
+	// On a 64-bit target the static model matches reflect on this host.
+	layouts, err := Static("testdata/static", "amd64")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if unsafe.Sizeof(uintptr(0)) == 8 && layouts["Unpadded"].Diagram() != For[unpadded]().Diagram() {
+		t.Errorf("static amd64 layout\n%s\ndiffers from reflect\n%s", layouts["Unpadded"].Diagram(), For[unpadded]().Diagram())
+	}
+	if _, err := Static("testdata/static", "vax"); err == nil {
+		t.Error("Static(vax) succeeded, want unknown GOARCH error")
+	}
+}
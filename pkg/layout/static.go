@@ -0,0 +1,71 @@
+package layout
+
+import (
+	"fmt"
+	"go/types"
+
+	"go-lab/pkg/internal/typecheck"
+)
+
+// Static returns the layouts of the named struct types of the package in
+// dir as the gc compiler lays them out for goarch, without building or
+// running anything for that architecture: files are selected with the
+// build constraints of goarch and sizes come from types.SizesFor.
+// Imported packages are type-checked for the host, which only matters for
+// imported types whose fields differ per architecture.
+func Static(dir, goarch string) (map[string]Layout, error) {
+	sizes := types.SizesFor("gc", goarch)
+	if sizes == nil {
+		return nil, fmt.Errorf("layout: unknown GOARCH %q", goarch)
+	}
+	pkg, _, err := typecheck.Dir(dir, goarch, sizes)
+	if err != nil {
+		return nil, fmt.Errorf("layout: %w", err)
+	}
+
+	out := map[string]Layout{}
+	scope := pkg.Scope()
+	for _, name := range scope.Names() {
+		tn, ok := scope.Lookup(name).(*types.TypeName)
+		if !ok || tn.IsAlias() {
+			continue
+		}
+		if n, ok := tn.Type().(*types.Named); ok && n.TypeParams().Len() > 0 {
+			continue
+		}
+		st, ok := tn.Type().Underlying().(*types.Struct)
+		if !ok {
+			continue
+		}
+		s := staticShape(st, sizes, types.RelativeTo(pkg))
+		out[name] = Layout{Name: name, Size: s.size, Align: s.align, Fields: fields(s, 0, 0)}
+	}
+	return out, nil
+}
+
+// staticShape returns the shape of st under sizes.
+func staticShape(st *types.Struct, sizes types.Sizes, qual types.Qualifier) *shape {
+	n := st.NumFields()
+	vars := make([]*types.Var, n)
+	for i := range n {
+		vars[i] = st.Field(i)
+	}
+	offsets := sizes.Offsetsof(vars)
+	s := &shape{size: uintptr(sizes.Sizeof(st)), align: uintptr(sizes.Alignof(st))}
+	for i, v := range vars {
+		t := v.Type()
+		m := member{
+			name:     v.Name(),
+			typ:      types.TypeString(t, qual),
+			offset:   uintptr(offsets[i]),
+			size:     uintptr(sizes.Sizeof(t)),
+			align:    uintptr(sizes.Alignof(t)),
+			embedded: v.Embedded(),
+		}
+		if inner, ok := t.Underlying().(*types.Struct); ok {
+			m.inner = staticShape(inner, sizes, qual)
+		}
+		s.members = append(s.members, m)
+	}
+	return s
+}
@@ -0,0 +1,13 @@
+package static
+
+type Unpadded struct {
+	a bool
+	b int64
+	c bool
+	d int32
+}
+
+type Node struct {
+	val  int64
+	next *Node
+}
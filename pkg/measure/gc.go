@@ -0,0 +1,85 @@
+package measure
+
+import (
+	"math"
+	"runtime/metrics"
+	"testing"
+)
+
+// gcMetrics are the runtime/metrics samples behind ReportGC.
+var gcMetrics = []string{
+	"/gc/cycles/total:gc-cycles",
+	"/gc/cycles/forced:gc-cycles",
+	"/cpu/classes/gc/total:cpu-seconds",
+	"/sched/pauses/total/gc:seconds",
+}
+
+// ReportGC attaches GC work read from runtime/metrics to the benchmark
+// results of b as custom metrics:
+//
+//	gc-cycles/op    completed GC cycles per iteration (forced ones excluded)
+//	gc-cpu-ns/op    estimated CPU time spent in the GC per iteration
+//	gc-pause-ns/op  stop-the-world GC pause time per iteration
+//
+// Call it before the benchmark loop; the metrics are reported when the
+// benchmark function returns. Pause times come from a histogram and are
+// estimated from its bucket midpoints.
+func ReportGC(b *testing.B) {
+	before := readGC()
+	b.Cleanup(func() {
+		if b.N == 0 {
+			return
+		}
+		after := readGC()
+		n := float64(b.N)
+		b.ReportMetric((after.cycles-before.cycles)/n, "gc-cycles/op")
+		b.ReportMetric((after.cpu-before.cpu)*1e9/n, "gc-cpu-ns/op")
+		b.ReportMetric((after.pause-before.pause)*1e9/n, "gc-pause-ns/op")
+	})
+}
+
+type gcCounters struct {
+	cycles float64 // non-forced cycles
+	cpu    float64 // seconds
+	pause  float64 // seconds
+}
+
+func readGC() gcCounters {
+	samples := make([]metrics.Sample, len(gcMetrics))
+	for i, name := range gcMetrics {
+		samples[i].Name = name
+	}
+	metrics.Read(samples)
+	var c gcCounters
+	if samples[0].Value.Kind() == metrics.KindUint64 && samples[1].Value.Kind() == metrics.KindUint64 {
+		c.cycles = float64(samples[0].Value.Uint64() - samples[1].Value.Uint64())
+	}
+	if samples[2].Value.Kind() == metrics.KindFloat64 {
+		c.cpu = samples[2].Value.Float64()
+	}
+	if samples[3].Value.Kind() == metrics.KindFloat64Histogram {
+		c.pause = histogramSum(samples[3].Value.Float64Histogram())
+	}
+	return c
+}
+
+// histogramSum estimates the sum of the values recorded in h from the
+// midpoints of its buckets; unbounded buckets use their finite bound.
+func histogramSum(h *metrics.Float64Histogram) float64 {
+	var sum float64
+	for i, n := range h.Counts {
+		if n == 0 {
+			continue
+		}
+		lo, hi := h.Buckets[i], h.Buckets[i+1]
+		v := (lo + hi) / 2
+		switch {
+		case math.IsInf(lo, -1):
+			v = hi
+		case math.IsInf(hi, 1):
+			v = lo
+		}
+		sum += v * float64(n)
+	}
+	return sum
+}
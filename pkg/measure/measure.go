@@ -0,0 +1,39 @@
+// Package measure provides the shared measurement tools used by every
+// experiment in the lab: allocation assertions, runtime/metrics and
+// runtime.ReadMemStats deltas, dead-code-elimination sinks and
+// sub-benchmark sweeps.
+package measure
+
+import "testing"
+
+// DefaultRuns is the number of runs averaged by the allocation helpers.
+const DefaultRuns = 100
+
+// Allocs reports the average number of heap allocations per call of f.
+func Allocs(f func()) float64 {
+	return testing.AllocsPerRun(DefaultRuns, f)
+}
+
+// AssertAllocs fails tb unless f allocates exactly want times per call.
+// Use it for patterns whose outcome is determined by the Go spec or by
+// well-established escape analysis rules. The observed count is logged in
+// the same format as ObserveAllocs so that runs can be compared.
+func AssertAllocs(tb testing.TB, name string, want float64, f func()) float64 {
+	tb.Helper()
+	got := Allocs(f)
+	tb.Logf("%s: allocs/run = %v (asserted %v)", name, got, want)
+	if got != want {
+		tb.Errorf("%s: want %v allocs, got %v", name, want, got)
+	}
+	return got
+}
+
+// ObserveAllocs logs the allocations per call of f without asserting.
+// Use it for exploratory patterns whose allocation count is the research
+// question itself and must not be constrained by a prior hypothesis.
+func ObserveAllocs(tb testing.TB, name string, f func()) float64 {
+	tb.Helper()
+	got := Allocs(f)
+	tb.Logf("%s: allocs/run = %v", name, got)
+	return got
+}
@@ -0,0 +1,97 @@
+package measure
+
+import (
+	"fmt"
+	"testing"
+)
+
+// recorder captures Errorf/Logf calls so assertion helpers can be tested
+// without failing the enclosing test.
+type recorder struct {
+	testing.TB
+	errors []string
+	logs   []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Logf(format string, args ...any) {
+	r.logs = append(r.logs, fmt.Sprintf(format, args...))
+}
+
+var ptrSink Sink[*[64]byte]
+
+func allocOne() { ptrSink.Set(new([64]byte)) }
+
+func TestAllocs(t *testing.T) {
+	if got := Allocs(func() {}); got != 0 {
+		t.Errorf("Allocs(empty) = %v, want 0", got)
+	}
+	if got := Allocs(allocOne); got != 1 {
+		t.Errorf("Allocs(allocOne) = %v, want 1", got)
+	}
+}
+
+func TestAssertAllocs(t *testing.T) {
+	r := &recorder{TB: t}
+	AssertAllocs(r, "allocOne", 1, allocOne)
+	if len(r.errors) != 0 {
+		t.Fatalf("unexpected failure: %v", r.errors)
+	}
+
+	AssertAllocs(r, "allocOne", 0, allocOne)
+	if len(r.errors) != 1 {
+		t.Fatalf("want 1 failure, got %v", r.errors)
+	}
+	if want := "allocOne: want 0 allocs, got 1"; r.errors[0] != want {
+		t.Errorf("message = %q, want %q", r.errors[0], want)
+	}
+	if want := "allocOne: allocs/run = 1 (asserted 0)"; len(r.logs) != 2 || r.logs[1] != want {
+		t.Errorf("logs = %q, want second %q", r.logs, want)
+	}
+}
+
+func TestObserveAllocs(t *testing.T) {
+	r := &recorder{TB: t}
+	got := ObserveAllocs(r, "allocOne", allocOne)
+	if got != 1 {
+		t.Errorf("ObserveAllocs = %v, want 1", got)
+	}
+	if len(r.errors) != 0 {
+		t.Errorf("observation must never fail: %v", r.errors)
+	}
+	if want := "allocOne: allocs/run = 1"; len(r.logs) != 1 || r.logs[0] != want {
+		t.Errorf("logs = %q, want [%q]", r.logs, want)
+	}
+}
+
+func TestSink(t *testing.T) {
+	var s Sink[string]
+	s.Set("x")
+	if got := s.Get(); got != "x" {
+		t.Errorf("Get() = %q, want %q", got, "x")
+	}
+}
+
+var bytesSink Sink[[]byte]
+
+func TestReportGC(t *testing.T) {
+	r := testing.Benchmark(func(b *testing.B) {
+		ReportGC(b)
+		for b.Loop() {
+			bytesSink.Set(make([]byte, 8<<20))
+		}
+	})
+	for _, unit := range []string{"gc-cycles/op", "gc-cpu-ns/op", "gc-pause-ns/op"} {
+		if _, ok := r.Extra[unit]; !ok {
+			t.Errorf("metric %s not reported: %v", unit, r.Extra)
+		}
+	}
+	if r.Extra["gc-cycles/op"] <= 0 {
+		t.Errorf("gc-cycles/op = %v for 8 MiB per op, want > 0", r.Extra["gc-cycles/op"])
+	}
+}
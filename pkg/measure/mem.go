@@ -0,0 +1,61 @@
+package measure
+
+import (
+	"runtime"
+	"testing"
+	"time"
+)
+
+// MemDelta is the difference between two runtime.MemStats snapshots taken
+// around ops calls of a function.
+type MemDelta struct {
+	Ops        int
+	Bytes      uint64        // TotalAlloc delta
+	Allocs     uint64        // Mallocs delta
+	Frees      uint64        // Frees delta
+	NumGC      uint32        // completed GC cycles
+	PauseTotal time.Duration // total STW pause time
+}
+
+// Mem runs f ops times and returns the heap statistics delta.
+// A GC is forced first so that the delta is not polluted by earlier garbage.
+func Mem(ops int, f func()) MemDelta {
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	for range ops {
+		f()
+	}
+	runtime.ReadMemStats(&after)
+	return MemDelta{
+		Ops:        ops,
+		Bytes:      after.TotalAlloc - before.TotalAlloc,
+		Allocs:     after.Mallocs - before.Mallocs,
+		Frees:      after.Frees - before.Frees,
+		NumGC:      after.NumGC - before.NumGC,
+		PauseTotal: time.Duration(after.PauseTotalNs - before.PauseTotalNs),
+	}
+}
+
+// BytesPerOp returns the average number of bytes allocated per op.
+func (d MemDelta) BytesPerOp() float64 {
+	if d.Ops == 0 {
+		return 0
+	}
+	return float64(d.Bytes) / float64(d.Ops)
+}
+
+// AllocsPerOp returns the average number of heap objects allocated per op.
+func (d MemDelta) AllocsPerOp() float64 {
+	if d.Ops == 0 {
+		return 0
+	}
+	return float64(d.Allocs) / float64(d.Ops)
+}
+
+// Log writes the per-op figures of d to tb.
+func (d MemDelta) Log(tb testing.TB, name string) {
+	tb.Helper()
+	tb.Logf("%s: %.1f B/op, %.2f allocs/op, %d GC (%d ops)",
+		name, d.BytesPerOp(), d.AllocsPerOp(), d.NumGC, d.Ops)
+}
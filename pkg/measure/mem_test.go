@@ -0,0 +1,26 @@
+package measure
+
+import "testing"
+
+func TestMem(t *testing.T) {
+	const ops = 1000
+	d := Mem(ops, allocOne)
+	if d.Ops != ops {
+		t.Errorf("Ops = %d, want %d", d.Ops, ops)
+	}
+	// Background runtime activity may add a few objects; never fewer.
+	if got := d.AllocsPerOp(); got < 1 || got > 1.1 {
+		t.Errorf("AllocsPerOp() = %v, want ~1", got)
+	}
+	if got := d.BytesPerOp(); got < 64 {
+		t.Errorf("BytesPerOp() = %v, want >= 64", got)
+	}
+	d.Log(t, "allocOne")
+}
+
+func TestMemDeltaZeroOps(t *testing.T) {
+	var d MemDelta
+	if d.BytesPerOp() != 0 || d.AllocsPerOp() != 0 {
+		t.Errorf("zero-op delta must report 0, got %v B/op %v allocs/op", d.BytesPerOp(), d.AllocsPerOp())
+	}
+}
@@ -0,0 +1,286 @@
+package measure
+
+import (
+	"math"
+	"runtime"
+	"runtime/metrics"
+	"testing"
+	"time"
+)
+
+// memMetrics are the runtime/metrics samples behind Metrics and
+// ReportMetrics, in snapshot field order.
+var memMetrics = []string{
+	"/gc/heap/allocs:bytes",
+	"/gc/heap/allocs:objects",
+	"/gc/heap/tiny/allocs:objects",
+	"/sched/goroutines:goroutines",
+	"/memory/classes/heap/objects:bytes",
+	"/memory/classes/total:bytes",
+	"/sched/latencies:seconds",
+	"/sched/pauses/stopping/other:seconds",
+}
+
+// snapshot is one reading of memMetrics.
+type snapshot struct {
+	bytes       uint64 // cumulative heap bytes allocated
+	allocs      uint64 // cumulative heap objects allocated, tiny ones included
+	goroutines  uint64
+	heapObjects uint64 // bytes of live and not yet swept objects
+	total       uint64 // bytes mapped by the runtime
+	sched       *metrics.Float64Histogram
+	stops       uint64 // non-GC stop-the-world pauses, e.g. ReadMemStats
+}
+
+// readMetrics reads memMetrics. Unlike runtime.ReadMemStats it does not
+// stop the world.
+func readMetrics() snapshot {
+	samples := make([]metrics.Sample, len(memMetrics))
+	for i, name := range memMetrics {
+		samples[i].Name = name
+	}
+	metrics.Read(samples)
+	u := func(i int) uint64 {
+		if samples[i].Value.Kind() != metrics.KindUint64 {
+			return 0
+		}
+		return samples[i].Value.Uint64()
+	}
+	h := func(i int) *metrics.Float64Histogram {
+		if samples[i].Value.Kind() != metrics.KindFloat64Histogram {
+			return &metrics.Float64Histogram{}
+		}
+		return samples[i].Value.Float64Histogram()
+	}
+	s := snapshot{
+		bytes:       u(0),
+		allocs:      u(1) + u(2),
+		goroutines:  u(3),
+		heapObjects: u(4),
+		total:       u(5),
+		sched:       h(6),
+	}
+	for _, n := range h(7).Counts {
+		s.stops += n
+	}
+	return s
+}
+
+// MetricsDelta is the difference between two runtime/metrics readings taken
+// around ops calls of a function. Bytes and Allocs correspond to the
+// TotalAlloc and Mallocs deltas of MemDelta.
+type MetricsDelta struct {
+	Ops         int
+	Bytes       uint64 // /gc/heap/allocs:bytes delta
+	Allocs      uint64 // /gc/heap/allocs:objects + /gc/heap/tiny/allocs:objects delta
+	Goroutines  int64  // change of /sched/goroutines
+	HeapObjects int64  // change of /memory/classes/heap/objects:bytes
+	Total       int64  // change of /memory/classes/total:bytes
+	Stops       uint64 // non-GC stop-the-world pauses
+
+	// sched holds the goroutine scheduling latencies recorded in between.
+	sched metrics.Float64Histogram
+}
+
+// Metrics runs f ops times and returns the runtime/metrics delta. It
+// neither forces a GC nor stops the world, so it can bracket code whose
+// timing matters. The price is precision: small objects are counted when
+// a P's cached span is refilled, so a short window may miss or include
+// part of a span's worth of objects.
+func Metrics(ops int, f func()) MetricsDelta {
+	before := readMetrics()
+	for range ops {
+		f()
+	}
+	return delta(ops, before, readMetrics())
+}
+
+func delta(ops int, before, after snapshot) MetricsDelta {
+	d := MetricsDelta{
+		Ops:         ops,
+		Bytes:       after.bytes - before.bytes,
+		Allocs:      after.allocs - before.allocs,
+		Goroutines:  int64(after.goroutines) - int64(before.goroutines),
+		HeapObjects: int64(after.heapObjects) - int64(before.heapObjects),
+		Total:       int64(after.total) - int64(before.total),
+		Stops:       after.stops - before.stops,
+	}
+	if len(after.sched.Counts) == len(before.sched.Counts) {
+		d.sched.Buckets = after.sched.Buckets
+		d.sched.Counts = make([]uint64, len(after.sched.Counts))
+		for i := range d.sched.Counts {
+			d.sched.Counts[i] = after.sched.Counts[i] - before.sched.Counts[i]
+		}
+	}
+	return d
+}
+
+// BytesPerOp returns the average number of bytes allocated per op.
+func (d MetricsDelta) BytesPerOp() float64 {
+	if d.Ops == 0 {
+		return 0
+	}
+	return float64(d.Bytes) / float64(d.Ops)
+}
+
+// AllocsPerOp returns the average number of heap objects allocated per op.
+func (d MetricsDelta) AllocsPerOp() float64 {
+	if d.Ops == 0 {
+		return 0
+	}
+	return float64(d.Allocs) / float64(d.Ops)
+}
+
+// SchedLatency returns the q-quantile (0..1) of the time goroutines spent
+// runnable before running, estimated from the upper bounds of the
+// histogram buckets. It is 0 when no goroutine was scheduled.
+func (d MetricsDelta) SchedLatency(q float64) time.Duration {
+	var n uint64
+	for _, c := range d.sched.Counts {
+		n += c
+	}
+	if n == 0 {
+		return 0
+	}
+	rank := uint64(math.Ceil(q * float64(n)))
+	var seen uint64
+	for i, c := range d.sched.Counts {
+		seen += c
+		if seen >= rank && c > 0 {
+			hi := d.sched.Buckets[i+1]
+			if math.IsInf(hi, 1) {
+				hi = d.sched.Buckets[i]
+			}
+			return time.Duration(hi * 1e9)
+		}
+	}
+	return 0
+}
+
+// Log writes the per-op figures of d to tb.
+func (d MetricsDelta) Log(tb testing.TB, name string) {
+	tb.Helper()
+	tb.Logf("%s: %.1f B/op, %.2f allocs/op, %+d goroutines, sched p99 %v (%d ops)",
+		name, d.BytesPerOp(), d.AllocsPerOp(), d.Goroutines, d.SchedLatency(0.99), d.Ops)
+}
+
+// ReportMetrics attaches runtime/metrics readings taken around the
+// benchmark to the results of b as custom metrics:
+//
+//	heap-B/op       bytes allocated per iteration
+//	heap-allocs/op  heap objects allocated per iteration
+//	goroutines      goroutines left running (leaks) at the end
+//	heap-live-B     growth of the bytes held by heap objects
+//	mapped-B        growth of the memory mapped by the runtime
+//	sched-p99-ns    99th percentile goroutine scheduling latency
+//
+// Unlike -benchmem and runtime.ReadMemStats, reading runtime/metrics does
+// not stop the world. Call it before the benchmark loop; the metrics are
+// reported when the benchmark function returns.
+func ReportMetrics(b *testing.B) {
+	before := readMetrics()
+	b.Cleanup(func() {
+		if b.N == 0 {
+			return
+		}
+		d := delta(b.N, before, readMetrics())
+		b.ReportMetric(d.BytesPerOp(), "heap-B/op")
+		b.ReportMetric(d.AllocsPerOp(), "heap-allocs/op")
+		b.ReportMetric(float64(d.Goroutines), "goroutines")
+		b.ReportMetric(float64(d.HeapObjects), "heap-live-B")
+		b.ReportMetric(float64(d.Total), "mapped-B")
+		b.ReportMetric(float64(d.SchedLatency(0.99).Nanoseconds()), "sched-p99-ns")
+	})
+}
+
+// BackendCost is what reading memory statistics during a measurement cost
+// under one backend.
+type BackendCost struct {
+	Backend     string // "none", "runtime/metrics" or "runtime.ReadMemStats"
+	Reads       int
+	NsPerOp     float64 // wall time per op, reads included
+	NsPerRead   float64 // extra wall time per read compared with "none"
+	Stops       uint64  // non-GC stop-the-world pauses during the measurement
+	AllocsPerOp float64 // as reported by the backend ("none": runtime/metrics)
+}
+
+// BackendComparison is the result of CompareBackends, "none" first.
+type BackendComparison []BackendCost
+
+// CompareBackends measures how much each way of reading memory statistics
+// distorts the code it measures. It runs f ops times without reads, then
+// reading runtime/metrics after every `every` ops, then calling
+// runtime.ReadMemStats as often, and reports the time per op, the
+// stop-the-world pauses and the allocation counts each backend saw.
+func CompareBackends(ops, every int, f func()) BackendComparison {
+	every = max(every, 1)
+	var ms runtime.MemStats
+	allocSamples := []metrics.Sample{
+		{Name: "/gc/heap/allocs:objects"},
+		{Name: "/gc/heap/tiny/allocs:objects"},
+	}
+	backends := []struct {
+		name   string
+		allocs func() uint64
+	}{
+		{"none", nil},
+		{"runtime/metrics", func() uint64 {
+			metrics.Read(allocSamples)
+			return allocSamples[0].Value.Uint64() + allocSamples[1].Value.Uint64()
+		}},
+		{"runtime.ReadMemStats", func() uint64 { runtime.ReadMemStats(&ms); return ms.Mallocs }},
+	}
+	for range ops / 10 {
+		f() // warm up
+	}
+	var cmp BackendComparison
+	for _, be := range backends {
+		runtime.GC()
+		c := BackendCost{Backend: be.name}
+		before := readMetrics()
+		var first, last uint64
+		if be.allocs != nil {
+			first = be.allocs()
+			c.Reads++
+		}
+		start := time.Now()
+		for i := range ops {
+			f()
+			if be.allocs != nil && (i+1)%every == 0 {
+				last = be.allocs()
+				c.Reads++
+			}
+		}
+		elapsed := time.Since(start)
+		after := readMetrics()
+		c.NsPerOp = float64(elapsed.Nanoseconds()) / float64(ops)
+		c.Stops = after.stops - before.stops
+		// The last read follows the last multiple of every.
+		counted := ops - ops%every
+		if be.allocs == nil {
+			first, last, counted = before.allocs, after.allocs, ops
+		}
+		if counted > 0 {
+			c.AllocsPerOp = float64(last-first) / float64(counted)
+		}
+		if c.Reads > 1 && len(cmp) > 0 {
+			c.NsPerRead = (c.NsPerOp - cmp[0].NsPerOp) * float64(ops) / float64(c.Reads-1)
+		}
+		cmp = append(cmp, c)
+	}
+	return cmp
+}
+
+// Log writes one line per backend to tb, with the change of ns/op against
+// "none".
+func (cmp BackendComparison) Log(tb testing.TB, name string) {
+	tb.Helper()
+	for _, c := range cmp {
+		change := 0.0
+		if base := cmp[0].NsPerOp; base > 0 {
+			change = (c.NsPerOp/base - 1) * 100
+		}
+		tb.Logf("%s: %s: %.1f ns/op (%+.1f%%), %d reads at %.0f ns, %d STW pauses, %.2f allocs/op",
+			name, c.Backend, c.NsPerOp, change, c.Reads, c.NsPerRead, c.Stops, c.AllocsPerOp)
+	}
+}
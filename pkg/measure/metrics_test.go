@@ -0,0 +1,66 @@
+package measure
+
+import (
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestMetrics(t *testing.T) {
+	// More ops than TestMem: the count may lag by part of a span.
+	const ops = 10_000
+	d := Metrics(ops, allocOne)
+	if d.Ops != ops {
+		t.Errorf("Ops = %d, want %d", d.Ops, ops)
+	}
+	// Background activity may add a few objects, the span lag remove some.
+	if got := d.AllocsPerOp(); got < 0.95 || got > 1.1 {
+		t.Errorf("AllocsPerOp() = %v, want ~1", got)
+	}
+	if got := d.BytesPerOp(); got < 0.95*64 {
+		t.Errorf("BytesPerOp() = %v, want ~64", got)
+	}
+	if d.Stops != 0 {
+		t.Errorf("reading runtime/metrics stopped the world %d times", d.Stops)
+	}
+	d.Log(t, "allocOne")
+}
+
+func TestMetricsGoroutines(t *testing.T) {
+	done := make(chan struct{})
+	// The runtime samples scheduling latency on every 8th transition.
+	const n = 100
+	d := Metrics(n, func() {
+		go func() { <-done }()
+		runtime.Gosched() // let it run: scheduling latency is recorded then
+	})
+	close(done)
+	if d.Goroutines != n {
+		t.Errorf("Goroutines = %+d, want +%d", d.Goroutines, n)
+	}
+	if d.SchedLatency(0.99) <= 0 || d.SchedLatency(0.99) > time.Second {
+		t.Errorf("SchedLatency(0.99) = %v, want a positive latency", d.SchedLatency(0.99))
+	}
+}
+
+func TestCompareBackends(t *testing.T) {
+	cmp := CompareBackends(10_000, 100, allocOne)
+	if len(cmp) != 3 || cmp[0].Backend != "none" {
+		t.Fatalf("CompareBackends = %+v", cmp)
+	}
+	// runtime/metrics sees the objects of a cached span only once the span
+	// is refilled, so its counts may lag by part of a span.
+	for _, c := range cmp {
+		if c.AllocsPerOp < 0.9 || c.AllocsPerOp > 1.1 {
+			t.Errorf("%s: AllocsPerOp = %v, want ~1", c.Backend, c.AllocsPerOp)
+		}
+	}
+	// Every ReadMemStats stops the world; runtime/metrics never does.
+	if got := cmp[1].Stops; got != 0 {
+		t.Errorf("runtime/metrics: %d STW pauses, want 0", got)
+	}
+	if got, want := cmp[2].Stops, uint64(cmp[2].Reads); got < want {
+		t.Errorf("runtime.ReadMemStats: %d STW pauses, want >= %d", got, want)
+	}
+	cmp.Log(t, "allocOne")
+}
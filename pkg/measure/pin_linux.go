@@ -0,0 +1,70 @@
+package measure
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"syscall"
+	"unsafe"
+)
+
+// cpuSet is the kernel's cpu_set_t: one bit per CPU, 1024 CPUs.
+type cpuSet [1024 / 64]uint64
+
+// Pin restricts the current process to cpus with sched_setaffinity. The
+// affinity is set on every thread of the process, so goroutines cannot
+// migrate to other CPUs, and it is inherited by the threads and processes
+// started afterwards (go test and its test binaries). Pinning a benchmark
+// to CPUs no other work is scheduled on removes most scheduler noise.
+func Pin(cpus []int) error {
+	var set cpuSet
+	for _, c := range cpus {
+		if c < 0 || c >= len(set)*64 {
+			return fmt.Errorf("pin: CPU %d out of range", c)
+		}
+		set[c/64] |= 1 << (c % 64)
+	}
+	// Threads started while the affinity is being set may inherit the old
+	// one; repeat until every thread has been seen.
+	pinned := map[int]bool{}
+	for {
+		des, err := os.ReadDir("/proc/self/task")
+		if err != nil {
+			return fmt.Errorf("pin: %w", err)
+		}
+		added := false
+		for _, de := range des {
+			tid, err := strconv.Atoi(de.Name())
+			if err != nil || pinned[tid] {
+				continue
+			}
+			_, _, errno := syscall.RawSyscall(syscall.SYS_SCHED_SETAFFINITY,
+				uintptr(tid), unsafe.Sizeof(set), uintptr(unsafe.Pointer(&set)))
+			if errno != 0 && !errors.Is(errno, syscall.ESRCH) { // ESRCH: thread exited
+				return fmt.Errorf("pin thread %d to CPUs %v: %w", tid, cpus, errno)
+			}
+			pinned[tid], added = true, true
+		}
+		if !added {
+			return nil
+		}
+	}
+}
+
+// Affinity returns the CPUs the calling thread may run on.
+func Affinity() ([]int, error) {
+	var set cpuSet
+	_, _, errno := syscall.RawSyscall(syscall.SYS_SCHED_GETAFFINITY,
+		0, unsafe.Sizeof(set), uintptr(unsafe.Pointer(&set)))
+	if errno != 0 {
+		return nil, fmt.Errorf("affinity: %w", errno)
+	}
+	var cpus []int
+	for c := range len(set) * 64 {
+		if set[c/64]&(1<<(c%64)) != 0 {
+			cpus = append(cpus, c)
+		}
+	}
+	return cpus, nil
+}
@@ -0,0 +1,20 @@
+//go:build !linux
+
+package measure
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Pin restricts the current process to cpus. It is only implemented on
+// Linux.
+func Pin(cpus []int) error {
+	return fmt.Errorf("pin: %w", errors.ErrUnsupported)
+}
+
+// Affinity returns the CPUs the calling thread may run on. It is only
+// implemented on Linux.
+func Affinity() ([]int, error) {
+	return nil, fmt.Errorf("affinity: %w", errors.ErrUnsupported)
+}
@@ -0,0 +1,62 @@
+package measure
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseCPUList(t *testing.T) {
+	got, err := ParseCPUList("4,0-2, 2")
+	if err != nil || !slices.Equal(got, []int{0, 1, 2, 4}) {
+		t.Errorf("ParseCPUList = %v, %v; want [0 1 2 4]", got, err)
+	}
+	for _, bad := range []string{"", "x", "3-1", "-1"} {
+		if _, err := ParseCPUList(bad); err == nil {
+			t.Errorf("ParseCPUList(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestPin(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("Pin is only implemented on Linux")
+	}
+	orig, err := Affinity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := Pin(orig); err != nil {
+			t.Error(err)
+		}
+	})
+	cpu := orig[len(orig)-1]
+	if err := Pin([]int{cpu}); err != nil {
+		t.Fatal(err)
+	}
+	// Every thread, not just the calling one, is pinned.
+	tasks, err := filepath.Glob("/proc/self/task/*/status")
+	if err != nil || len(tasks) == 0 {
+		t.Fatalf("no threads listed: %v", err)
+	}
+	for _, status := range tasks {
+		b, err := os.ReadFile(status)
+		if err != nil {
+			continue // thread exited
+		}
+		for line := range strings.Lines(string(b)) {
+			if v, ok := strings.CutPrefix(line, "Cpus_allowed_list:"); ok {
+				if got, _ := ParseCPUList(v); !slices.Equal(got, []int{cpu}) {
+					t.Errorf("%s: Cpus_allowed_list = %q, want %d", status, strings.TrimSpace(v), cpu)
+				}
+			}
+		}
+	}
+	if err := Pin([]int{4096}); err == nil {
+		t.Error("Pin of an out-of-range CPU succeeded")
+	}
+}
@@ -0,0 +1,21 @@
+package measure
+
+// Sink keeps a result observable so the compiler cannot remove the
+// computation that produced it via dead-code elimination.
+//
+// Declare one package-level sink per result type:
+//
+//	var sink measure.Sink[int]
+//
+//	for b.Loop() {
+//		sink.Set(Compute())
+//	}
+type Sink[T any] struct {
+	v T
+}
+
+// Set stores v in the sink.
+func (s *Sink[T]) Set(v T) { s.v = v }
+
+// Get returns the last value stored in the sink.
+func (s *Sink[T]) Get() T { return s.v }
@@ -0,0 +1,31 @@
+package measure
+
+import (
+	"fmt"
+	"testing"
+)
+
+// Sweep runs fn as one sub-benchmark per value, named "key=value"
+// (e.g. "N=64"), so that the independent variable is encoded in the
+// benchmark name.
+//
+// Setup done inside fn before the first b.Loop call is excluded from timing.
+func Sweep[P any](b *testing.B, key string, values []P, fn func(b *testing.B, v P)) {
+	b.Helper()
+	for _, v := range values {
+		b.Run(sweepName(key, v), func(b *testing.B) {
+			fn(b, v)
+		})
+	}
+}
+
+// SweepN is Sweep over the conventional size axis "N".
+func SweepN(b *testing.B, sizes []int, fn func(b *testing.B, n int)) {
+	b.Helper()
+	Sweep(b, "N", sizes, fn)
+}
+
+// sweepName formats a sub-benchmark name as "key=value".
+func sweepName(key string, v any) string {
+	return fmt.Sprintf("%s=%v", key, v)
+}
@@ -0,0 +1,36 @@
+package measure
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSweepName(t *testing.T) {
+	tests := []struct {
+		key  string
+		v    any
+		want string
+	}{
+		{"N", 64, "N=64"},
+		{"size", "XLarge", "size=XLarge"},
+	}
+	for _, tt := range tests {
+		if got := sweepName(tt.key, tt.v); got != tt.want {
+			t.Errorf("sweepName(%q, %v) = %q, want %q", tt.key, tt.v, got, tt.want)
+		}
+	}
+}
+
+func TestSweepN(t *testing.T) {
+	var values []int
+	testing.Benchmark(func(b *testing.B) {
+		SweepN(b, []int{2, 64}, func(b *testing.B, n int) {
+			if !slices.Contains(values, n) {
+				values = append(values, n)
+			}
+		})
+	})
+	if !slices.Equal(values, []int{2, 64}) {
+		t.Errorf("values = %v, want [2 64]", values)
+	}
+}
@@ -0,0 +1,257 @@
+// Package report renders a stored run in the layout of
+// .github/PULL_REQUEST_TEMPLATE.md, so that every experiment pull request
+// reports its environment, static sizes, raw benchmark output and
+// statistical summary in an identical, reproducible format.
+package report
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"go-lab/pkg/benchfmt"
+	"go-lab/pkg/hypothesis"
+	"go-lab/pkg/runner"
+	"go-lab/pkg/stats"
+)
+
+// Size is one unsafe.Sizeof observation logged by a test.
+type Size struct {
+	Struct string
+	Bytes  int
+	Test   string // test that logged it, e.g. "TestSize/Unpadded"
+}
+
+// Row is one line of the Summary Table.
+type Row struct {
+	Metric string
+	A, B   string
+	Diff   string
+}
+
+// Data is everything the pull request template needs.
+type Data struct {
+	Issue     int // 0 leaves the placeholder in place
+	Topic     string
+	GoVersion string
+	GOOS      string
+	GOARCH    string
+	CPU       string
+	Kernel    string
+	Sizes     []Size
+	Benchmark string // benchmark-format lines of the raw output
+	PatternA  string
+	PatternB  string
+	Summary   []Row
+	Label     hypothesis.Label // empty when no TestHypothesis logged one
+	Charts    []string         // chart image paths relative to the report; none leaves the section out
+}
+
+// Build collects the report data for topic from run. Pattern A and B are
+// benchmark base names; when both are empty and the topic has exactly two
+// benchmarks, those two are used.
+func Build(run *runner.Run, topic, patternA, patternB string) (Data, error) {
+	res, ok := run.Result(topic)
+	if !ok {
+		return Data{}, fmt.Errorf("run %s has no results for %q", run.ID, topic)
+	}
+	raw, err := run.RawOutput(res)
+	if err != nil {
+		return Data{}, fmt.Errorf("report %s: %w", topic, err)
+	}
+	bench, err := benchfmt.Extract(bytes.NewReader(raw))
+	if err != nil {
+		return Data{}, fmt.Errorf("report %s: %w", topic, err)
+	}
+	d := Data{
+		Topic:     topic,
+		GoVersion: run.GoVersion,
+		GOOS:      run.GOOS,
+		GOARCH:    run.GOARCH,
+		CPU:       run.System.CPU,
+		Kernel:    run.System.Kernel,
+		Sizes:     ParseSizes(raw),
+		Benchmark: bench,
+		Label:     ParseLabel(raw),
+	}
+	if patternA == "" && patternB == "" {
+		patternA, patternB = onlyPair(res.Benchmarks)
+	}
+	if patternA != "" && patternB != "" {
+		d.PatternA, d.PatternB = patternA, patternB
+		d.Summary = Summarize(res.Benchmarks, patternA, patternB)
+	}
+	return d, nil
+}
+
+// onlyPair returns the two base names of results when there are exactly two.
+func onlyPair(results []benchfmt.Result) (string, string) {
+	var bases []string
+	for _, r := range results {
+		if !slices.Contains(bases, r.Name.Base) {
+			bases = append(bases, r.Name.Base)
+		}
+	}
+	if len(bases) != 2 {
+		return "", ""
+	}
+	return bases[0], bases[1]
+}
+
+// Summarize compares patternA against patternB for every unit both report,
+// one row per unit and shared axis combination.
+func Summarize(results []benchfmt.Result, patternA, patternB string) []Row {
+	var units []string
+	for _, r := range results {
+		for _, v := range r.Values {
+			if !slices.Contains(units, v.Unit) {
+				units = append(units, v.Unit)
+			}
+		}
+	}
+	var rows []Row
+	for _, unit := range units {
+		for _, p := range benchfmt.Pairs(results, unit, patternA, patternB) {
+			c := stats.Compare(p.A, p.B, stats.DefaultAlpha)
+			metric := unit
+			if p.Key != "" && !strings.HasPrefix(p.Key, "-") {
+				metric += " (" + p.Key + ")"
+			}
+			diff := c.String()
+			if c.A.N > 1 && c.B.N > 1 {
+				diff += fmt.Sprintf(" (p=%.3f n=%d)", c.P, c.A.N)
+			}
+			rows = append(rows, Row{
+				Metric: metric,
+				A:      stats.FormatSummary(c.A),
+				B:      stats.FormatSummary(c.B),
+				Diff:   diff,
+			})
+		}
+	}
+	return rows
+}
+
+var (
+	runLine  = regexp.MustCompile(`^=== RUN\s+(\S+)`)
+	sizeLine = regexp.MustCompile(`unsafe\.Sizeof\(([\w.]+)\{\}\) = (\d+) B`)
+	// labelLine matches the "Result: result:..." line logged by hypothesis.Check.
+	labelLine = regexp.MustCompile(`Result: (result:\w+)`)
+)
+
+// ParseSizes extracts the "unsafe.Sizeof(T{}) = N B" lines logged by tests
+// such as TestSize and TestStructSizes. Repetitions (-count) are dropped.
+func ParseSizes(raw []byte) []Size {
+	var (
+		sizes []Size
+		test  string
+	)
+	sc := bufio.NewScanner(bytes.NewReader(raw))
+	for sc.Scan() {
+		line := sc.Text()
+		if m := runLine.FindStringSubmatch(line); m != nil {
+			test = m[1]
+			continue
+		}
+		m := sizeLine.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		if slices.ContainsFunc(sizes, func(s Size) bool { return s.Struct == m[1] }) {
+			continue
+		}
+		n, _ := strconv.Atoi(m[2])
+		sizes = append(sizes, Size{Struct: m[1], Bytes: n, Test: test})
+	}
+	return sizes
+}
+
+// ParseLabel returns the result label logged by hypothesis.Check. When
+// several hypotheses were checked, the weakest outcome wins:
+// unexpected over inconclusive over verified.
+func ParseLabel(raw []byte) hypothesis.Label {
+	rank := map[hypothesis.Label]int{hypothesis.Verified: 1, hypothesis.Inconclusive: 2, hypothesis.Unexpected: 3}
+	var label hypothesis.Label
+	for _, m := range labelLine.FindAllSubmatch(raw, -1) {
+		if l := hypothesis.Label(m[1]); rank[l] > rank[label] {
+			label = l
+		}
+	}
+	return label
+}
+
+// Render writes d in the layout of the pull request template.
+func Render(w io.Writer, d Data) error {
+	if err := tmpl.Execute(w, d); err != nil {
+		return fmt.Errorf("render report: %w", err)
+	}
+	return nil
+}
+
+var tmpl = template.Must(template.New("pr").Parse(prTemplate))
+
+const prTemplate = `## Summary
+
+Closes #{{if .Issue}}{{.Issue}}{{else}}<!-- Issue番号 -->{{end}}
+
+<!-- 実験の目的を1-2行で記述 -->
+
+## Environment
+
+| Key | Value |
+|:---|:---|
+| Go | ` + "`{{.GoVersion}}`" + ` |
+| OS/Arch | ` + "`{{.GOOS}}/{{.GOARCH}}`" + ` |
+| CPU | {{.CPU}} |
+| Kernel | {{.Kernel}} |
+
+## Results
+
+### Size / Static Analysis
+
+<!-- unsafe.Sizeof やオフセット等の静的検証結果 -->
+
+| Struct | Size | Note |
+|:---|---:|:---|
+{{- range .Sizes}}
+| {{.Struct}} | {{.Bytes}} B | {{.Test}} |
+{{- else}}
+| A | -- B | |
+| B | -- B | |
+{{- end}}
+
+### Benchmark
+
+` + "```text" + `
+{{.Benchmark}}` + "```" + `
+
+### Summary Table
+
+| Metric | {{if .PatternA}}{{.PatternA}}{{else}}Pattern A{{end}} | {{if .PatternB}}{{.PatternB}}{{else}}Pattern B{{end}} | Diff |
+|:---|---:|---:|:---|
+{{- range .Summary}}
+| {{.Metric}} | {{.A}} | {{.B}} | {{.Diff}} |
+{{- else}}
+| | | | |
+{{- end}}
+{{- if .Charts}}
+
+### Charts
+{{range .Charts}}
+![{{.}}]({{.}})
+{{- end}}
+{{- end}}
+
+## Conclusion
+
+<!-- verified / unexpected / inconclusive のいずれか -->
+
+- **Result**: ` + "`{{if .Label}}{{.Label}}{{else}}result:___{{end}}`" + `
+- <!-- 仮説に対する結論を1-2行で記述 -->
+`
@@ -0,0 +1,139 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"go-lab/pkg/benchfmt"
+	"go-lab/pkg/hypothesis"
+)
+
+const sampleOutput = `=== RUN   TestSize
+=== RUN   TestSize/Unpadded
+    padding_test.go:26: unsafe.Sizeof(Unpadded{}) = 24 B
+=== RUN   TestSize/Padded
+    padding_test.go:26: unsafe.Sizeof(Padded{}) = 16 B
+--- PASS: TestSize (0.00s)
+=== RUN   TestHypothesis
+    hypothesis_test.go:40: Field order
+        | Metric | A | B | Diff |
+        Result: result:verified
+--- PASS: TestHypothesis (0.00s)
+=== RUN   TestSize
+=== RUN   TestSize/Unpadded
+    padding_test.go:26: unsafe.Sizeof(Unpadded{}) = 24 B
+goos: linux
+goarch: amd64
+pkg: go-lab/experiments/struct-padding
+BenchmarkUnpadded
+BenchmarkUnpadded-8   	 1000	  1200 ns/op	  24 B/op	  1 allocs/op
+BenchmarkUnpadded-8   	 1000	  1210 ns/op	  24 B/op	  1 allocs/op
+BenchmarkPadded-8     	 1000	   800 ns/op	  16 B/op	  1 allocs/op
+BenchmarkPadded-8     	 1000	   810 ns/op	  16 B/op	  1 allocs/op
+PASS
+ok  	go-lab/experiments/struct-padding	1.2s
+`
+
+func TestParseSizes(t *testing.T) {
+	got := ParseSizes([]byte(sampleOutput))
+	want := []Size{
+		{"Unpadded", 24, "TestSize/Unpadded"},
+		{"Padded", 16, "TestSize/Padded"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("ParseSizes = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("sizes[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestParseLabel(t *testing.T) {
+	if got := ParseLabel([]byte(sampleOutput)); got != hypothesis.Verified {
+		t.Errorf("ParseLabel = %q, want %q", got, hypothesis.Verified)
+	}
+	mixed := "Result: result:verified\nResult: result:unexpected\nResult: result:inconclusive\n"
+	if got := ParseLabel([]byte(mixed)); got != hypothesis.Unexpected {
+		t.Errorf("ParseLabel(mixed) = %q, want %q", got, hypothesis.Unexpected)
+	}
+	if got := ParseLabel(nil); got != "" {
+		t.Errorf("ParseLabel(nil) = %q, want empty", got)
+	}
+}
+
+func TestRender(t *testing.T) {
+	results, err := benchfmt.Parse(strings.NewReader(sampleOutput))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bench, err := benchfmt.Extract(strings.NewReader(sampleOutput))
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, b := onlyPair(results)
+	d := Data{
+		Issue:     12,
+		GoVersion: "go1.26.0",
+		GOOS:      "linux",
+		GOARCH:    "amd64",
+		CPU:       "Intel(R) Xeon(R) Processor",
+		Kernel:    "6.8.0",
+		Sizes:     ParseSizes([]byte(sampleOutput)),
+		Benchmark: bench,
+		PatternA:  a,
+		PatternB:  b,
+		Summary:   Summarize(results, a, b),
+		Label:     ParseLabel([]byte(sampleOutput)),
+		Charts:    []string{"struct-padding-ns-op-bars.svg"},
+	}
+	var buf bytes.Buffer
+	if err := Render(&buf, d); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"Closes #12\n",
+		"| Go | `go1.26.0` |\n",
+		"| OS/Arch | `linux/amd64` |\n",
+		"| CPU | Intel(R) Xeon(R) Processor |\n",
+		"| Unpadded | 24 B | TestSize/Unpadded |\n| Padded | 16 B | TestSize/Padded |\n",
+		"```text\ngoos: linux\n",
+		"BenchmarkPadded-8     \t 1000\t   810 ns/op\t  16 B/op\t  1 allocs/op\n```\n",
+		"| Metric | Unpadded | Padded | Diff |\n",
+		"| ns/op | 1205 ±0% | 805 ±1% | ~ (p=0.333 n=2) |\n",
+		"| allocs/op | 1 ±0% | 1 ±0% | ~ (p=1.000 n=2) |\n",
+		"| 1 ±0% | 1 ±0% | ~ (p=1.000 n=2) |\n\n### Charts\n\n![struct-padding-ns-op-bars.svg](struct-padding-ns-op-bars.svg)\n\n## Conclusion",
+		"- **Result**: `result:verified`\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("report lacks %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "BenchmarkUnpadded\n") || strings.Contains(out, "PASS") {
+		t.Errorf("benchmark block contains non-result lines:\n%s", out)
+	}
+}
+
+func TestRenderPlaceholders(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Render(&buf, Data{}); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"Closes #<!-- Issue番号 -->\n",
+		"| A | -- B | |\n| B | -- B | |\n",
+		"| Metric | Pattern A | Pattern B | Diff |\n|:---|---:|---:|:---|\n| | | | |\n",
+		"- **Result**: `result:___`\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("report lacks %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "### Charts") {
+		t.Errorf("report without charts has a Charts section:\n%s", out)
+	}
+}
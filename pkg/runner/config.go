@@ -0,0 +1,86 @@
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+)
+
+// Config is one configuration experiments are built and run with: a
+// toolchain plus go build flags and environment. The zero Config runs the
+// go command found in PATH with no extra flags.
+type Config struct {
+	Name      string    `json:"name"` // label, e.g. "go1.23.4" or "-gcflags=-l"
+	Toolchain Toolchain `json:"toolchain"`
+	Flags     []string  `json:"flags,omitempty"` // go build flags, e.g. -gcflags=-l, -pgo=off
+	Env       []string  `json:"env,omitempty"`   // e.g. GOEXPERIMENT=noswissmap, GOAMD64=v3
+}
+
+var envToken = regexp.MustCompile(`^[A-Z][A-Z0-9_]*=`)
+
+// ParseConfig parses a build configuration: ";"-separated go build flags
+// and environment assignments, e.g. "-gcflags=-N -l" or
+// "-pgo=off;GOAMD64=v3". A flag keeps its spaces, so "-gcflags=-N -l" is
+// one flag. The configuration is named after spec; an empty spec is the
+// default build, named "default".
+func ParseConfig(spec string) (Config, error) {
+	c := Config{Name: strings.TrimSpace(spec)}
+	for tok := range strings.SplitSeq(spec, ";") {
+		tok = strings.TrimSpace(tok)
+		switch {
+		case tok == "":
+		case strings.HasPrefix(tok, "-"):
+			c.Flags = append(c.Flags, tok)
+		case envToken.MatchString(tok):
+			c.Env = append(c.Env, tok)
+		default:
+			return Config{}, fmt.Errorf("build configuration %q: %q is neither a -flag nor a VAR=value", spec, tok)
+		}
+	}
+	if c.Name == "" {
+		c.Name = "default"
+	}
+	return c, nil
+}
+
+// Build describes the build flags and environment of c, "" for the default
+// build. Runs with different Build values measure different binaries.
+func (c Config) Build() string {
+	return strings.Join(append(append([]string(nil), c.Flags...), c.Env...), ";")
+}
+
+var unsafeChars = regexp.MustCompile(`[^A-Za-z0-9._=+-]+`)
+
+// dirName returns a file name for c.
+func (c Config) dirName() string {
+	return strings.Trim(unsafeChars.ReplaceAllString(c.Name, "_"), "_")
+}
+
+// command returns the go command of c running args in dir.
+func (c Config) command(ctx context.Context, dir string, args ...string) *exec.Cmd {
+	cmd := c.Toolchain.command(ctx, dir, args...)
+	if len(c.Env) > 0 {
+		if cmd.Env == nil {
+			cmd.Env = os.Environ()
+		}
+		cmd.Env = append(cmd.Env, c.Env...)
+	}
+	return cmd
+}
+
+// goEnv reads the go env variables names under c in dir.
+func (c Config) goEnv(ctx context.Context, dir string, names ...string) (map[string]string, error) {
+	out, err := c.command(ctx, dir, append([]string{"env", "-json"}, names...)...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("go env: %w", err)
+	}
+	env := map[string]string{}
+	if err := json.Unmarshal(out, &env); err != nil {
+		return nil, fmt.Errorf("parse go env: %w", err)
+	}
+	return env, nil
+}
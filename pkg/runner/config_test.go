@@ -0,0 +1,33 @@
+package runner
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseConfig(t *testing.T) {
+	for _, tt := range []struct {
+		spec, name, build string
+		flags, env        []string
+	}{
+		{"", "default", "", nil, nil},
+		{"-gcflags=-N -l", "-gcflags=-N -l", "-gcflags=-N -l", []string{"-gcflags=-N -l"}, nil},
+		{"-pgo=off; GOAMD64=v3", "-pgo=off; GOAMD64=v3", "-pgo=off;GOAMD64=v3", []string{"-pgo=off"}, []string{"GOAMD64=v3"}},
+		{"GOEXPERIMENT=noswissmap", "GOEXPERIMENT=noswissmap", "GOEXPERIMENT=noswissmap", nil, []string{"GOEXPERIMENT=noswissmap"}},
+	} {
+		c, err := ParseConfig(tt.spec)
+		if err != nil {
+			t.Errorf("ParseConfig(%q): %v", tt.spec, err)
+			continue
+		}
+		if c.Name != tt.name || c.Build() != tt.build || !slices.Equal(c.Flags, tt.flags) || !slices.Equal(c.Env, tt.env) {
+			t.Errorf("ParseConfig(%q) = %+v (build %q)", tt.spec, c, c.Build())
+		}
+	}
+	if _, err := ParseConfig("-l;noinline"); err == nil {
+		t.Error("ParseConfig accepted a token that is neither flag nor assignment")
+	}
+	if got := (Config{Name: "-gcflags=-N -l"}).dirName(); got != "-gcflags=-N_-l" {
+		t.Errorf("dirName = %q", got)
+	}
+}
@@ -0,0 +1,289 @@
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-lab/pkg/benchfmt"
+	"go-lab/pkg/stats"
+)
+
+// matrixManifest is the file name of a matrix run's configurations.
+const matrixManifest = "matrix.json"
+
+// Matrix is one invocation of the runner over the same experiments under
+// several configurations. The run of each configuration is stored in a
+// subdirectory of Dir and gets the ID "<matrix ID>/<subdirectory>".
+type Matrix struct {
+	ID      string    `json:"id"`
+	Start   time.Time `json:"start"`
+	Configs []Config  `json:"configs"`
+
+	// Runs holds the run of each configuration, in Configs order.
+	Runs []*Run `json:"-"`
+	// Dir is the directory holding the matrix. It is not stored.
+	Dir string `json:"-"`
+}
+
+// ExecuteMatrix runs mods under every configuration in turn and stores the
+// runs in a new directory under runsDir. A configuration whose toolchain is
+// older than the go directive of go.work runs in a staged copy of the
+// workspace (see stage). Progress lines are written to log.
+func (w *Workspace) ExecuteMatrix(ctx context.Context, configs []Config, mods []Module, opts Options, runsDir string, log io.Writer) (*Matrix, error) {
+	seen := map[string]bool{}
+	for _, c := range configs {
+		d := c.dirName()
+		if d == "" || seen[d] {
+			return nil, fmt.Errorf("matrix configuration names must be unique and non-empty: %q", c.Name)
+		}
+		seen[d] = true
+	}
+	start := time.Now().UTC()
+	m := &Matrix{Start: start, Configs: configs}
+	var err error
+	if m.ID, m.Dir, err = reserveDir(runsDir, start); err != nil {
+		return nil, err
+	}
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encode matrix: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(m.Dir, matrixManifest), append(b, '\n'), 0o644); err != nil {
+		return nil, fmt.Errorf("write matrix: %w", err)
+	}
+	for _, c := range configs {
+		fmt.Fprintf(log, "### %s\n", c.Name)
+		run, err := w.executeConfig(ctx, c, mods, opts, filepath.Join(m.Dir, c.dirName()), log)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", c.Name, err)
+		}
+		run.ID = m.ID + "/" + c.dirName()
+		if err := run.Save(); err != nil {
+			return nil, err
+		}
+		m.Runs = append(m.Runs, run)
+	}
+	return m, nil
+}
+
+func (w *Workspace) executeConfig(ctx context.Context, c Config, mods []Module, opts Options, dir string, log io.Writer) (*Run, error) {
+	ws := w
+	if v := c.Toolchain.Version; w.needsStaging(v) {
+		tmp, err := os.MkdirTemp("", "lab-stage-")
+		if err != nil {
+			return nil, fmt.Errorf("stage: %w", err)
+		}
+		defer os.RemoveAll(tmp)
+		if ws, mods, err = w.stage(tmp, v, mods); err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(log, "staged with go %s: go.work requires go %s\n", ws.GoVersion, w.GoVersion)
+	}
+	run, err := newRun(ctx, ws, c, opts)
+	if err != nil {
+		return nil, err
+	}
+	run.Config = &c
+	run.Dir = dir
+	if err := ws.execute(ctx, run, c, mods, opts, log); err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+// LoadMatrix reads the matrix stored in dir together with its runs.
+func LoadMatrix(dir string) (*Matrix, error) {
+	b, err := os.ReadFile(filepath.Join(dir, matrixManifest))
+	if err != nil {
+		return nil, fmt.Errorf("read matrix: %w", err)
+	}
+	var m Matrix
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("decode matrix %s: %w", dir, err)
+	}
+	m.Dir = dir
+	for _, c := range m.Configs {
+		run, err := LoadRun(filepath.Join(dir, c.dirName()))
+		if err != nil {
+			return nil, err
+		}
+		m.Runs = append(m.Runs, run)
+	}
+	return &m, nil
+}
+
+// LatestMatrix returns the directory of the newest matrix stored under runsDir.
+func LatestMatrix(runsDir string) (string, error) {
+	entries, err := os.ReadDir(runsDir)
+	if err != nil {
+		return "", fmt.Errorf("list runs: %w", err)
+	}
+	for i := len(entries) - 1; i >= 0; i-- {
+		dir := filepath.Join(runsDir, entries[i].Name())
+		if _, err := os.Stat(filepath.Join(dir, matrixManifest)); err == nil {
+			return dir, nil
+		}
+	}
+	return "", fmt.Errorf("no matrix runs stored in %s", runsDir)
+}
+
+// header writes the table header with one column per configuration.
+func (m *Matrix) header(sb *strings.Builder, first ...string) {
+	cols := slices.Clone(first)
+	align := make([]string, len(first))
+	for i := range align {
+		align[i] = ":---"
+	}
+	for _, r := range m.Runs {
+		name := r.GoVersion
+		if r.Config != nil {
+			name = r.Config.Name
+		}
+		cols = append(cols, name)
+		align = append(align, "---:")
+	}
+	fmt.Fprintf(sb, "| %s |\n| %s |\n", strings.Join(cols, " | "), strings.Join(align, " | "))
+}
+
+// topics returns the topics of every run in first-seen order.
+func (m *Matrix) topics() []string {
+	var topics []string
+	for _, r := range m.Runs {
+		for _, res := range r.Results {
+			if !slices.Contains(topics, res.Topic) {
+				topics = append(topics, res.Topic)
+			}
+		}
+	}
+	return topics
+}
+
+// StatusTable renders whether each experiment passed under each configuration.
+func (m *Matrix) StatusTable() string {
+	var sb strings.Builder
+	m.header(&sb, "Experiment")
+	for _, t := range m.topics() {
+		cells := []string{t}
+		for _, r := range m.Runs {
+			res, ok := r.Result(t)
+			switch {
+			case !ok:
+				cells = append(cells, "")
+			case res.Passed:
+				cells = append(cells, "ok")
+			default:
+				cells = append(cells, "FAIL")
+			}
+		}
+		fmt.Fprintf(&sb, "| %s |\n", strings.Join(cells, " | "))
+	}
+	return sb.String()
+}
+
+// BenchTable renders the median of every benchmark reporting unit under
+// each configuration. Every configuration after the first is compared with
+// the first: the change is shown when significant at alpha, "~" otherwise.
+// A final geomean row gives each configuration's typical change.
+func (m *Matrix) BenchTable(unit string, alpha float64) string {
+	var sb strings.Builder
+	m.header(&sb, "Experiment", "Benchmark")
+	comps := make([][]stats.Comparison, len(m.Runs))
+	for _, t := range m.topics() {
+		var (
+			keys    []string
+			samples = make([]map[string][]float64, len(m.Runs))
+		)
+		for i, r := range m.Runs {
+			res, _ := r.Result(t)
+			samples[i] = benchfmt.Samples(res.Benchmarks, unit)
+			for _, b := range res.Benchmarks {
+				if k := b.Name.Group(); !slices.Contains(keys, k) && len(samples[i][k]) > 0 {
+					keys = append(keys, k)
+				}
+			}
+		}
+		for _, k := range keys {
+			cells := []string{t, k}
+			base := samples[0][k]
+			for i := range m.Runs {
+				xs := samples[i][k]
+				if len(xs) == 0 {
+					cells = append(cells, "")
+					continue
+				}
+				cell := stats.FormatSummary(stats.Summarize(xs, stats.DefaultConfidence))
+				if i > 0 && len(base) > 0 {
+					c := stats.Compare(base, xs, alpha)
+					comps[i] = append(comps[i], c)
+					cell += " (" + c.String() + ")"
+				}
+				cells = append(cells, cell)
+			}
+			fmt.Fprintf(&sb, "| %s |\n", strings.Join(cells, " | "))
+		}
+	}
+	if slices.ContainsFunc(comps, func(cs []stats.Comparison) bool { return len(cs) > 0 }) {
+		cells := []string{"geomean", "", ""}
+		for _, cs := range comps[1:] {
+			cells = append(cells, stats.FormatDelta(stats.GeoMeanDelta(cs)))
+		}
+		fmt.Fprintf(&sb, "| %s |\n", strings.Join(cells, " | "))
+	}
+	return sb.String()
+}
+
+// AllocTable renders the allocation counts logged by measure.AssertAllocs
+// and measure.ObserveAllocs under each configuration. Assertions show the
+// expected count; a count that violates its assertion is marked "✗".
+func (m *Matrix) AllocTable() string {
+	type key struct{ topic, test, name string }
+	var (
+		keys     []key
+		asserted = map[key]string{}
+		values   = make([]map[key]string, len(m.Runs))
+	)
+	for i, r := range m.Runs {
+		values[i] = map[key]string{}
+		for _, res := range r.Results {
+			for _, a := range res.Allocs {
+				k := key{res.Topic, a.Test, a.Name}
+				if _, ok := values[i][k]; ok {
+					continue // -count repetition
+				}
+				v := strconv.FormatFloat(a.Value, 'g', -1, 64)
+				if a.Asserted != nil {
+					asserted[k] = strconv.FormatFloat(*a.Asserted, 'g', -1, 64)
+					if a.Value != *a.Asserted {
+						v += " ✗"
+					}
+				}
+				values[i][k] = v
+				if !slices.Contains(keys, k) {
+					keys = append(keys, k)
+				}
+			}
+		}
+	}
+	var sb strings.Builder
+	m.header(&sb, "Experiment", "Allocation", "Asserted")
+	for _, k := range keys {
+		want, ok := asserted[k]
+		if !ok {
+			want = "observed"
+		}
+		cells := []string{k.topic, k.name, want}
+		for i := range m.Runs {
+			cells = append(cells, values[i][k])
+		}
+		fmt.Fprintf(&sb, "| %s |\n", strings.Join(cells, " | "))
+	}
+	return sb.String()
+}
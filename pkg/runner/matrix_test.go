@@ -0,0 +1,143 @@
+package runner
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestStage(t *testing.T) {
+	root := writeWorkspace(t, "alpha", "beta")
+	w, err := Open(context.Background(), root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !w.needsStaging("go1.22.3") || w.needsStaging("go1.26.0") || w.needsStaging("devel +abc") {
+		t.Error("needsStaging must hold exactly for released toolchains older than go 1.26.0")
+	}
+	mods, err := w.Select([]string{"beta"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	sw, staged, err := w.stage(dir, "go1.22.3", mods)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sw.GoVersion != "1.22" || sw.source() != root {
+		t.Errorf("staged workspace = %+v, want go 1.22 from %s", sw, root)
+	}
+	if len(staged) != 1 || staged[0].Dir != filepath.Join(dir, "experiments", "beta") {
+		t.Fatalf("staged modules = %+v", staged)
+	}
+	for name, want := range map[string]string{
+		"go.work":                    "go 1.22\n\nuse (\n\t./pkg\n\t./experiments/beta\n)\n",
+		"pkg/go.mod":                 "module go-lab/pkg\n\ngo 1.22\n",
+		"experiments/beta/go.mod":    "module go-lab/experiments/beta\n\ngo 1.22\n",
+		"experiments/beta/x_test.go": "",
+	} {
+		b, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if want != "" && string(b) != want {
+			t.Errorf("%s = %q, want %q", name, b, want)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "experiments", "alpha")); err == nil {
+		t.Error("unselected experiment was staged")
+	}
+}
+
+func TestExecuteMatrix(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs the go command")
+	}
+	ctx := context.Background()
+	env, err := Config{}.goEnv(ctx, ".", "GOROOT", "GOVERSION")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var configs []Config
+	for _, spec := range []string{env["GOROOT"], "local"} {
+		tc, err := ResolveToolchain(ctx, spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tc.Version != env["GOVERSION"] {
+			t.Errorf("ResolveToolchain(%s).Version = %q, want %q", spec, tc.Version, env["GOVERSION"])
+		}
+		configs = append(configs, Config{Name: "go " + spec, Toolchain: tc})
+	}
+	noinline, err := ParseConfig("-gcflags=-l")
+	if err != nil {
+		t.Fatal(err)
+	}
+	configs = append(configs, noinline)
+	if _, err := ResolveToolchain(ctx, "go1.0.1"); err == nil {
+		t.Error("ResolveToolchain of a toolchain that is not installed succeeded")
+	}
+
+	root := writeWorkspace(t, "alpha")
+	w, err := Open(ctx, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := DefaultOptions()
+	opts.Benchtime = "1x"
+	runsDir := filepath.Join(root, RunsDir)
+	if _, err := w.ExecuteMatrix(ctx, configs, w.Experiments(), opts, runsDir, io.Discard); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LatestRun(runsDir); err == nil {
+		t.Error("LatestRun found a matrix")
+	}
+	dir, err := LatestMatrix(runsDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := LoadMatrix(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Runs) != 3 || m.Runs[1].ID != m.ID+"/go_local" || m.Runs[1].Config.Name != "go local" {
+		t.Fatalf("matrix runs = %+v", m.Runs)
+	}
+	if res := m.Runs[2].Results[0]; !slices.Contains(res.Command, "-gcflags=-l") {
+		t.Errorf("command %q lacks the configuration's flags", res.Command)
+	}
+	if got, want := m.StatusTable(), "| alpha | ok | ok | ok |\n"; !strings.HasSuffix(got, want) {
+		t.Errorf("StatusTable:\n%s\nwant last row %q", got, want)
+	}
+	if got := m.BenchTable("ns/op", 0.05); !strings.Contains(got, "| alpha | Nop") {
+		t.Errorf("BenchTable lacks Nop:\n%s", got)
+	}
+}
+
+func TestAllocTable(t *testing.T) {
+	zero, one := 0.0, 1.0
+	run := func(name string, a, b float64) *Run {
+		return &Run{Config: &Config{Name: name}, Results: []Result{{
+			Topic: "closure-capture",
+			Allocs: []Alloc{
+				{Test: "TestAllocations/PatternA", Name: "PatternA", Value: a, Asserted: &zero},
+				{Test: "TestAllocations/PatternA", Name: "PatternA", Value: a, Asserted: &zero},
+				{Test: "TestAllocations/PatternB", Name: "PatternB", Value: b},
+			},
+		}}}
+	}
+	m := &Matrix{Runs: []*Run{run("go1.22", 0, one), run("go1.26", 1, 0)}}
+	want := `| Experiment | Allocation | Asserted | go1.22 | go1.26 |
+| :--- | :--- | :--- | ---: | ---: |
+| closure-capture | PatternA | 0 | 0 | 1 ✗ |
+| closure-capture | PatternB | observed | 1 | 0 |
+`
+	if got := m.AllocTable(); got != want {
+		t.Errorf("AllocTable:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,96 @@
+package runner
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"go-lab/pkg/benchfmt"
+	"go-lab/pkg/stats"
+)
+
+// FragileCV is the robust coefficient of variation of ns/op above which a
+// benchmark is too noisy for differences of a few percent to be trusted.
+const FragileCV = 0.05
+
+// Noise describes how repeatable the benchmarks of a run were across their
+// -count samples.
+type Noise struct {
+	// Score is the median robust coefficient of variation of ns/op over
+	// every benchmark with at least three samples; 0 when there are none.
+	Score      float64      `json:"score"`
+	Benchmarks []BenchNoise `json:"benchmarks,omitempty"`
+	// Warnings lists host settings that add noise (see sysinfo.Info.Warnings).
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// BenchNoise is the spread of one benchmark's ns/op samples.
+type BenchNoise struct {
+	Topic     string    `json:"topic"`
+	Benchmark string    `json:"benchmark"` // benchfmt group key
+	Samples   int       `json:"samples"`
+	CV        float64   `json:"cv"`                 // stats.RobustCV
+	Outliers  []float64 `json:"outliers,omitempty"` // samples outside the Tukey fences
+}
+
+// Fragile reports whether conclusions drawn from b are at risk: its spread
+// exceeds FragileCV or a sample is an outlier.
+func (b BenchNoise) Fragile() bool {
+	return b.CV > FragileCV || len(b.Outliers) > 0
+}
+
+// measureNoise computes the noise of r from its results and host.
+func (r *Run) measureNoise() {
+	n := &Noise{Warnings: r.System.Warnings()}
+	var cvs []float64
+	for _, res := range r.Results {
+		samples := benchfmt.Samples(res.Benchmarks, "ns/op")
+		var keys []string
+		for _, b := range res.Benchmarks {
+			if k := b.Name.Group(); !slices.Contains(keys, k) && len(samples[k]) >= 3 {
+				keys = append(keys, k)
+			}
+		}
+		for _, k := range keys {
+			xs := samples[k]
+			bn := BenchNoise{Topic: res.Topic, Benchmark: k, Samples: len(xs), CV: stats.RobustCV(xs)}
+			for _, i := range stats.Outliers(xs) {
+				bn.Outliers = append(bn.Outliers, xs[i])
+			}
+			n.Benchmarks = append(n.Benchmarks, bn)
+			cvs = append(cvs, bn.CV)
+		}
+	}
+	if len(cvs) > 0 {
+		n.Score = stats.Median(cvs)
+	}
+	r.Noise = n
+}
+
+// String summarizes n in one line, followed by one line per fragile
+// benchmark and per warning.
+func (n *Noise) String() string {
+	var sb strings.Builder
+	if len(n.Benchmarks) == 0 {
+		sb.WriteString("noise: not scored (run with -count 3 or more)\n")
+	} else {
+		outliers := 0
+		for _, b := range n.Benchmarks {
+			outliers += len(b.Outliers)
+		}
+		fmt.Fprintf(&sb, "noise: score %.1f%% over %d benchmarks, %d outliers\n", n.Score*100, len(n.Benchmarks), outliers)
+	}
+	for _, b := range n.Benchmarks {
+		if b.Fragile() {
+			fmt.Fprintf(&sb, "  fragile: %s %s: ±%.1f%% over %d samples", b.Topic, b.Benchmark, b.CV*100, b.Samples)
+			if len(b.Outliers) > 0 {
+				fmt.Fprintf(&sb, ", outliers %v", b.Outliers)
+			}
+			sb.WriteByte('\n')
+		}
+	}
+	for _, w := range n.Warnings {
+		fmt.Fprintf(&sb, "  warning: %s\n", w)
+	}
+	return sb.String()
+}
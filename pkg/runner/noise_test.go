@@ -0,0 +1,53 @@
+package runner
+
+import (
+	"strings"
+	"testing"
+
+	"go-lab/pkg/benchfmt"
+	"go-lab/pkg/sysinfo"
+)
+
+func TestMeasureNoise(t *testing.T) {
+	bench := func(name string, ns float64) benchfmt.Result {
+		return benchfmt.Result{Name: benchfmt.ParseName(name), Values: []benchfmt.Value{{Value: ns, Unit: "ns/op"}}}
+	}
+	var results []benchfmt.Result
+	for _, ns := range []float64{100, 101, 99, 100, 100, 160} {
+		results = append(results, bench("BenchmarkSpawnBuffered-8", ns))
+	}
+	for _, ns := range []float64{100, 130, 80} {
+		results = append(results, bench("BenchmarkSpawnUnbuffered-8", ns))
+	}
+	results = append(results, bench("BenchmarkOnce-8", 5))
+	run := &Run{
+		System:  sysinfo.Info{Governors: []string{"powersave"}},
+		Results: []Result{{Topic: "goroutine-cost", Benchmarks: results}},
+	}
+	run.measureNoise()
+	n := run.Noise
+	if len(n.Benchmarks) != 2 {
+		t.Fatalf("Benchmarks = %+v, want the two with 3+ samples", n.Benchmarks)
+	}
+	buffered, unbuffered := n.Benchmarks[0], n.Benchmarks[1]
+	if buffered.Samples != 6 || len(buffered.Outliers) != 1 || buffered.Outliers[0] != 160 || buffered.CV > FragileCV {
+		t.Errorf("SpawnBuffered noise = %+v, want one outlier (160) and a low spread", buffered)
+	}
+	if !unbuffered.Fragile() || unbuffered.Outliers != nil {
+		t.Errorf("SpawnUnbuffered noise = %+v, want fragile without outliers", unbuffered)
+	}
+	if n.Score != (buffered.CV+unbuffered.CV)/2 {
+		t.Errorf("Score = %v, want the median CV", n.Score)
+	}
+	s := n.String()
+	for _, want := range []string{
+		"over 2 benchmarks, 1 outliers",
+		"fragile: goroutine-cost SpawnBuffered-8",
+		"fragile: goroutine-cost SpawnUnbuffered-8: ±29.7% over 3 samples\n",
+		"warning: cpufreq governor \"powersave\"",
+	} {
+		if !strings.Contains(s, want) {
+			t.Errorf("String() lacks %q:\n%s", want, s)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package runner
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-lab/pkg/benchfmt"
+)
+
+// TestResult is the final status line of one test or subtest.
+type TestResult struct {
+	Name    string        `json:"name"`
+	Status  string        `json:"status"` // PASS, FAIL or SKIP
+	Elapsed time.Duration `json:"elapsed"`
+}
+
+// Alloc is an allocation count logged by measure.AssertAllocs or
+// measure.ObserveAllocs.
+type Alloc struct {
+	Test     string   `json:"test"` // enclosing (sub)test
+	Name     string   `json:"name"`
+	Value    float64  `json:"value"`              // allocs/run
+	Asserted *float64 `json:"asserted,omitempty"` // expected value, for assertions
+}
+
+var (
+	runLine   = regexp.MustCompile(`^=== (?:RUN|CONT)\s+(\S+)`)
+	allocLine = regexp.MustCompile(`^\s+\S+\.go:\d+: (.+): allocs/run = (\S+)(?: \(asserted (\S+)\))?$`)
+)
+
+// parseOutput extracts test status lines, allocation counts and benchmark
+// result lines from go test -v output.
+func parseOutput(out []byte) ([]TestResult, []Alloc, []benchfmt.Result, error) {
+	var (
+		tests  []TestResult
+		allocs []Alloc
+		test   string
+	)
+	sc := bufio.NewScanner(bytes.NewReader(out))
+	sc.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	for sc.Scan() {
+		line := sc.Text()
+		if tr, ok := parseTestLine(line); ok {
+			tests = append(tests, tr)
+			continue
+		}
+		if m := runLine.FindStringSubmatch(line); m != nil {
+			test = m[1]
+			continue
+		}
+		if a, ok := parseAllocLine(line); ok {
+			a.Test = test
+			allocs = append(allocs, a)
+		}
+	}
+	benchs, err := benchfmt.Parse(bytes.NewReader(out))
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("parse benchmarks: %w", err)
+	}
+	return tests, allocs, benchs, nil
+}
+
+// parseAllocLine parses "    closure_test.go:34: PatternA: allocs/run = 0
+// (asserted 0)"; the assertion suffix is optional.
+func parseAllocLine(line string) (Alloc, bool) {
+	m := allocLine.FindStringSubmatch(line)
+	if m == nil {
+		return Alloc{}, false
+	}
+	v, err := strconv.ParseFloat(m[2], 64)
+	if err != nil {
+		return Alloc{}, false
+	}
+	a := Alloc{Name: m[1], Value: v}
+	if m[3] != "" {
+		w, err := strconv.ParseFloat(m[3], 64)
+		if err != nil {
+			return Alloc{}, false
+		}
+		a.Asserted = &w
+	}
+	return a, true
+}
+
+// parseTestLine parses "--- PASS: TestName (0.00s)", including indented
+// subtest lines.
+func parseTestLine(line string) (TestResult, bool) {
+	line = strings.TrimSpace(line)
+	rest, ok := strings.CutPrefix(line, "--- ")
+	if !ok {
+		return TestResult{}, false
+	}
+	status, rest, ok := strings.Cut(rest, ": ")
+	if !ok || (status != "PASS" && status != "FAIL" && status != "SKIP") {
+		return TestResult{}, false
+	}
+	name, elapsed, _ := strings.Cut(rest, " ")
+	tr := TestResult{Name: name, Status: status}
+	elapsed = strings.TrimSuffix(strings.TrimPrefix(elapsed, "("), ")")
+	if d, err := time.ParseDuration(elapsed); err == nil {
+		tr.Elapsed = d
+	}
+	return tr, true
+}
@@ -0,0 +1,86 @@
+package runner
+
+import (
+	"testing"
+	"time"
+)
+
+const sampleOutput = `=== RUN   TestSize
+=== RUN   TestSize/Unpadded
+    padding_test.go:25: unsafe.Sizeof(Unpadded{}) = 24 B
+--- PASS: TestSize (0.00s)
+    --- PASS: TestSize/Unpadded (0.00s)
+=== RUN   TestAllocations
+=== RUN   TestAllocations/PatternA
+    closure_test.go:34: PatternA: allocs/run = 0 (asserted 0)
+=== RUN   TestAllocations/PatternB
+    closure_test.go:39: PatternB (reference capture, non-escaping): allocs/run = 1
+--- PASS: TestAllocations (0.00s)
+--- FAIL: TestBroken (0.12s)
+goos: linux
+goarch: amd64
+pkg: go-lab/experiments/string-concat
+BenchmarkConcatPlus/N=64-8   	  129157	      9105 ns/op	   17128 B/op	      63 allocs/op
+BenchmarkSpawnBuffered
+BenchmarkSpawnBuffered-8     	 3000000	       412.5 ns/op
+PASS
+ok  	go-lab/experiments/string-concat	1.2s
+`
+
+func TestParseOutput(t *testing.T) {
+	tests, allocs, benchs, err := parseOutput([]byte(sampleOutput))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantTests := []TestResult{
+		{"TestSize", "PASS", 0},
+		{"TestSize/Unpadded", "PASS", 0},
+		{"TestAllocations", "PASS", 0},
+		{"TestBroken", "FAIL", 120 * time.Millisecond},
+	}
+	if len(tests) != len(wantTests) {
+		t.Fatalf("got %d tests, want %d: %+v", len(tests), len(wantTests), tests)
+	}
+	for i, want := range wantTests {
+		if tests[i] != want {
+			t.Errorf("tests[%d] = %+v, want %+v", i, tests[i], want)
+		}
+	}
+
+	if len(allocs) != 2 {
+		t.Fatalf("got %d allocs, want 2: %+v", len(allocs), allocs)
+	}
+	if a := allocs[0]; a.Test != "TestAllocations/PatternA" || a.Name != "PatternA" || a.Value != 0 || a.Asserted == nil || *a.Asserted != 0 {
+		t.Errorf("allocs[0] = %+v", a)
+	}
+	if a := allocs[1]; a.Name != "PatternB (reference capture, non-escaping)" || a.Value != 1 || a.Asserted != nil {
+		t.Errorf("allocs[1] = %+v", a)
+	}
+
+	if len(benchs) != 2 {
+		t.Fatalf("got %d benchmarks, want 2: %+v", len(benchs), benchs)
+	}
+	b := benchs[0]
+	if b.FullName != "BenchmarkConcatPlus/N=64-8" || b.Iterations != 129157 {
+		t.Errorf("benchmark = %s x%d", b.FullName, b.Iterations)
+	}
+	if got, _ := benchs[1].Get("ns/op"); got != 412.5 {
+		t.Errorf("ns/op = %v, want 412.5", got)
+	}
+}
+
+func TestOptionsArgs(t *testing.T) {
+	o := Options{Run: "^$", Bench: "Spawn", Count: 5, CPU: "1,4", Benchtime: "100x"}
+	got := o.Args()
+	want := []string{"test", "-v", "-run", "^$", "-bench", "Spawn", "-benchmem",
+		"-count", "5", "-cpu", "1,4", "-benchtime", "100x", "./..."}
+	if len(got) != len(want) {
+		t.Fatalf("Args() = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Args() = %q, want %q", got, want)
+		}
+	}
+}
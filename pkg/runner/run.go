@@ -0,0 +1,188 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"time"
+
+	"go-lab/pkg/benchfmt"
+	"go-lab/pkg/measure"
+)
+
+// Options are the go test flags applied to every experiment of a run.
+type Options struct {
+	Run       string `json:"run"`                 // -run regexp
+	Bench     string `json:"bench"`               // -bench regexp; empty disables benchmarks
+	Count     int    `json:"count"`               // -count
+	CPU       string `json:"cpu,omitempty"`       // -cpu list, e.g. "1,4"
+	Benchtime string `json:"benchtime,omitempty"` // -benchtime, e.g. "1s" or "100x"
+
+	// Warmup is the -benchtime of a discarded benchmark pass run before
+	// the measured one, e.g. "100ms"; empty skips it.
+	Warmup string `json:"warmup,omitempty"`
+	// Pin lists the CPUs the go command and the test binaries are pinned
+	// to (see measure.Pin) while the run executes; the previous affinity
+	// is restored afterwards. Empty leaves the affinity alone.
+	Pin []int `json:"pin,omitempty"`
+}
+
+// DefaultOptions runs every test and benchmark once.
+func DefaultOptions() Options {
+	return Options{Run: ".", Bench: ".", Count: 1}
+}
+
+// Args returns the go command line (without "go") for opts.
+// Warmup and Pin are applied by the runner, not passed to go test.
+// Tests run with -v so that t.Logf observations are kept in the raw output.
+func (o Options) Args() []string {
+	args := []string{"test", "-v", "-run", o.Run}
+	if o.Bench != "" {
+		args = append(args, "-bench", o.Bench, "-benchmem")
+	}
+	if o.Count > 0 {
+		args = append(args, "-count", strconv.Itoa(o.Count))
+	}
+	if o.CPU != "" {
+		args = append(args, "-cpu", o.CPU)
+	}
+	if o.Benchtime != "" {
+		args = append(args, "-benchtime", o.Benchtime)
+	}
+	return append(args, "./...")
+}
+
+// Result is the outcome of running one experiment module.
+type Result struct {
+	Topic      string            `json:"topic"`
+	Command    []string          `json:"command"`
+	Start      time.Time         `json:"start"`
+	Duration   time.Duration     `json:"duration"`
+	Passed     bool              `json:"passed"`
+	Error      string            `json:"error,omitempty"`
+	Output     string            `json:"output"` // raw output file, relative to the run directory
+	Tests      []TestResult      `json:"tests,omitempty"`
+	Allocs     []Alloc           `json:"allocs,omitempty"`
+	Benchmarks []benchfmt.Result `json:"benchmarks,omitempty"`
+}
+
+// Test runs go test for m and returns the parsed result and raw output.
+// A failing test is reported through Result.Passed, not as an error; the
+// error is non-nil only when the go command could not be run at all.
+func Test(ctx context.Context, m Module, opts Options) (Result, []byte, error) {
+	return Config{}.test(ctx, m, opts)
+}
+
+// warmup runs the benchmarks of m for opts.Warmup and discards the
+// results, so that the measured pass starts with a built test binary, warm
+// caches and a settled clock.
+func (c Config) warmup(ctx context.Context, m Module, opts Options) error {
+	args := []string{"test", "-run", "^$", "-bench", opts.Bench, "-benchtime", opts.Warmup}
+	if opts.CPU != "" {
+		args = append(args, "-cpu", opts.CPU)
+	}
+	args = append(slices.Insert(args, 1, c.Flags...), "./...")
+	if out, err := c.command(ctx, m.Dir, args...).CombinedOutput(); err != nil {
+		var exit *exec.ExitError
+		if errors.As(err, &exit) {
+			return nil // the measured pass reports the failure
+		}
+		return fmt.Errorf("%s: warmup: %w: %s", m.Topic, err, out)
+	}
+	return nil
+}
+
+func (c Config) test(ctx context.Context, m Module, opts Options) (Result, []byte, error) {
+	args := slices.Insert(opts.Args(), 1, c.Flags...)
+	cmd := c.command(ctx, m.Dir, args...)
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+
+	res := Result{Topic: m.Topic, Command: append([]string{"go"}, args...), Start: time.Now()}
+	err := cmd.Run()
+	res.Duration = time.Since(res.Start)
+	var exit *exec.ExitError
+	switch {
+	case err == nil:
+		res.Passed = true
+	case errors.As(err, &exit):
+		res.Error = err.Error()
+	default:
+		return res, out.Bytes(), fmt.Errorf("%s: %w", m.Topic, err)
+	}
+	res.Tests, res.Allocs, res.Benchmarks, err = parseOutput(out.Bytes())
+	return res, out.Bytes(), err
+}
+
+// Execute runs every module in mods, writing "<topic>.txt" raw output files
+// and a run.json manifest into a new directory under runsDir.
+// Progress lines are written to log.
+func (w *Workspace) Execute(ctx context.Context, mods []Module, opts Options, runsDir string, log io.Writer) (*Run, error) {
+	run, err := newRun(ctx, w, Config{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	if run.ID, run.Dir, err = reserveDir(runsDir, run.Start); err != nil {
+		return nil, err
+	}
+	if err := w.execute(ctx, run, Config{}, mods, opts, log); err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+// execute runs mods with c into run, which is saved in run.Dir.
+func (w *Workspace) execute(ctx context.Context, run *Run, c Config, mods []Module, opts Options, log io.Writer) (err error) {
+	if err := os.MkdirAll(run.Dir, 0o755); err != nil {
+		return fmt.Errorf("create run directory: %w", err)
+	}
+	if len(opts.Pin) > 0 {
+		// Pin applies to the lab process itself; restore its affinity so
+		// that later builds and runs are not confined to opts.Pin.
+		prev, err := measure.Affinity()
+		if err != nil {
+			return err
+		}
+		if err := measure.Pin(opts.Pin); err != nil {
+			return err
+		}
+		defer func() {
+			if perr := measure.Pin(prev); perr != nil && err == nil {
+				err = fmt.Errorf("restore CPU affinity: %w", perr)
+			}
+		}()
+	}
+	for _, m := range mods {
+		fmt.Fprintf(log, "=== %s\n", m.Topic)
+		if opts.Warmup != "" && opts.Bench != "" {
+			if err := c.warmup(ctx, m, opts); err != nil {
+				return err
+			}
+		}
+		res, raw, err := c.test(ctx, m, opts)
+		res.Output = m.Topic + ".txt"
+		if werr := os.WriteFile(filepath.Join(run.Dir, res.Output), raw, 0o644); werr != nil {
+			return fmt.Errorf("write output: %w", werr)
+		}
+		if err != nil {
+			return err
+		}
+		status := "ok"
+		if !res.Passed {
+			status = "FAIL"
+		}
+		fmt.Fprintf(log, "%s\t%s\t%d tests, %d benchmarks\t%s\n",
+			status, m.Topic, len(res.Tests), len(res.Benchmarks), res.Duration.Round(time.Millisecond))
+		run.Results = append(run.Results, res)
+	}
+	run.measureNoise()
+	return run.Save()
+}
@@ -0,0 +1,177 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"go-lab/pkg/sysinfo"
+)
+
+// RunsDir is the workspace-relative default directory for stored runs.
+const RunsDir = ".lab/runs"
+
+// manifest is the file name of a run's parsed results.
+const manifest = "run.json"
+
+// Run is one invocation of the runner over a set of experiments.
+type Run struct {
+	ID        string       `json:"id"` // UTC timestamp, e.g. 20261017T120000Z or 20261017T120000Z-02
+	Start     time.Time    `json:"start"`
+	GoVersion string       `json:"go_version"`
+	GOOS      string       `json:"goos"`
+	GOARCH    string       `json:"goarch"`
+	GOAMD64   string       `json:"goamd64,omitempty"` // microarchitecture level on amd64, e.g. v1
+	Commit    string       `json:"commit,omitempty"`  // HEAD of the workspace repository
+	Dirty     bool         `json:"dirty,omitempty"`   // uncommitted changes at run time
+	System    sysinfo.Info `json:"system"`
+	Config    *Config      `json:"config,omitempty"` // set for matrix cells
+	Options   Options      `json:"options"`
+	Results   []Result     `json:"results"`
+	Noise     *Noise       `json:"noise,omitempty"`
+
+	// Dir is the directory holding the run's files. It is not stored.
+	Dir string `json:"-"`
+}
+
+func newRun(ctx context.Context, w *Workspace, c Config, opts Options) (*Run, error) {
+	// Run in the workspace root so that go.work toolchain selection applies.
+	env, err := c.goEnv(ctx, w.Root, "GOVERSION", "GOOS", "GOARCH", "GOAMD64")
+	if err != nil {
+		return nil, err
+	}
+	start := time.Now().UTC()
+	run := &Run{
+		ID:        newID(start),
+		Start:     start,
+		GoVersion: env["GOVERSION"],
+		GOOS:      env["GOOS"],
+		GOARCH:    env["GOARCH"],
+		GOAMD64:   env["GOAMD64"],
+		System:    sysinfo.Collect(),
+		Options:   opts,
+	}
+	run.Commit, run.Dirty = gitHead(ctx, w.source())
+	return run, nil
+}
+
+func newID(t time.Time) string { return t.Format("20060102T150405Z") }
+
+// reserveDir creates a new directory under runsDir for a run or matrix
+// started at start and returns its ID. Runs started within the same second
+// get a "-02", "-03", ... suffix, so no run overwrites another's files.
+func reserveDir(runsDir string, start time.Time) (id, dir string, err error) {
+	if err := os.MkdirAll(runsDir, 0o755); err != nil {
+		return "", "", fmt.Errorf("create run directory: %w", err)
+	}
+	base := newID(start)
+	for n := 1; ; n++ {
+		id = base
+		if n > 1 {
+			id = fmt.Sprintf("%s-%02d", base, n)
+		}
+		dir = filepath.Join(runsDir, id)
+		err := os.Mkdir(dir, 0o755)
+		if err == nil {
+			return id, dir, nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return "", "", fmt.Errorf("create run directory: %w", err)
+		}
+	}
+}
+
+// gitHead returns the commit checked out in dir and whether the work tree
+// has uncommitted changes. Outside a git repository the commit is empty.
+func gitHead(ctx context.Context, dir string) (string, bool) {
+	cmd := exec.CommandContext(ctx, "git", "rev-parse", "HEAD")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return "", false
+	}
+	cmd = exec.CommandContext(ctx, "git", "status", "--porcelain", "--untracked-files=no")
+	cmd.Dir = dir
+	status, err := cmd.Output()
+	return strings.TrimSpace(string(out)), err == nil && len(bytes.TrimSpace(status)) > 0
+}
+
+// Passed reports whether every experiment of the run passed.
+func (r *Run) Passed() bool {
+	for _, res := range r.Results {
+		if !res.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+// Result returns the result for topic.
+func (r *Run) Result(topic string) (Result, bool) {
+	i := slices.IndexFunc(r.Results, func(res Result) bool { return res.Topic == topic })
+	if i < 0 {
+		return Result{}, false
+	}
+	return r.Results[i], true
+}
+
+// RawOutput reads the raw go test output stored for res.
+func (r *Run) RawOutput(res Result) ([]byte, error) {
+	b, err := os.ReadFile(filepath.Join(r.Dir, res.Output))
+	if err != nil {
+		return nil, fmt.Errorf("read raw output: %w", err)
+	}
+	return b, nil
+}
+
+// Save writes the run manifest into r.Dir.
+func (r *Run) Save() error {
+	b, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode run: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(r.Dir, manifest), append(b, '\n'), 0o644); err != nil {
+		return fmt.Errorf("write run: %w", err)
+	}
+	return nil
+}
+
+// LoadRun reads the run stored in dir.
+func LoadRun(dir string) (*Run, error) {
+	b, err := os.ReadFile(filepath.Join(dir, manifest))
+	if err != nil {
+		return nil, fmt.Errorf("read run: %w", err)
+	}
+	var r Run
+	if err := json.Unmarshal(b, &r); err != nil {
+		return nil, fmt.Errorf("decode run %s: %w", dir, err)
+	}
+	r.Dir = dir
+	return &r, nil
+}
+
+// LatestRun returns the directory of the newest run stored under runsDir.
+func LatestRun(runsDir string) (string, error) {
+	entries, err := os.ReadDir(runsDir)
+	if err != nil {
+		return "", fmt.Errorf("list runs: %w", err)
+	}
+	// IDs are timestamps with an optional zero-padded same-second suffix,
+	// so lexical order is chronological; ReadDir sorts.
+	for i := len(entries) - 1; i >= 0; i-- {
+		dir := filepath.Join(runsDir, entries[i].Name())
+		if _, err := os.Stat(filepath.Join(dir, manifest)); err == nil {
+			return dir, nil
+		}
+	}
+	return "", errors.New("no runs stored in " + runsDir)
+}
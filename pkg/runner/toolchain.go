@@ -0,0 +1,161 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go/version"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Toolchain is a locally installed Go SDK experiments can be run with.
+type Toolchain struct {
+	Spec    string   `json:"spec"`              // as given: SDK directory, go binary or GOTOOLCHAIN value
+	Go      string   `json:"go"`                // go command to run
+	Env     []string `json:"env,omitempty"`     // environment selecting the toolchain
+	Version string   `json:"version,omitempty"` // go env GOVERSION, e.g. go1.23.4
+}
+
+// ResolveToolchain resolves spec to a toolchain and reports its version.
+// A spec naming an existing path is an SDK directory (GOROOT), its bin
+// directory or a go binary, run with GOTOOLCHAIN=local; anything else is a
+// GOTOOLCHAIN value such as "go1.23.4", which must already be installed in
+// the module cache: resolving never downloads a toolchain.
+func ResolveToolchain(ctx context.Context, spec string) (Toolchain, error) {
+	tc := Toolchain{Spec: spec, Go: "go", Env: []string{"GOTOOLCHAIN=" + spec}}
+	if fi, err := os.Stat(spec); err == nil {
+		bin, err := goBinary(spec, fi)
+		if err != nil {
+			return Toolchain{}, err
+		}
+		root := filepath.Dir(filepath.Dir(bin))
+		tc.Go = bin
+		tc.Env = []string{
+			"GOTOOLCHAIN=local",
+			"GOROOT=" + root,
+			// Tests that exec "go" themselves (escape, disasm) must get the
+			// same toolchain.
+			"PATH=" + filepath.Dir(bin) + string(filepath.ListSeparator) + os.Getenv("PATH"),
+		}
+	}
+	cmd := tc.command(ctx, os.TempDir(), "env", "GOVERSION")
+	cmd.Env = append(cmd.Env, "GOWORK=off", "GOPROXY=off")
+	out, err := cmd.Output()
+	if err != nil {
+		var exit *exec.ExitError
+		if errors.As(err, &exit) {
+			return Toolchain{}, fmt.Errorf("toolchain %s: %s", spec, strings.TrimSpace(string(exit.Stderr)))
+		}
+		return Toolchain{}, fmt.Errorf("toolchain %s: %w", spec, err)
+	}
+	tc.Version = strings.TrimSpace(string(out))
+	return tc, nil
+}
+
+// goBinary finds the go command of the SDK at path.
+func goBinary(path string, fi os.FileInfo) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("resolve %s: %w", path, err)
+	}
+	if !fi.IsDir() {
+		return abs, nil
+	}
+	for _, bin := range []string{filepath.Join(abs, "bin", "go"), filepath.Join(abs, "go")} {
+		if fi, err := os.Stat(bin); err == nil && !fi.IsDir() {
+			return bin, nil
+		}
+	}
+	return "", fmt.Errorf("no go command in %s", path)
+}
+
+// command returns the go command of tc running args in dir.
+// The zero Toolchain is the go command found in PATH.
+func (tc Toolchain) command(ctx context.Context, dir string, args ...string) *exec.Cmd {
+	name := tc.Go
+	if name == "" {
+		name = "go"
+	}
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = dir
+	if len(tc.Env) > 0 {
+		cmd.Env = append(os.Environ(), tc.Env...)
+	}
+	return cmd
+}
+
+// needsStaging reports whether toolchain version v is older than the go
+// directive of w and so refuses to load the workspace as it is.
+func (w *Workspace) needsStaging(v string) bool {
+	return version.IsValid(v) && version.Compare(v, "go"+w.GoVersion) < 0
+}
+
+var (
+	goLine        = regexp.MustCompile(`(?m)^go \S+$`)
+	toolchainLine = regexp.MustCompile(`(?m)^toolchain \S+\n`)
+)
+
+// stage copies the shared (non-experiment, non-cmd) modules of w and mods
+// into dir, lowering every go directive to the language version of
+// toolchain version v, and returns the copy as a workspace. Lowering the
+// go directive changes language semantics as well (e.g. per-iteration loop
+// variables before go1.22), exactly as a module written for v would see.
+func (w *Workspace) stage(dir, v string, mods []Module) (*Workspace, []Module, error) {
+	lang := strings.TrimPrefix(version.Lang(v), "go")
+	sw := &Workspace{Root: dir, GoVersion: lang, src: w.source()}
+	var staged []Module
+	copyMod := func(m Module) (Module, error) {
+		rel, err := filepath.Rel(w.Root, m.Dir)
+		if err != nil {
+			return Module{}, fmt.Errorf("stage %s: %w", m.Topic, err)
+		}
+		dst := filepath.Join(dir, rel)
+		if err := os.CopyFS(dst, os.DirFS(m.Dir)); err != nil {
+			return Module{}, fmt.Errorf("stage %s: %w", m.Topic, err)
+		}
+		gomod := filepath.Join(dst, "go.mod")
+		b, err := os.ReadFile(gomod)
+		if err != nil {
+			return Module{}, fmt.Errorf("stage %s: %w", m.Topic, err)
+		}
+		b = toolchainLine.ReplaceAll(goLine.ReplaceAll(b, []byte("go "+lang)), nil)
+		if err := os.WriteFile(gomod, b, 0o644); err != nil {
+			return Module{}, fmt.Errorf("stage %s: %w", m.Topic, err)
+		}
+		sm := Module{Topic: m.Topic, Dir: dst}
+		sw.Modules = append(sw.Modules, sm)
+		return sm, nil
+	}
+	for _, m := range w.Modules {
+		rel, _ := filepath.Rel(w.Root, m.Dir)
+		top, _, _ := strings.Cut(filepath.ToSlash(rel), "/")
+		if top == ExperimentsDir || top == "cmd" {
+			continue
+		}
+		if _, err := copyMod(m); err != nil {
+			return nil, nil, err
+		}
+	}
+	for _, m := range mods {
+		sm, err := copyMod(m)
+		if err != nil {
+			return nil, nil, err
+		}
+		staged = append(staged, sm)
+	}
+	var work strings.Builder
+	fmt.Fprintf(&work, "go %s\n\nuse (\n", lang)
+	for _, m := range sw.Modules {
+		rel, _ := filepath.Rel(dir, m.Dir)
+		fmt.Fprintf(&work, "\t./%s\n", filepath.ToSlash(rel))
+	}
+	work.WriteString(")\n")
+	if err := os.WriteFile(filepath.Join(dir, "go.work"), []byte(work.String()), 0o644); err != nil {
+		return nil, nil, fmt.Errorf("stage go.work: %w", err)
+	}
+	return sw, staged, nil
+}
@@ -0,0 +1,122 @@
+// Package runner discovers the experiment modules of the go-lab workspace,
+// runs their tests and benchmarks, and stores the raw output together with
+// the parsed results of every run.
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// ExperimentsDir is the workspace-relative directory holding experiment modules.
+const ExperimentsDir = "experiments"
+
+// Workspace is the go.work workspace containing the lab.
+type Workspace struct {
+	Root      string   // directory holding go.work
+	GoVersion string   // go directive of go.work
+	Modules   []Module // modules listed in use directives, in go.work order
+
+	src string // root of the workspace a staged copy was made from
+}
+
+// source returns the root of the original workspace, also for staged copies.
+func (w *Workspace) source() string {
+	if w.src != "" {
+		return w.src
+	}
+	return w.Root
+}
+
+// Module is one module of the workspace.
+type Module struct {
+	Topic string // directory name, e.g. "struct-padding"
+	Dir   string // absolute path
+}
+
+// FindRoot walks up from dir to the nearest directory containing go.work.
+func FindRoot(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("resolve %s: %w", dir, err)
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "go.work")); err == nil {
+			return dir, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", errors.New("go.work not found")
+		}
+		dir = parent
+	}
+}
+
+// Open loads the workspace enclosing dir.
+func Open(ctx context.Context, dir string) (*Workspace, error) {
+	root, err := FindRoot(dir)
+	if err != nil {
+		return nil, err
+	}
+	out, err := exec.CommandContext(ctx, "go", "work", "edit", "-json", filepath.Join(root, "go.work")).Output()
+	if err != nil {
+		return nil, fmt.Errorf("go work edit -json: %w", err)
+	}
+	var wf struct {
+		Go  string
+		Use []struct{ DiskPath string }
+	}
+	if err := json.Unmarshal(out, &wf); err != nil {
+		return nil, fmt.Errorf("parse go.work: %w", err)
+	}
+	w := &Workspace{Root: root, GoVersion: wf.Go}
+	for _, u := range wf.Use {
+		dir := filepath.Join(root, filepath.FromSlash(u.DiskPath))
+		w.Modules = append(w.Modules, Module{Topic: filepath.Base(dir), Dir: dir})
+	}
+	return w, nil
+}
+
+// Experiments returns the modules located under ExperimentsDir.
+func (w *Workspace) Experiments() []Module {
+	prefix := filepath.Join(w.Root, ExperimentsDir) + string(filepath.Separator)
+	var mods []Module
+	for _, m := range w.Modules {
+		if strings.HasPrefix(m.Dir, prefix) {
+			mods = append(mods, m)
+		}
+	}
+	return mods
+}
+
+// Select returns the experiments named by topics, or every experiment when
+// topics is empty. Unknown topics are reported together in one error.
+func (w *Workspace) Select(topics []string) ([]Module, error) {
+	all := w.Experiments()
+	if len(topics) == 0 {
+		return all, nil
+	}
+	var (
+		mods    []Module
+		unknown []string
+	)
+	for _, t := range topics {
+		i := slices.IndexFunc(all, func(m Module) bool { return m.Topic == t })
+		if i < 0 {
+			unknown = append(unknown, t)
+			continue
+		}
+		mods = append(mods, all[i])
+	}
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("unknown experiment(s): %s", strings.Join(unknown, ", "))
+	}
+	return mods, nil
+}
@@ -0,0 +1,204 @@
+package runner
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"go-lab/pkg/measure"
+)
+
+// writeWorkspace creates a minimal lab workspace with the given experiment
+// topics and a shared pkg module, returning its root.
+func writeWorkspace(t *testing.T, topics ...string) string {
+	t.Helper()
+	root := t.TempDir()
+	use := []string{"./pkg"}
+	files := map[string]string{
+		"pkg/go.mod": "module go-lab/pkg\n\ngo 1.26.0\n",
+	}
+	for _, topic := range topics {
+		dir := "experiments/" + topic
+		use = append(use, "./"+dir)
+		files[dir+"/go.mod"] = "module go-lab/" + dir + "\n\ngo 1.26.0\n"
+		files[dir+"/x_test.go"] = `package x
+
+import "testing"
+
+func TestOK(t *testing.T) { t.Log("observed") }
+
+func BenchmarkNop(b *testing.B) {
+	for b.Loop() {
+	}
+}
+`
+	}
+	files["go.work"] = "go 1.26.0\n\nuse (\n\t" + strings.Join(use, "\n\t") + "\n)\n"
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestOpenAndSelect(t *testing.T) {
+	root := writeWorkspace(t, "alpha", "beta")
+	w, err := Open(context.Background(), filepath.Join(root, "experiments", "beta"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w.GoVersion != "1.26.0" {
+		t.Errorf("GoVersion = %q, want 1.26.0", w.GoVersion)
+	}
+	if len(w.Modules) != 3 {
+		t.Errorf("Modules = %+v, want 3 entries", w.Modules)
+	}
+
+	all, err := w.Select(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 2 || all[0].Topic != "alpha" || all[1].Topic != "beta" {
+		t.Errorf("Select(nil) = %+v, want [alpha beta]", all)
+	}
+
+	one, err := w.Select([]string{"beta"})
+	if err != nil || len(one) != 1 || one[0].Topic != "beta" {
+		t.Errorf("Select(beta) = %+v, %v", one, err)
+	}
+
+	if _, err := w.Select([]string{"alpha", "gamma", "delta"}); err == nil ||
+		!strings.Contains(err.Error(), "gamma, delta") {
+		t.Errorf("Select(unknown) error = %v, want both unknown topics", err)
+	}
+}
+
+func TestExecute(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs the go command")
+	}
+	root := writeWorkspace(t, "alpha")
+	ctx := context.Background()
+	w, err := Open(ctx, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := DefaultOptions()
+	opts.Benchtime = "1x"
+	run, err := w.Execute(ctx, w.Experiments(), opts, filepath.Join(root, RunsDir), io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !run.Passed() {
+		t.Fatalf("run failed: %+v", run.Results)
+	}
+
+	latest, err := LatestRun(filepath.Join(root, RunsDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadRun(latest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, ok := loaded.Result("alpha")
+	if !ok {
+		t.Fatalf("result for alpha missing: %+v", loaded.Results)
+	}
+	if len(res.Tests) != 1 || len(res.Benchmarks) != 1 {
+		t.Errorf("parsed %d tests, %d benchmarks; want 1, 1", len(res.Tests), len(res.Benchmarks))
+	}
+	raw, err := loaded.RawOutput(res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(raw), "observed") {
+		t.Errorf("raw output lacks t.Log line:\n%s", raw)
+	}
+}
+
+func TestExecuteNoise(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs the go command")
+	}
+	root := writeWorkspace(t, "alpha")
+	ctx := context.Background()
+	w, err := Open(ctx, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := DefaultOptions()
+	opts.Benchtime = "1x"
+	opts.Warmup = "1x"
+	opts.Count = 3
+	var before []int
+	if runtime.GOOS == "linux" {
+		if before, err = measure.Affinity(); err != nil {
+			t.Fatal(err)
+		}
+		opts.Pin = before[:1]
+	}
+	run, err := w.Execute(ctx, w.Experiments(), opts, filepath.Join(root, RunsDir), io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !run.Passed() {
+		t.Fatalf("run failed: %+v", run.Results)
+	}
+	res, _ := run.Result("alpha")
+	if len(res.Tests) != 3 || len(res.Benchmarks) != 3 {
+		t.Errorf("parsed %d tests, %d benchmarks; want 3, 3", len(res.Tests), len(res.Benchmarks))
+	}
+	if n := run.Noise; n == nil || len(n.Benchmarks) != 1 || n.Benchmarks[0].Samples != 3 {
+		t.Errorf("Noise = %+v, want Nop scored over 3 samples", n)
+	}
+	if before != nil {
+		after, err := measure.Affinity()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(after, before) {
+			t.Errorf("affinity after Execute = %v, want %v restored", after, before)
+		}
+	}
+}
+
+func TestReserveDir(t *testing.T) {
+	runsDir := filepath.Join(t.TempDir(), RunsDir)
+	start := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	var ids []string
+	for range 11 {
+		id, dir, err := reserveDir(runsDir, start)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if dir != filepath.Join(runsDir, id) {
+			t.Errorf("reserveDir dir = %s, want it named %s", dir, id)
+		}
+		ids = append(ids, id)
+	}
+	if ids[0] != "20261017T120000Z" || ids[1] != "20261017T120000Z-02" || ids[10] != "20261017T120000Z-11" {
+		t.Errorf("reserveDir IDs = %q", ids)
+	}
+	if !slices.IsSorted(ids) {
+		t.Errorf("IDs of same-second runs do not sort in creation order: %q", ids)
+	}
+	next, _, err := reserveDir(runsDir, start.Add(time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last := ids[len(ids)-1]; next <= last {
+		t.Errorf("ID %s of a later run sorts before %s", next, last)
+	}
+}
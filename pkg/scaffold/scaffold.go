@@ -0,0 +1,165 @@
+// Package scaffold creates experiment modules following the project
+// protocol of AGENT.md: the experiments/<topic> module requiring
+// go-lab/pkg, its go.work entry, the exp/<issue>-<topic> branch, skeleton
+// sources (a sink, b.Loop benchmarks, an allocation table split into hard
+// assertions and observations, a TestHypothesis) and an empty IMRaD spec
+// for lab issue.
+package scaffold
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"go/format"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"go-lab/pkg/imrad"
+)
+
+// topicRE matches kebab-case topics such as "struct-padding".
+var topicRE = regexp.MustCompile(`^[a-z][a-z0-9]*(-[a-z0-9]+)*$`)
+
+// Experiment describes a new experiment module.
+type Experiment struct {
+	Issue     int    // GitHub issue number
+	Topic     string // kebab-case directory name, e.g. "struct-padding"
+	GoVersion string // go directive of the module, that of go.work
+}
+
+// New validates issue and topic and returns the experiment whose go
+// directive is pinned to goVersion, the go directive of the workspace.
+func New(issue int, topic, goVersion string) (Experiment, error) {
+	switch {
+	case issue <= 0:
+		return Experiment{}, fmt.Errorf("invalid issue number %d", issue)
+	case !topicRE.MatchString(topic):
+		return Experiment{}, fmt.Errorf("invalid topic %q: use kebab-case, e.g. struct-padding", topic)
+	case goVersion == "":
+		return Experiment{}, errors.New("no go version to pin the module to")
+	}
+	return Experiment{Issue: issue, Topic: topic, GoVersion: goVersion}, nil
+}
+
+// Package returns the package name: the topic without hyphens.
+func (e Experiment) Package() string { return strings.ReplaceAll(e.Topic, "-", "") }
+
+// Module returns the module path, e.g. "go-lab/experiments/struct-padding".
+func (e Experiment) Module() string { return "go-lab/experiments/" + e.Topic }
+
+// Dir returns the workspace-relative, slash-separated module directory.
+func (e Experiment) Dir() string { return "experiments/" + e.Topic }
+
+// Branch returns the experiment branch, e.g. "exp/1-struct-padding".
+func (e Experiment) Branch() string { return fmt.Sprintf("exp/%d-%s", e.Issue, e.Topic) }
+
+// Files renders the files of the module keyed by file name: go.mod,
+// <topic>.go, <topic>_test.go and the IMRaD spec imrad.SpecFile.
+func (e Experiment) Files() (map[string][]byte, error) {
+	files := map[string][]byte{}
+	for name, t := range map[string]*template.Template{
+		"go.mod":             goModTmpl,
+		e.Topic + ".go":      sourceTmpl,
+		e.Topic + "_test.go": testTmpl,
+		imrad.SpecFile:       specTmpl,
+	} {
+		var buf bytes.Buffer
+		if err := t.Execute(&buf, e); err != nil {
+			return nil, fmt.Errorf("render %s: %w", name, err)
+		}
+		src := buf.Bytes()
+		if strings.HasSuffix(name, ".go") {
+			var err error
+			if src, err = format.Source(src); err != nil {
+				return nil, fmt.Errorf("format %s: %w", name, err)
+			}
+		}
+		files[name] = src
+	}
+	return files, nil
+}
+
+// Create creates the experiment in the workspace rooted at root, which
+// must be a git work tree: it writes Files into Dir, adds the module to
+// go.work and then creates and switches to Branch at HEAD. If any step
+// fails, the steps before it are undone, so a failed Create leaves neither
+// a partial module nor a new branch behind. The branch is created with git
+// plumbing only (update-ref and symbolic-ref), so the index and the work
+// tree, including uncommitted changes, are untouched.
+func (e Experiment) Create(ctx context.Context, root string) (err error) {
+	files, err := e.Files()
+	if err != nil {
+		return err
+	}
+	dir := filepath.Join(root, filepath.FromSlash(e.Dir()))
+	if _, err := os.Stat(dir); err == nil {
+		return fmt.Errorf("%s already exists", e.Dir())
+	}
+	ref := "refs/heads/" + e.Branch()
+	if _, err := git(ctx, root, "check-ref-format", ref); err != nil {
+		return fmt.Errorf("invalid branch name %s: %w", e.Branch(), err)
+	}
+	if _, err := git(ctx, root, "rev-parse", "--verify", "--quiet", ref); err == nil {
+		return fmt.Errorf("branch %s already exists", e.Branch())
+	}
+	head, err := git(ctx, root, "rev-parse", "--verify", "HEAD^{commit}")
+	if err != nil {
+		return fmt.Errorf("resolve HEAD: %w", err)
+	}
+
+	workFile := filepath.Join(root, "go.work")
+	work, err := os.ReadFile(workFile)
+	if err != nil {
+		return fmt.Errorf("read go.work: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			os.RemoveAll(dir)
+			os.WriteFile(workFile, work, 0o644)
+		}
+	}()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("create experiment: %w", err)
+	}
+	for name, src := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), src, 0o644); err != nil {
+			return fmt.Errorf("create experiment: %w", err)
+		}
+	}
+	cmd := exec.CommandContext(ctx, "go", "work", "use", "./"+e.Dir())
+	cmd.Dir = root
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("go work use: %w\n%s", err, out)
+	}
+
+	// The empty old value makes update-ref fail when the branch exists.
+	if _, err := git(ctx, root, "update-ref", "-m", "lab new", ref, head, ""); err != nil {
+		return fmt.Errorf("create branch %s: %w", e.Branch(), err)
+	}
+	if _, err := git(ctx, root, "symbolic-ref", "-m", "lab new: moving to "+e.Branch(), "HEAD", ref); err != nil {
+		git(ctx, root, "update-ref", "-d", ref, head)
+		return fmt.Errorf("switch to branch %s: %w", e.Branch(), err)
+	}
+	return nil
+}
+
+// git runs a git command in dir and returns its trimmed standard output.
+func git(ctx context.Context, dir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%w: %s", err, msg)
+		}
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
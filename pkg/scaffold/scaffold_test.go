@@ -0,0 +1,156 @@
+package scaffold
+
+import (
+	"context"
+	"go/parser"
+	"go/token"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go-lab/pkg/imrad"
+)
+
+func TestNew(t *testing.T) {
+	for _, tc := range []struct {
+		issue int
+		topic string
+		ok    bool
+	}{
+		{12, "struct-padding", true},
+		{3, "gc2-pacer", true},
+		{0, "struct-padding", false},
+		{12, "StructPadding", false},
+		{12, "struct_padding", false},
+		{12, "-padding", false},
+		{12, "2-padding", false},
+	} {
+		_, err := New(tc.issue, tc.topic, "1.26.0")
+		if (err == nil) != tc.ok {
+			t.Errorf("New(%d, %q) error = %v, want ok %v", tc.issue, tc.topic, err, tc.ok)
+		}
+	}
+	e, err := New(12, "struct-padding", "1.26.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Package() != "structpadding" || e.Module() != "go-lab/experiments/struct-padding" || e.Branch() != "exp/12-struct-padding" {
+		t.Errorf("package %q, module %q, branch %q", e.Package(), e.Module(), e.Branch())
+	}
+}
+
+func TestFiles(t *testing.T) {
+	e, err := New(7, "map-growth", "1.26.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	files, err := e.Files()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "module go-lab/experiments/map-growth\n\ngo 1.26.0\n\nrequire go-lab/pkg v0.0.0\n"
+	if got := string(files["go.mod"]); got != want {
+		t.Errorf("go.mod =\n%s\nwant\n%s", got, want)
+	}
+	for _, name := range []string{"map-growth.go", "map-growth_test.go"} {
+		f, err := parser.ParseFile(token.NewFileSet(), name, files[name], parser.ParseComments)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if f.Name.Name != "mapgrowth" {
+			t.Errorf("%s: package %s, want mapgrowth", name, f.Name.Name)
+		}
+	}
+	spec, err := imrad.Parse(imrad.SpecFile, string(files[imrad.SpecFile]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if spec.Issue != 7 || spec.Title != "map-growth" || len(spec.Code) != 0 {
+		t.Errorf("spec: issue %d, title %q, %d code blocks", spec.Issue, spec.Title, len(spec.Code))
+	}
+	if v, _ := spec.Method("Implementation"); v != "`experiments/map-growth`" {
+		t.Errorf("spec implementation = %q", v)
+	}
+	test := string(files["map-growth_test.go"])
+	for _, want := range []string{"var sink measure.Sink[int]", "for b.Loop() {", "measure.AssertAllocs(", "measure.ObserveAllocs(", "func TestHypothesis("} {
+		if !strings.Contains(test, want) {
+			t.Errorf("test skeleton lacks %q", want)
+		}
+	}
+}
+
+func TestCreate(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs git and the go command")
+	}
+	pkgDir, err := filepath.Abs("..")
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "go.work"), []byte("go 1.26.0\n\nuse "+pkgDir+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	run := func(name string, args ...string) string {
+		t.Helper()
+		cmd := exec.Command(name, args...)
+		cmd.Dir = root
+		cmd.Env = append(os.Environ(), "GOFLAGS=")
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("%s %s: %v\n%s", name, strings.Join(args, " "), err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	run("git", "init", "-q", "-b", "main")
+	run("git", "-c", "user.name=lab", "-c", "user.email=lab@example.com", "commit", "-q", "--allow-empty", "-m", "init")
+
+	e, err := New(5, "chan-cost", "1.26.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GOFLAGS", "")
+	if err := e.Create(context.Background(), root); err != nil {
+		t.Fatal(err)
+	}
+	if got := run("git", "symbolic-ref", "--short", "HEAD"); got != "exp/5-chan-cost" {
+		t.Errorf("HEAD = %s, want exp/5-chan-cost", got)
+	}
+	if work := run("go", "work", "edit", "-json"); !strings.Contains(work, `"DiskPath": "./experiments/chan-cost"`) {
+		t.Errorf("go.work does not use the experiment:\n%s", work)
+	}
+	run("go", "test", "-bench", ".", "-benchtime", "10x", "./experiments/chan-cost")
+
+	if err := e.Create(context.Background(), root); err == nil {
+		t.Error("second Create succeeded")
+	}
+
+	// A failing step leaves neither the module nor the branch behind.
+	run("git", "symbolic-ref", "HEAD", "refs/heads/main")
+	e, err = New(6, "broken", "1.26.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	work := filepath.Join(root, "go.work")
+	before, err := os.ReadFile(work)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(work, append(before, "bogus\n"...), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Create(context.Background(), root); err == nil {
+		t.Fatal("Create with a broken go.work succeeded")
+	}
+	if got := run("git", "symbolic-ref", "--short", "HEAD"); got != "main" {
+		t.Errorf("HEAD after failed Create = %s, want main", got)
+	}
+	if branches := run("git", "branch", "--list", e.Branch()); branches != "" {
+		t.Errorf("failed Create left branch %s", branches)
+	}
+	if _, err := os.Stat(filepath.Join(root, e.Dir())); !os.IsNotExist(err) {
+		t.Errorf("failed Create left %s: %v", e.Dir(), err)
+	}
+}
@@ -0,0 +1,165 @@
+package scaffold
+
+import "text/template"
+
+var goModTmpl = template.Must(template.New("go.mod").Parse(`module {{.Module}}
+
+go {{.GoVersion}}
+
+require go-lab/pkg v0.0.0
+`))
+
+var specTmpl = template.Must(template.New("spec").Parse(`# IMRaD spec of issue #{{.Issue}}; render it with go run ./cmd/lab issue {{.Topic}}
+# and, after lab run, fill in the results with lab issue -results {{.Topic}}.
+
+title = "{{.Topic}}"
+topic = ""  # memory, cpu, data-structure, algorithm or io
+issue = {{.Issue}}
+pattern_a = "PatternA"
+pattern_b = "PatternB"
+
+[introduction]
+context = """
+"""
+objective = """
+"""
+
+[hypothesis]
+text = """
+"""
+code = '''
+'''
+
+[[expected]]
+metric = "Allocs/op"
+a = "0"
+b = "0"
+diff = ""
+
+[methods]
+implementation = "` + "`{{.Dir}}`" + `"
+variables = ""
+measurement = "` + "`testing.B` / `runtime/metrics`" + `"
+
+[discussion]
+analysis = """
+"""
+conclusion = """
+"""
+`))
+
+var sourceTmpl = template.Must(template.New("source").Parse(`// Package {{.Package}} is the experiment of issue #{{.Issue}} ({{.Topic}}).
+// The hypothesis and the expected outcome are stated in the issue.
+package {{.Package}}
+
+// ---- Section 0: Baseline ----
+
+// Baseline returns its argument without doing any work.
+// Absolute zero point: 0 allocs guaranteed.
+func Baseline(n int) int {
+	return n
+}
+
+// ---- Section 1: Pattern A vs Pattern B ----
+
+// PatternA is the control group: <the mechanism whose outcome is known>.
+func PatternA(n int) int {
+	return n
+}
+
+// PatternB is the research question: <the mechanism under test>.
+func PatternB(n int) int {
+	return n
+}
+`))
+
+var testTmpl = template.Must(template.New("test").Parse(`package {{.Package}}
+
+import (
+	"testing"
+
+	"go-lab/pkg/hypothesis"
+	"go-lab/pkg/measure"
+)
+
+// sink prevents the compiler from eliminating benchmark results via dead-code elimination.
+var sink measure.Sink[int]
+
+// TestAllocations measures heap allocations per call using measure.AssertAllocs
+// and measure.ObserveAllocs (testing.AllocsPerRun).
+//
+// Assertion policy:
+//   - Hard assertion (measure.AssertAllocs): patterns where the compiler outcome
+//     is determined by the Go spec or well-established escape analysis rules.
+//   - Observation only (measure.ObserveAllocs): exploratory patterns whose
+//     allocation count is the primary research question. These must NOT be
+//     constrained by a prior hypothesis.
+func TestAllocations(t *testing.T) {
+
+	// ---- Hard assertions: controls ----
+
+	asserted := []struct {
+		name string
+		want float64
+		f    func()
+	}{
+		{"Baseline", 0, func() { sink.Set(Baseline(1)) }},
+		{"PatternA", 0, func() { sink.Set(PatternA(1)) }},
+	}
+	for _, tc := range asserted {
+		t.Run(tc.name, func(t *testing.T) {
+			measure.AssertAllocs(t, tc.name, tc.want, tc.f)
+		})
+	}
+
+	// ---- Observations: the research question ----
+
+	observed := []struct {
+		name string
+		f    func()
+	}{
+		{"PatternB", func() { sink.Set(PatternB(1)) }},
+	}
+	for _, tc := range observed {
+		t.Run(tc.name, func(t *testing.T) {
+			measure.ObserveAllocs(t, tc.name, tc.f)
+		})
+	}
+}
+
+// TestHypothesis declares the Expected Outcome table of issue #{{.Issue}}.
+// PatternA is a control and is asserted (hypothesis.Assert); PatternB is the
+// research question, so its prediction is in hypothesis.Observe mode: a
+// mismatch yields result:unexpected instead of a test failure.
+func TestHypothesis(t *testing.T) {
+	h := hypothesis.New("{{.Topic}}", "PatternA", "PatternB")
+	h.Predict("Allocs/op", "", hypothesis.Exactly(0), hypothesis.Exactly(0).Observe())
+
+	h.Record("Allocs/op",
+		measure.Allocs(func() { sink.Set(PatternA(1)) }),
+		measure.Allocs(func() { sink.Set(PatternB(1)) }))
+	h.Check(t)
+}
+
+// ---- Section 0: Baseline ----
+
+func BenchmarkBaseline(b *testing.B) {
+	for b.Loop() {
+		sink.Set(Baseline(1))
+	}
+}
+
+// ---- Section 1: Pattern A vs Pattern B ----
+
+func BenchmarkPatternA(b *testing.B) {
+	for b.Loop() {
+		sink.Set(PatternA(1))
+	}
+}
+
+func BenchmarkPatternB(b *testing.B) {
+	for b.Loop() {
+		sink.Set(PatternB(1))
+	}
+}
+`))
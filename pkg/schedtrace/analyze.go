@@ -0,0 +1,138 @@
+package schedtrace
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"go-lab/pkg/stats"
+)
+
+// LogCategory is the category of the trace.Log events that delimit the
+// analysed window; the goroutine logging them is the spawning goroutine.
+const LogCategory = "schedtrace"
+
+// Stats describes the scheduling of the goroutines spawned in a window.
+type Stats struct {
+	Spawned int             // goroutines created by the spawning goroutine
+	Latency []time.Duration // creation to first Running, per spawned goroutine that ran
+	SameP   int             // spawned goroutines that first ran on their creator's P
+
+	// Parks and unparks of the spawning and the spawned goroutines.
+	Parks       int
+	Unparks     int
+	ParkReasons map[string]int
+
+	// Wakeups of the spawning goroutine (e.g. a receiver woken by the
+	// sender) and how many of them next ran on the waker's P, the runnext
+	// handoff.
+	Wakeups      int
+	WakeupsSameP int
+}
+
+// Analyze computes Stats for the window between the "begin" and "end"
+// LogCategory log events.
+func Analyze(events []Event) (Stats, error) {
+	begin := slices.IndexFunc(events, func(e Event) bool {
+		return e.Kind == "Log" && e.Category == LogCategory && e.Message == "begin"
+	})
+	if begin < 0 {
+		return Stats{}, errors.New("schedtrace: no begin log event in trace")
+	}
+	parent := events[begin].G
+	end := slices.IndexFunc(events[begin:], func(e Event) bool {
+		return e.Kind == "Log" && e.Category == LogCategory && e.Message == "end" && e.G == parent
+	})
+	if end < 0 {
+		return Stats{}, errors.New("schedtrace: no end log event in trace")
+	}
+
+	type child struct {
+		created int64 // time
+		p       int64 // creator's P
+		ran     bool
+	}
+	var (
+		s        = Stats{ParkReasons: map[string]int{}}
+		children = map[int64]*child{}
+		woken    bool  // parent is Runnable after a wakeup
+		wakerP   int64 // P of the goroutine that woke parent
+	)
+	for _, e := range events[begin : begin+end+1] {
+		if e.Kind != "StateTransition" {
+			continue
+		}
+		c, isChild := children[e.GoID]
+		tracked := e.GoID == parent || isChild
+		switch {
+		case e.From == "NotExist" && e.G == parent:
+			s.Spawned++
+			children[e.GoID] = &child{created: e.Time, p: e.P}
+		case e.To == "Running" && isChild && !c.ran:
+			c.ran = true
+			s.Latency = append(s.Latency, time.Duration(e.Time-c.created))
+			if e.P == c.p {
+				s.SameP++
+			}
+		case e.To == "Running" && e.GoID == parent && woken:
+			if e.P == wakerP {
+				s.WakeupsSameP++
+			}
+			woken = false
+		case e.From == "Running" && e.To == "Waiting" && tracked:
+			s.Parks++
+			s.ParkReasons[e.Reason]++
+		case e.From == "Waiting" && e.To == "Runnable" && tracked:
+			s.Unparks++
+			if e.GoID == parent {
+				s.Wakeups++
+				woken, wakerP = true, e.P
+			}
+		}
+	}
+	return s, nil
+}
+
+// LatencySummary summarizes the creation-to-running latencies in ns.
+func (s Stats) LatencySummary() stats.Summary {
+	xs := make([]float64, len(s.Latency))
+	for i, d := range s.Latency {
+		xs[i] = float64(d)
+	}
+	return stats.Summarize(xs, stats.DefaultConfidence)
+}
+
+// String formats s on one line, e.g.
+//
+//	1000 spawned, start latency 1.2µs (same P 100%), 2000 parks (chan receive 1000, ...), 1000 unparks, 1000 wakeups (same P 100%)
+func (s Stats) String() string {
+	var reasons []string
+	for _, r := range slices.Sorted(maps.Keys(s.ParkReasons)) {
+		name := r
+		if name == "" {
+			name = "unknown"
+		}
+		reasons = append(reasons, fmt.Sprintf("%s %d", name, s.ParkReasons[r]))
+	}
+	lat := "-"
+	if len(s.Latency) > 0 {
+		lat = time.Duration(s.LatencySummary().Median).String()
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d spawned, start latency %s (same P %s), %d parks", s.Spawned, lat, percent(s.SameP, len(s.Latency)), s.Parks)
+	if len(reasons) > 0 {
+		fmt.Fprintf(&sb, " (%s)", strings.Join(reasons, ", "))
+	}
+	fmt.Fprintf(&sb, ", %d unparks, %d wakeups (same P %s)", s.Unparks, s.Wakeups, percent(s.WakeupsSameP, s.Wakeups))
+	return sb.String()
+}
+
+func percent(n, of int) string {
+	if of == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.0f%%", float64(n)/float64(of)*100)
+}
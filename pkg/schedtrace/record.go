@@ -0,0 +1,54 @@
+package schedtrace
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime/trace"
+	"strings"
+	"testing"
+)
+
+// Record runs f under runtime/trace, writing the trace to
+// <tb.TempDir()>/<name>.trace, and returns the analysis of the goroutines
+// f spawns. f must spawn from the calling goroutine. It skips tb when a
+// trace is already being recorded (go test -trace).
+func Record(tb testing.TB, name string, f func()) Stats {
+	tb.Helper()
+	if trace.IsEnabled() {
+		tb.Skip("schedtrace: a trace is already being recorded")
+	}
+	file := filepath.Join(tb.TempDir(), strings.ReplaceAll(name, "/", "_")+".trace")
+	out, err := os.Create(file)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	if err := trace.Start(out); err != nil {
+		out.Close()
+		tb.Fatal(err)
+	}
+	ctx := context.Background()
+	trace.Log(ctx, LogCategory, "begin")
+	f()
+	trace.Log(ctx, LogCategory, "end")
+	trace.Stop()
+	if err := out.Close(); err != nil {
+		tb.Fatal(err)
+	}
+
+	events, err := Decode(tb.Context(), file)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	s, err := Analyze(events)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return s
+}
+
+// Log writes s to tb.
+func (s Stats) Log(tb testing.TB, name string) {
+	tb.Helper()
+	tb.Logf("%s: %s", name, s)
+}
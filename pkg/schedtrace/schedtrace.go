@@ -0,0 +1,195 @@
+// Package schedtrace records runtime/trace execution traces and analyses
+// the scheduler behaviour of the goroutines a pattern spawns: how long a
+// new goroutine waits to run, how often goroutines park and unpark, and
+// whether they run on the P of the goroutine that created or woke them.
+//
+// Traces are decoded with "go tool trace -d=parsed", which prints the
+// events of the toolchain's own internal/trace reader, so the lab stays
+// free of dependencies and always understands the format of the toolchain
+// that wrote the trace. That dump is a debugging aid without a
+// compatibility promise: the -d=parsed mode and the event lines parsed
+// here are those of Go 1.23 and later. Output in any other shape is an
+// error rather than an empty or partial event list, so a format change in
+// a newer toolchain, or an older toolchain in a matrix run, fails loudly.
+package schedtrace
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// Event is a goroutine state transition or a user log event of a trace.
+// Other events (stacks, metrics, ranges, P transitions) are not kept.
+type Event struct {
+	Kind    string // "StateTransition" or "Log"
+	Time    int64  // nanoseconds on the trace clock
+	M, P, G int64  // thread, processor and goroutine the event occurred on; -1 for none
+
+	// State transitions.
+	GoID     int64  // goroutine whose state changed
+	From, To string // e.g. "Runnable", "Running", "Waiting"
+	Reason   string // e.g. "chan receive"
+
+	// Log events.
+	Category, Message string
+}
+
+// ErrFormat reports "go tool trace -d=parsed" output that does not have the
+// shape of the Go 1.23+ dump.
+var ErrFormat = errors.New("unrecognized go tool trace -d=parsed output (Go 1.23 or later is required)")
+
+// Parse reads the output of "go tool trace -d=parsed". It fails with
+// ErrFormat on event lines it does not recognize and on output without any
+// goroutine state transitions, which every trace contains.
+func Parse(r io.Reader) ([]Event, error) {
+	var events []Event
+	transitions := 0
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	for n := 1; sc.Scan(); n++ {
+		line := sc.Text()
+		if !strings.HasPrefix(line, "M=") {
+			continue // stacks, blank lines
+		}
+		e, ok, err := parseLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("schedtrace: line %d: %w", n, err)
+		}
+		if ok {
+			events = append(events, e)
+			if e.Kind == "StateTransition" {
+				transitions++
+			}
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("schedtrace: %w", err)
+	}
+	if transitions == 0 {
+		return nil, fmt.Errorf("schedtrace: no goroutine state transitions: %w", ErrFormat)
+	}
+	return events, nil
+}
+
+// parseLine parses one event line such as
+//
+//	M=29079 P=0 G=10 StateTransition Time=3420079180352 GoID=1 Waiting->Runnable Reason=""
+//	M=29722 P=0 G=1 Log Time=3473940902976 Task=0 Category="lab" Message="begin"
+func parseLine(line string) (Event, bool, error) {
+	toks, err := tokenize(line)
+	if err != nil {
+		return Event{}, false, err
+	}
+	if len(toks) < 5 || !strings.HasPrefix(toks[1], "P=") || !strings.HasPrefix(toks[2], "G=") ||
+		!strings.HasPrefix(toks[4], "Time=") {
+		return Event{}, false, fmt.Errorf("%w: %q", ErrFormat, line)
+	}
+	e := Event{Kind: toks[3]}
+	goroutine := false
+	if e.Kind != "StateTransition" && e.Kind != "Log" {
+		return Event{}, false, nil
+	}
+	for _, tok := range toks {
+		key, val, ok := strings.Cut(tok, "=")
+		if !ok {
+			if from, to, ok := strings.Cut(tok, "->"); ok {
+				e.From, e.To = from, to
+			}
+			continue
+		}
+		switch key {
+		case "M", "P", "G", "Time", "GoID":
+			n, err := strconv.ParseInt(val, 10, 64)
+			if err != nil {
+				return Event{}, false, fmt.Errorf("%s: %w", key, err)
+			}
+			switch key {
+			case "M":
+				e.M = n
+			case "P":
+				e.P = n
+			case "G":
+				e.G = n
+			case "Time":
+				e.Time = n
+			case "GoID":
+				e.GoID, goroutine = n, true
+			}
+		case "Reason":
+			e.Reason = val
+		case "Category":
+			e.Category = val
+		case "Message":
+			e.Message = val
+		}
+	}
+	if e.Kind == "StateTransition" && !goroutine {
+		return Event{}, false, nil // a P transition (ProcID=…)
+	}
+	if e.Kind == "StateTransition" && (e.From == "" || e.To == "") {
+		return Event{}, false, fmt.Errorf("%w: %q", ErrFormat, line)
+	}
+	return e, true, nil
+}
+
+// tokenize splits line at spaces outside double-quoted values and unquotes
+// the values, so that Reason="chan receive" is one token.
+func tokenize(line string) ([]string, error) {
+	var toks []string
+	for line != "" {
+		line = strings.TrimLeft(line, " ")
+		if line == "" {
+			break
+		}
+		i := strings.IndexAny(line, " \"")
+		if i < 0 {
+			toks = append(toks, line)
+			break
+		}
+		if line[i] == ' ' {
+			toks = append(toks, line[:i])
+			line = line[i:]
+			continue
+		}
+		// A quoted value: find its end with strconv.QuotedPrefix.
+		q, err := strconv.QuotedPrefix(line[i:])
+		if err != nil {
+			return nil, err
+		}
+		v, err := strconv.Unquote(q)
+		if err != nil {
+			return nil, err
+		}
+		toks = append(toks, line[:i]+v)
+		line = line[i+len(q):]
+	}
+	return toks, nil
+}
+
+// Decode decodes the trace file with the go command's trace reader.
+func Decode(ctx context.Context, file string) ([]Event, error) {
+	cmd := exec.CommandContext(ctx, "go", "tool", "trace", "-d=parsed", file)
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("schedtrace: %w", err)
+	}
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("schedtrace: go tool trace: %w", err)
+	}
+	events, perr := Parse(out)
+	if perr != nil {
+		io.Copy(io.Discard, out)
+	}
+	if err := cmd.Wait(); err != nil {
+		return nil, fmt.Errorf("schedtrace: go tool trace -d=parsed (Go 1.23 or later): %w\n%s", err, stderr.String())
+	}
+	return events, perr
+}
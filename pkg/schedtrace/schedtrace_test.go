@@ -0,0 +1,99 @@
+package schedtrace
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// parsed is "go tool trace -d=parsed" output of one SpawnUnbuffered call,
+// shortened.
+const parsed = `M=29079 P=0 G=1 StateTransition Time=1000 GoID=7 NotExist->Runnable Reason=""
+TransitionStack=
+	runtime.traceStartReadCPU.func1 @ 0x476c40
+		/usr/local/go/src/runtime/tracecpu.go:44
+
+M=29079 P=-1 G=-1 StateTransition Time=1100 ProcID=0 Undetermined->Running Reason=""
+M=29722 P=0 G=1 Log Time=2000 Task=0 Category="schedtrace" Message="begin"
+M=29079 P=0 G=1 StateTransition Time=2100 GoID=10 NotExist->Runnable Reason=""
+M=29079 P=0 G=1 StateTransition Time=2200 GoID=1 Running->Waiting Reason="chan receive"
+M=29079 P=0 G=-1 StateTransition Time=2600 GoID=10 Runnable->Running Reason=""
+M=29079 P=0 G=10 StateTransition Time=2700 GoID=1 Waiting->Runnable Reason=""
+M=29079 P=0 G=10 StateTransition Time=2800 GoID=10 Running->NotExist Reason=""
+M=29079 P=0 G=-1 StateTransition Time=2900 GoID=1 Runnable->Running Reason=""
+M=29722 P=0 G=1 Log Time=3000 Task=0 Category="schedtrace" Message="end"
+M=29079 P=0 G=1 StateTransition Time=3100 GoID=11 NotExist->Runnable Reason=""
+`
+
+func TestParse(t *testing.T) {
+	events, err := Parse(strings.NewReader(parsed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 10 {
+		t.Fatalf("parsed %d events, want 10 (P transition and stacks skipped)", len(events))
+	}
+	want := Event{Kind: "StateTransition", Time: 2200, M: 29079, P: 0, G: 1, GoID: 1, From: "Running", To: "Waiting", Reason: "chan receive"}
+	if events[3] != want {
+		t.Errorf("events[3] = %+v\nwant        %+v", events[3], want)
+	}
+	if e := events[1]; e.Kind != "Log" || e.Category != LogCategory || e.Message != "begin" || e.G != 1 {
+		t.Errorf("log event = %+v", e)
+	}
+}
+
+func TestParseUnrecognized(t *testing.T) {
+	for name, out := range map[string]string{
+		"empty":        "",
+		"old format":   "0 GoCreate p=0 g=1 off=7 g=7 stack=3\n",
+		"no Time":      "M=1 P=0 G=1 StateTransition GoID=7 NotExist->Runnable\n",
+		"no states":    "M=1 P=0 G=1 StateTransition Time=1000 GoID=7 Reason=\"\"\n",
+		"only P lines": "M=1 P=-1 G=-1 StateTransition Time=1100 ProcID=0 Undetermined->Running Reason=\"\"\n",
+	} {
+		if _, err := Parse(strings.NewReader(out)); !errors.Is(err, ErrFormat) {
+			t.Errorf("Parse(%s) error = %v, want ErrFormat", name, err)
+		}
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	events, err := Parse(strings.NewReader(parsed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := Analyze(events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Spawned != 1 || len(s.Latency) != 1 || s.Latency[0] != 500*time.Nanosecond || s.SameP != 1 ||
+		s.Parks != 1 || s.ParkReasons["chan receive"] != 1 || s.Unparks != 1 || s.Wakeups != 1 || s.WakeupsSameP != 1 {
+		t.Errorf("Analyze = %+v", s)
+	}
+	want := "1 spawned, start latency 500ns (same P 100%), 1 parks (chan receive 1), 1 unparks, 1 wakeups (same P 100%)"
+	if got := s.String(); got != want {
+		t.Errorf("String = %q\nwant       %q", got, want)
+	}
+	if _, err := Analyze(events[:1]); err == nil {
+		t.Error("Analyze without begin event succeeded")
+	}
+}
+
+func TestRecord(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs go tool trace")
+	}
+	s := Record(t, "waitgroup", func() {
+		for range 50 {
+			var wg sync.WaitGroup
+			wg.Add(1)
+			go wg.Done()
+			wg.Wait()
+		}
+	})
+	if s.Spawned != 50 || len(s.Latency) != 50 {
+		t.Errorf("Record: %d spawned, %d ran; want 50, 50", s.Spawned, len(s.Latency))
+	}
+	s.Log(t, "waitgroup")
+}
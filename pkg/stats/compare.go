@@ -0,0 +1,71 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+)
+
+// DefaultAlpha is the significance level used by benchstat.
+const DefaultAlpha = 0.05
+
+// DefaultConfidence is the confidence level of median intervals.
+const DefaultConfidence = 0.95
+
+// Comparison is the statistical comparison of Pattern A against Pattern B.
+type Comparison struct {
+	A, B Summary
+	// Delta is the relative change of the median from A to B (B/A - 1).
+	Delta float64
+	// P is the two-sided Mann-Whitney U p-value.
+	P float64
+	// Alpha is the significance level P was judged against.
+	Alpha float64
+}
+
+// Compare summarizes samples a and b and tests whether they differ.
+func Compare(a, b []float64, alpha float64) Comparison {
+	_, p := MannWhitneyU(a, b)
+	c := Comparison{
+		A:     Summarize(a, DefaultConfidence),
+		B:     Summarize(b, DefaultConfidence),
+		P:     p,
+		Alpha: alpha,
+	}
+	c.Delta = c.B.Median/c.A.Median - 1
+	return c
+}
+
+// Significant reports whether the difference is statistically significant.
+func (c Comparison) Significant() bool {
+	return c.P <= c.Alpha && !math.IsNaN(c.Delta)
+}
+
+// String formats the delta the way benchstat does: "-12.34%" for a
+// significant change, "~" when no significant difference was observed.
+func (c Comparison) String() string {
+	if !c.Significant() {
+		return "~"
+	}
+	return FormatDelta(c.Delta)
+}
+
+// FormatDelta formats a relative change as a signed percentage.
+func FormatDelta(d float64) string {
+	if math.IsNaN(d) || math.IsInf(d, 0) {
+		return "?"
+	}
+	return fmt.Sprintf("%+.2f%%", d*100)
+}
+
+// GeoMeanDelta returns the geometric mean of the ratios B/A over several
+// comparisons minus one, i.e. the typical relative change across a sweep.
+// Comparisons with a non-positive median are skipped.
+func GeoMeanDelta(cs []Comparison) float64 {
+	ratios := make([]float64, 0, len(cs))
+	for _, c := range cs {
+		if c.A.Median > 0 && c.B.Median > 0 {
+			ratios = append(ratios, c.B.Median/c.A.Median)
+		}
+	}
+	return GeoMean(ratios) - 1
+}
@@ -0,0 +1,56 @@
+package stats
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	unbuffered := []float64{410, 412, 415, 409, 411}
+	buffered := []float64{380, 382, 379, 381, 383}
+	c := Compare(unbuffered, buffered, DefaultAlpha)
+	if !c.Significant() {
+		t.Fatalf("p = %v, want significant", c.P)
+	}
+	if want := 381.0/411 - 1; !approx(c.Delta, want, 1e-12) {
+		t.Errorf("Delta = %v, want %v", c.Delta, want)
+	}
+	if got := c.String(); got != "-7.30%" {
+		t.Errorf("String() = %q, want -7.30%%", got)
+	}
+
+	noisy := Compare([]float64{100, 120, 90, 110, 105}, []float64{101, 95, 115, 108, 99}, DefaultAlpha)
+	if noisy.Significant() || noisy.String() != "~" {
+		t.Errorf("overlapping samples: p = %v, String() = %q, want ~", noisy.P, noisy.String())
+	}
+}
+
+func TestGeoMeanDelta(t *testing.T) {
+	cs := []Comparison{
+		{A: Summary{Median: 100}, B: Summary{Median: 50}},
+		{A: Summary{Median: 100}, B: Summary{Median: 200}},
+		{A: Summary{Median: 0}, B: Summary{Median: 1}}, // skipped
+	}
+	if got := GeoMeanDelta(cs); !approx(got, 0, 1e-12) {
+		t.Errorf("GeoMeanDelta = %v, want 0 (×0.5 and ×2 cancel)", got)
+	}
+	if got := FormatDelta(math.NaN()); got != "?" {
+		t.Errorf("FormatDelta(NaN) = %q", got)
+	}
+}
+
+func TestTable(t *testing.T) {
+	rows := []Row{
+		{Key: "N=2", Comparison: Compare([]float64{40, 41, 42, 43, 44}, []float64{20, 21, 22, 23, 24}, DefaultAlpha)},
+		{Key: "N=64", Comparison: Compare([]float64{100, 101, 102, 103, 104}, []float64{101, 100, 104, 102, 103}, DefaultAlpha)},
+	}
+	want := `| ns/op | ConcatPlus | ConcatBuilder | Diff | p |
+|:---|---:|---:|:---|:---|
+| N=2 | 42 ±5% | 22 ±9% | -47.62% | p=0.008 n=5+5 |
+| N=64 | 102 ±2% | 102 ±2% | ~ | p=1.000 n=5+5 |
+| geomean | | | -27.63% | |
+`
+	if got := Table("ConcatPlus", "ConcatBuilder", "ns/op", rows); got != want {
+		t.Errorf("Table() =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,136 @@
+package stats
+
+import (
+	"math"
+	"slices"
+)
+
+// exactLimit bounds the sample sizes for which the exact U distribution is
+// computed. Larger samples use the normal approximation.
+const exactLimit = 50
+
+// MannWhitneyU performs the two-sided Mann-Whitney U test on independent
+// samples a and b and returns the U statistic of a and the p-value.
+//
+// Without ties and for samples of at most 50 values the exact null
+// distribution is used; otherwise the normal approximation with tie and
+// continuity correction. Empty inputs yield p = 1.
+func MannWhitneyU(a, b []float64) (u, p float64) {
+	n1, n2 := len(a), len(b)
+	if n1 == 0 || n2 == 0 {
+		return 0, 1
+	}
+	ranks, tieTerm := rank(a, b)
+	var r1 float64
+	for _, r := range ranks[:n1] {
+		r1 += r
+	}
+	u = r1 - float64(n1*(n1+1))/2
+
+	if tieTerm == 0 && n1 <= exactLimit && n2 <= exactLimit {
+		return u, exactP(n1, n2, u)
+	}
+	return u, normalP(n1, n2, u, tieTerm)
+}
+
+// rank assigns mid-ranks (1-based) to the concatenation of a and b and
+// returns them in input order together with Σ(t³ - t) over tie groups.
+func rank(a, b []float64) ([]float64, float64) {
+	n := len(a) + len(b)
+	all := make([]float64, 0, n)
+	all = append(all, a...)
+	all = append(all, b...)
+	idx := make([]int, n)
+	for i := range idx {
+		idx[i] = i
+	}
+	slices.SortFunc(idx, func(i, j int) int {
+		switch {
+		case all[i] < all[j]:
+			return -1
+		case all[i] > all[j]:
+			return 1
+		default:
+			return 0
+		}
+	})
+	ranks := make([]float64, n)
+	var tieTerm float64
+	for i := 0; i < n; {
+		j := i + 1
+		for j < n && all[idx[j]] == all[idx[i]] {
+			j++
+		}
+		mid := float64(i+j+1) / 2 // average of 1-based ranks i+1..j
+		for k := i; k < j; k++ {
+			ranks[idx[k]] = mid
+		}
+		if t := float64(j - i); t > 1 {
+			tieTerm += t*t*t - t
+		}
+		i = j
+	}
+	return ranks, tieTerm
+}
+
+// exactP returns the two-sided p-value of u from the exact null
+// distribution of U for sample sizes n1 and n2.
+func exactP(n1, n2 int, u float64) float64 {
+	dist := uDistribution(n1, n2)
+	var total float64
+	for _, c := range dist {
+		total += c
+	}
+	k := int(math.Round(u))
+	var lower, upper float64
+	for i, c := range dist {
+		if i <= k {
+			lower += c
+		}
+		if i >= k {
+			upper += c
+		}
+	}
+	p := 2 * math.Min(lower, upper) / total
+	return math.Min(p, 1)
+}
+
+// uDistribution returns the number of arrangements yielding each value of U
+// (index 0..n1·n2) using the recurrence f(n, m, u) = f(n-1, m, u-m) + f(n, m-1, u).
+func uDistribution(n1, n2 int) []float64 {
+	// prev[m] holds f(n-1, m, ·) for m = 0..n2.
+	prev := make([][]float64, n2+1)
+	for m := range prev {
+		prev[m] = []float64{1} // f(0, m, 0) = 1
+	}
+	for n := 1; n <= n1; n++ {
+		cur := make([][]float64, n2+1)
+		cur[0] = []float64{1} // f(n, 0, 0) = 1
+		for m := 1; m <= n2; m++ {
+			d := make([]float64, n*m+1)
+			for u, c := range prev[m] {
+				d[u+m] += c
+			}
+			for u, c := range cur[m-1] {
+				d[u] += c
+			}
+			cur[m] = d
+		}
+		prev = cur
+	}
+	return prev[n2]
+}
+
+// normalP returns the two-sided p-value of u under the normal
+// approximation with tie and continuity correction.
+func normalP(n1, n2 int, u, tieTerm float64) float64 {
+	fn1, fn2 := float64(n1), float64(n2)
+	n := fn1 + fn2
+	mu := fn1 * fn2 / 2
+	sigma := math.Sqrt(fn1 * fn2 / 12 * ((n + 1) - tieTerm/(n*(n-1))))
+	if sigma == 0 {
+		return 1
+	}
+	z := math.Max(math.Abs(u-mu)-0.5, 0) / sigma
+	return math.Min(math.Erfc(z/math.Sqrt2), 1)
+}
@@ -0,0 +1,66 @@
+package stats
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMannWhitneyExact(t *testing.T) {
+	tests := []struct {
+		name  string
+		a, b  []float64
+		wantU float64
+		wantP float64
+	}{
+		// Complete separation with n=5 each: p = 2/C(10,5).
+		{"separated", []float64{1, 2, 3, 4, 5}, []float64{6, 7, 8, 9, 10}, 0, 2.0 / 252},
+		{"reversed", []float64{6, 7, 8, 9, 10}, []float64{1, 2, 3, 4, 5}, 25, 2.0 / 252},
+		// One inversion: P(U ≤ 1) = 2/252.
+		{"one swap", []float64{1, 2, 3, 4, 6}, []float64{5, 7, 8, 9, 10}, 1, 4.0 / 252},
+		// n=3 each can never reach p < 0.1.
+		{"small", []float64{1, 2, 3}, []float64{4, 5, 6}, 0, 0.1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, p := MannWhitneyU(tt.a, tt.b)
+			if u != tt.wantU || !approx(p, tt.wantP, 1e-12) {
+				t.Errorf("MannWhitneyU = (%v, %v), want (%v, %v)", u, p, tt.wantU, tt.wantP)
+			}
+		})
+	}
+}
+
+func TestMannWhitneyTies(t *testing.T) {
+	same := []float64{7, 7, 7, 7, 7}
+	if _, p := MannWhitneyU(same, same); p != 1 {
+		t.Errorf("identical samples: p = %v, want 1", p)
+	}
+
+	// Ties force the normal approximation.
+	a := []float64{1, 2, 2, 3, 3, 3, 4, 4}
+	b := []float64{5, 5, 6, 6, 7, 7, 8, 8}
+	u, p := MannWhitneyU(a, b)
+	if u != 0 {
+		t.Errorf("U = %v, want 0", u)
+	}
+	// σ² = 8·8/12 · (17 - Σ(t³-t)/(16·15)); Σ = 6+24+6+6·4 = 60.
+	sigma := math.Sqrt(64.0 / 12 * (17 - 60.0/240))
+	want := math.Erfc((32 - 0.5) / sigma / math.Sqrt2)
+	if !approx(p, want, 1e-12) {
+		t.Errorf("p = %v, want %v", p, want)
+	}
+}
+
+func TestUDistribution(t *testing.T) {
+	d := uDistribution(2, 3)
+	// Arrangements of 2 a's among 5 positions by U: 1 1 2 2 2 1 1.
+	want := []float64{1, 1, 2, 2, 2, 1, 1}
+	if len(d) != len(want) {
+		t.Fatalf("len = %d, want %d", len(d), len(want))
+	}
+	for i := range want {
+		if d[i] != want[i] {
+			t.Errorf("f(2,3,%d) = %v, want %v", i, d[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package stats
+
+import "math"
+
+// MAD returns the median absolute deviation of xs from their median, or
+// NaN when xs is empty.
+func MAD(xs []float64) float64 {
+	if len(xs) == 0 {
+		return math.NaN()
+	}
+	m := Median(xs)
+	dev := make([]float64, len(xs))
+	for i, x := range xs {
+		dev[i] = math.Abs(x - m)
+	}
+	return Median(dev)
+}
+
+// RobustCV returns the relative spread of xs: the MAD scaled to estimate
+// the standard deviation of normal samples (×1.4826), divided by the
+// median. Unlike StdDev/Mean a single outlier barely moves it. It is NaN
+// for fewer than two samples or a zero median.
+func RobustCV(xs []float64) float64 {
+	if len(xs) < 2 {
+		return math.NaN()
+	}
+	m := Median(xs)
+	if m == 0 {
+		return math.NaN()
+	}
+	return 1.4826 * MAD(xs) / math.Abs(m)
+}
+
+// OutlierFence is the Tukey fence factor: samples more than OutlierFence
+// interquartile ranges below the first or above the third quartile are
+// outliers.
+const OutlierFence = 1.5
+
+// Outliers returns the indices of the samples of xs outside the Tukey
+// fences. Fewer than four samples have no meaningful quartiles and yield
+// none.
+func Outliers(xs []float64) []int {
+	if len(xs) < 4 {
+		return nil
+	}
+	q1, q3 := Quantile(xs, 0.25), Quantile(xs, 0.75)
+	lo, hi := q1-OutlierFence*(q3-q1), q3+OutlierFence*(q3-q1)
+	var out []int
+	for i, x := range xs {
+		if x < lo || x > hi {
+			out = append(out, i)
+		}
+	}
+	return out
+}
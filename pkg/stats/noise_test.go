@@ -0,0 +1,35 @@
+package stats
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestRobustCV(t *testing.T) {
+	xs := []float64{100, 101, 99, 100, 102, 98, 100}
+	if got := MAD(xs); got != 1 {
+		t.Errorf("MAD = %v, want 1", got)
+	}
+	if got := RobustCV(xs); !approx(got, 0.014826, 1e-9) {
+		t.Errorf("RobustCV = %v, want 0.014826", got)
+	}
+	// One wild sample: the standard deviation explodes, the robust CV not.
+	wild := append(slices.Clone(xs), 1000)
+	if got := RobustCV(wild); got > 0.02 {
+		t.Errorf("RobustCV with an outlier = %v, want < 0.02", got)
+	}
+	if got := RobustCV([]float64{1}); !math.IsNaN(got) {
+		t.Errorf("RobustCV of one sample = %v, want NaN", got)
+	}
+}
+
+func TestOutliers(t *testing.T) {
+	xs := []float64{100, 101, 99, 100, 140, 98, 100, 60}
+	if got := Outliers(xs); !slices.Equal(got, []int{4, 7}) {
+		t.Errorf("Outliers = %v, want [4 7]", got)
+	}
+	if got := Outliers([]float64{1, 100, 1}); got != nil {
+		t.Errorf("Outliers of three samples = %v, want none", got)
+	}
+}
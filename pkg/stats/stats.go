@@ -0,0 +1,151 @@
+// Package stats implements the statistics needed to decide whether a
+// difference between two benchmark groups is real: medians with
+// distribution-free confidence intervals, the Mann-Whitney U test and
+// geometric-mean deltas. It is a zero-dependency equivalent of the parts
+// of benchstat used by the lab.
+package stats
+
+import (
+	"math"
+	"slices"
+)
+
+// Mean returns the arithmetic mean of xs, or NaN when xs is empty.
+func Mean(xs []float64) float64 {
+	if len(xs) == 0 {
+		return math.NaN()
+	}
+	var sum float64
+	for _, x := range xs {
+		sum += x
+	}
+	return sum / float64(len(xs))
+}
+
+// StdDev returns the sample standard deviation of xs, or NaN when
+// fewer than two samples are given.
+func StdDev(xs []float64) float64 {
+	if len(xs) < 2 {
+		return math.NaN()
+	}
+	m := Mean(xs)
+	var ss float64
+	for _, x := range xs {
+		ss += (x - m) * (x - m)
+	}
+	return math.Sqrt(ss / float64(len(xs)-1))
+}
+
+// Median returns the median of xs, or NaN when xs is empty.
+func Median(xs []float64) float64 {
+	return Quantile(xs, 0.5)
+}
+
+// Quantile returns the q-quantile of xs (0 ≤ q ≤ 1) using linear
+// interpolation between order statistics, or NaN when xs is empty.
+func Quantile(xs []float64, q float64) float64 {
+	if len(xs) == 0 {
+		return math.NaN()
+	}
+	s := sorted(xs)
+	pos := q * float64(len(s)-1)
+	lo := int(math.Floor(pos))
+	hi := int(math.Ceil(pos))
+	return s[lo] + (s[hi]-s[lo])*(pos-float64(lo))
+}
+
+// GeoMean returns the geometric mean of xs, which must all be positive.
+// It returns NaN when xs is empty or contains a non-positive value.
+func GeoMean(xs []float64) float64 {
+	if len(xs) == 0 {
+		return math.NaN()
+	}
+	var logs float64
+	for _, x := range xs {
+		if x <= 0 {
+			return math.NaN()
+		}
+		logs += math.Log(x)
+	}
+	return math.Exp(logs / float64(len(xs)))
+}
+
+// Summary describes one group of samples.
+type Summary struct {
+	N      int     `json:"n"`
+	Median float64 `json:"median"`
+	Min    float64 `json:"min"`
+	Max    float64 `json:"max"`
+	// Lo and Hi bound the confidence interval of the median.
+	Lo float64 `json:"lo"`
+	Hi float64 `json:"hi"`
+	// Confidence is the coverage actually achieved by [Lo, Hi]. With few
+	// samples it is lower than requested (benchstat reports the same).
+	Confidence float64 `json:"confidence"`
+}
+
+// Summarize computes the median of xs and its distribution-free
+// confidence interval at the requested confidence level (e.g. 0.95).
+func Summarize(xs []float64, confidence float64) Summary {
+	if len(xs) == 0 {
+		return Summary{Median: math.NaN(), Min: math.NaN(), Max: math.NaN(), Lo: math.NaN(), Hi: math.NaN()}
+	}
+	s := sorted(xs)
+	lo, hi, achieved := MedianCI(s, confidence)
+	return Summary{
+		N:          len(s),
+		Median:     Median(s),
+		Min:        s[0],
+		Max:        s[len(s)-1],
+		Lo:         lo,
+		Hi:         hi,
+		Confidence: achieved,
+	}
+}
+
+// MedianCI returns a distribution-free confidence interval for the median
+// of xs built from order statistics: [x(k), x(n-k+1)] where k is the largest
+// index whose binomial tail keeps the coverage at or above confidence.
+// When n is too small to reach confidence, the full range [min, max] is
+// returned together with its (lower) achieved coverage.
+func MedianCI(xs []float64, confidence float64) (lo, hi, achieved float64) {
+	n := len(xs)
+	if n == 0 {
+		return math.NaN(), math.NaN(), 0
+	}
+	s := sorted(xs)
+	alpha := 1 - confidence
+	// tail(k) = P(Binom(n, 1/2) < k); coverage of [x(k), x(n-k+1)] is 1 - 2·tail(k).
+	k := 1
+	for next := 2; next <= (n+1)/2; next++ {
+		if 2*binomCDF(next-1, n) > alpha {
+			break
+		}
+		k = next
+	}
+	achieved = 1 - 2*binomCDF(k-1, n)
+	return s[k-1], s[n-k], achieved
+}
+
+// binomCDF returns P(Binom(n, 1/2) ≤ k).
+func binomCDF(k, n int) float64 {
+	if k < 0 {
+		return 0
+	}
+	var sum float64
+	c := 1.0 // C(n, 0)
+	for i := 0; i <= k && i <= n; i++ {
+		sum += c
+		c = c * float64(n-i) / float64(i+1)
+	}
+	return sum / math.Pow(2, float64(n))
+}
+
+func sorted(xs []float64) []float64 {
+	if slices.IsSorted(xs) {
+		return xs
+	}
+	s := slices.Clone(xs)
+	slices.Sort(s)
+	return s
+}
@@ -0,0 +1,71 @@
+package stats
+
+import (
+	"math"
+	"testing"
+)
+
+func approx(a, b, eps float64) bool { return math.Abs(a-b) <= eps }
+
+func TestBasics(t *testing.T) {
+	xs := []float64{4, 1, 3, 2, 5}
+	if got := Mean(xs); got != 3 {
+		t.Errorf("Mean = %v, want 3", got)
+	}
+	if got := Median(xs); got != 3 {
+		t.Errorf("Median = %v, want 3", got)
+	}
+	if got := Median([]float64{1, 2, 3, 4}); got != 2.5 {
+		t.Errorf("Median(even) = %v, want 2.5", got)
+	}
+	if got := StdDev(xs); !approx(got, math.Sqrt(2.5), 1e-12) {
+		t.Errorf("StdDev = %v, want √2.5", got)
+	}
+	if got := GeoMean([]float64{1, 4, 16}); !approx(got, 4, 1e-12) {
+		t.Errorf("GeoMean = %v, want 4", got)
+	}
+	if got := GeoMean([]float64{1, 0}); !math.IsNaN(got) {
+		t.Errorf("GeoMean with zero = %v, want NaN", got)
+	}
+	if xs[0] != 4 {
+		t.Error("Median must not reorder its input")
+	}
+}
+
+func TestMedianCI(t *testing.T) {
+	tests := []struct {
+		n            int
+		wantLo       float64 // 1-based order statistic index
+		wantCoverage float64
+	}{
+		// n=5: the full range is the best achievable, 1 - 2/32.
+		{5, 1, 0.9375},
+		// n=10: [x(2), x(9)], coverage 1 - 2·11/1024.
+		{10, 2, 1 - 2*11.0/1024},
+		// n=20: [x(6), x(15)], coverage 1 - 2·P(X ≤ 5).
+		{20, 6, 0.9586105346679688},
+	}
+	for _, tt := range tests {
+		xs := make([]float64, tt.n)
+		for i := range xs {
+			xs[i] = float64(i + 1)
+		}
+		lo, hi, cov := MedianCI(xs, 0.95)
+		if lo != tt.wantLo || hi != float64(tt.n)-tt.wantLo+1 {
+			t.Errorf("n=%d: CI = [%v, %v], want [x(%v), x(%v)]", tt.n, lo, hi, tt.wantLo, float64(tt.n)-tt.wantLo+1)
+		}
+		if !approx(cov, tt.wantCoverage, 1e-12) {
+			t.Errorf("n=%d: coverage = %v, want %v", tt.n, cov, tt.wantCoverage)
+		}
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	s := Summarize([]float64{105, 100, 103, 101, 102}, DefaultConfidence)
+	if s.N != 5 || s.Median != 102 || s.Min != 100 || s.Max != 105 {
+		t.Errorf("Summarize = %+v", s)
+	}
+	if s.Lo != 100 || s.Hi != 105 {
+		t.Errorf("CI = [%v, %v], want [100, 105]", s.Lo, s.Hi)
+	}
+}
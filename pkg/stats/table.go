@@ -0,0 +1,54 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Row is one line of a comparison table, keyed by the benchmark axes that
+// A and B share (e.g. "N=64").
+type Row struct {
+	Key string
+	Comparison
+}
+
+// Table renders rows as a markdown table in the Summary Table layout of the
+// pull request template, followed by a geomean row when there is more than
+// one row. Medians carry the half-width of their confidence interval as
+// "±x%"; the p column gives the Mann-Whitney U p-value and sample sizes.
+func Table(nameA, nameB, unit string, rows []Row) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "| %s | %s | %s | Diff | p |\n", unit, nameA, nameB)
+	sb.WriteString("|:---|---:|---:|:---|:---|\n")
+	cs := make([]Comparison, 0, len(rows))
+	for _, r := range rows {
+		key := r.Key
+		if key == "" {
+			key = "-"
+		}
+		fmt.Fprintf(&sb, "| %s | %s | %s | %s | p=%.3f n=%d+%d |\n",
+			key, FormatSummary(r.A), FormatSummary(r.B), r.Comparison, r.P, r.A.N, r.B.N)
+		cs = append(cs, r.Comparison)
+	}
+	if len(rows) > 1 {
+		fmt.Fprintf(&sb, "| geomean | | | %s | |\n", FormatDelta(GeoMeanDelta(cs)))
+	}
+	return sb.String()
+}
+
+// FormatSummary formats the median of s with four significant digits (whole
+// numbers from 1000 up) and the relative half-width of its confidence
+// interval, e.g. "411 ±1%".
+func FormatSummary(s Summary) string {
+	m := strconv.FormatFloat(s.Median, 'g', 4, 64)
+	if math.Abs(s.Median) >= 1000 {
+		m = strconv.FormatFloat(s.Median, 'f', 0, 64)
+	}
+	if s.N < 2 || s.Median == 0 || math.IsNaN(s.Median) {
+		return m
+	}
+	half := math.Max(s.Hi-s.Median, s.Median-s.Lo) / math.Abs(s.Median) * 100
+	return fmt.Sprintf("%s ±%.0f%%", m, half)
+}
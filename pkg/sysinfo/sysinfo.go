@@ -0,0 +1,135 @@
+// Package sysinfo collects the host properties that a benchmark result
+// depends on: CPU model, logical CPU count, kernel release and the CPU
+// frequency settings that make benchmarks noisy.
+package sysinfo
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"runtime"
+	"slices"
+	"strings"
+)
+
+// Unknown is reported for properties that cannot be read on this host.
+const Unknown = "unknown"
+
+// Info describes the host a run was measured on.
+type Info struct {
+	CPU    string `json:"cpu"`     // model name, e.g. "Intel(R) Xeon(R) Processor"
+	NumCPU int    `json:"num_cpu"` // logical CPUs usable by the process
+	Kernel string `json:"kernel"`  // kernel release, e.g. "6.8.0-45-generic"
+
+	// Governors lists the distinct cpufreq scaling governors of the CPUs,
+	// e.g. ["powersave"]; empty when the host exposes no cpufreq.
+	Governors []string `json:"governors,omitempty"`
+	// Turbo is "on" or "off" for the frequency boost (intel_pstate turbo
+	// or cpufreq boost), empty when unknown.
+	Turbo string `json:"turbo,omitempty"`
+}
+
+// Collect reads the host properties. Properties that cannot be read are
+// reported as Unknown rather than as an error, so that a run on an exotic
+// host still produces a report.
+func Collect() Info {
+	info := Info{
+		CPU:    cpuModel(),
+		NumCPU: runtime.NumCPU(),
+		Kernel: kernelRelease(),
+	}
+	info.Governors, info.Turbo = frequency(os.DirFS(CPUDir))
+	return info
+}
+
+// CPUDir is the sysfs directory describing the CPUs.
+const CPUDir = "/sys/devices/system/cpu"
+
+// frequency reads the scaling governors and the boost state from cpu, a
+// file system rooted at CPUDir.
+func frequency(cpu fs.FS) (governors []string, turbo string) {
+	files, _ := fs.Glob(cpu, "cpu[0-9]*/cpufreq/scaling_governor")
+	for _, f := range files {
+		if b, err := fs.ReadFile(cpu, f); err == nil {
+			if g := strings.TrimSpace(string(b)); g != "" && !slices.Contains(governors, g) {
+				governors = append(governors, g)
+			}
+		}
+	}
+	slices.Sort(governors)
+	if b, err := fs.ReadFile(cpu, "intel_pstate/no_turbo"); err == nil {
+		turbo = map[string]string{"0": "on", "1": "off"}[strings.TrimSpace(string(b))]
+	} else if b, err := fs.ReadFile(cpu, "cpufreq/boost"); err == nil {
+		turbo = map[string]string{"1": "on", "0": "off"}[strings.TrimSpace(string(b))]
+	}
+	return governors, turbo
+}
+
+// Warnings lists the frequency settings of i that let the clock speed
+// vary during a run: a governor other than "performance" and an enabled
+// turbo boost. Hosts without cpufreq (e.g. most VMs) get none.
+func (i Info) Warnings() []string {
+	var w []string
+	for _, g := range i.Governors {
+		if g != "performance" {
+			w = append(w, fmt.Sprintf("cpufreq governor %q scales the clock; use \"performance\"", g))
+		}
+	}
+	if i.Turbo == "on" {
+		w = append(w, "turbo boost is on; clock speed depends on temperature and load")
+	}
+	return w
+}
+
+// Fingerprint identifies the CPU configuration: results measured on hosts
+// with different fingerprints are not directly comparable.
+func (i Info) Fingerprint() string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%d", i.CPU, i.NumCPU)))
+	return hex.EncodeToString(sum[:6])
+}
+
+func cpuModel() string {
+	b, err := os.ReadFile("/proc/cpuinfo")
+	if err != nil {
+		return Unknown
+	}
+	if m := ParseCPUInfo(b); m != "" {
+		return m
+	}
+	return Unknown
+}
+
+// ParseCPUInfo returns the CPU model from /proc/cpuinfo contents. It reads
+// "model name" (x86) and falls back to "Hardware" or "Model" (arm).
+func ParseCPUInfo(b []byte) string {
+	fields := map[string]string{}
+	sc := bufio.NewScanner(bytes.NewReader(b))
+	for sc.Scan() {
+		k, v, ok := strings.Cut(sc.Text(), ":")
+		if !ok {
+			continue
+		}
+		k = strings.TrimSpace(k)
+		if _, seen := fields[k]; !seen {
+			fields[k] = strings.TrimSpace(v)
+		}
+	}
+	for _, k := range []string{"model name", "Hardware", "Model"} {
+		if v := fields[k]; v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func kernelRelease() string {
+	b, err := os.ReadFile("/proc/sys/kernel/osrelease")
+	if err != nil {
+		return Unknown
+	}
+	return strings.TrimSpace(string(b))
+}
@@ -0,0 +1,69 @@
+package sysinfo
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestParseCPUInfo(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"x86", "processor\t: 0\nvendor_id\t: GenuineIntel\nmodel name\t: Intel(R) Xeon(R) Processor\n\nprocessor\t: 1\nmodel name\t: other\n", "Intel(R) Xeon(R) Processor"},
+		{"arm", "processor\t: 0\nBogoMIPS\t: 108.00\n\nHardware\t: BCM2835\nModel\t\t: Raspberry Pi 4\n", "BCM2835"},
+		{"empty", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseCPUInfo([]byte(tt.in)); got != tt.want {
+				t.Errorf("ParseCPUInfo = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCollect(t *testing.T) {
+	info := Collect()
+	if info.NumCPU < 1 || info.CPU == "" || info.Kernel == "" {
+		t.Errorf("Collect() = %+v, want every field set", info)
+	}
+	t.Logf("%+v", info)
+}
+
+func TestFingerprint(t *testing.T) {
+	a := Info{CPU: "Intel(R) Xeon(R) Processor", NumCPU: 8}
+	b := Info{CPU: "Intel(R) Xeon(R) Processor", NumCPU: 4}
+	if a.Fingerprint() == b.Fingerprint() || len(a.Fingerprint()) != 12 {
+		t.Errorf("Fingerprint() = %q, %q; want distinct 12-digit hashes", a.Fingerprint(), b.Fingerprint())
+	}
+	if a.Fingerprint() != (Info{CPU: a.CPU, NumCPU: 8, Kernel: "6.8"}).Fingerprint() {
+		t.Error("Fingerprint depends on the kernel")
+	}
+}
+
+func TestFrequency(t *testing.T) {
+	cpu := fstest.MapFS{
+		"cpu0/cpufreq/scaling_governor": {Data: []byte("powersave\n")},
+		"cpu1/cpufreq/scaling_governor": {Data: []byte("powersave\n")},
+		"cpu2/cpufreq/scaling_governor": {Data: []byte("performance\n")},
+		"cpuidle/current_driver":        {Data: []byte("intel_idle\n")},
+		"intel_pstate/no_turbo":         {Data: []byte("0\n")},
+	}
+	info := Info{}
+	info.Governors, info.Turbo = frequency(cpu)
+	if !slices.Equal(info.Governors, []string{"performance", "powersave"}) || info.Turbo != "on" {
+		t.Errorf("frequency = %q, %q; want [performance powersave], on", info.Governors, info.Turbo)
+	}
+	if w := info.Warnings(); len(w) != 2 || !strings.Contains(w[0], "powersave") || !strings.Contains(w[1], "turbo") {
+		t.Errorf("Warnings() = %q, want powersave and turbo warnings", w)
+	}
+
+	info.Governors, info.Turbo = frequency(fstest.MapFS{"cpufreq/boost": {Data: []byte("0\n")}})
+	if info.Governors != nil || info.Turbo != "off" || info.Warnings() != nil {
+		t.Errorf("boost off, no governors: %+v, warnings %q", info, info.Warnings())
+	}
+}